@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/scraper"
+)
+
+const dedupeUsage = `usage: server dedupe [--dry-run]
+
+Canonicalizes every stored article URL and merges articles that are the same
+page under different URLs (tracking parameters, www., http, AMP variants).`
+
+// runDedupe implements the `dedupe` subcommand
+func runDedupe(repo database.Repository, args []string) error {
+    dryRun := false
+    for _, arg := range args {
+        switch arg {
+        case "--dry-run", "-dry-run":
+            dryRun = true
+        default:
+            return fmt.Errorf("unknown argument %q\n%s", arg, dedupeUsage)
+        }
+    }
+
+    report, err := scraper.MergeDuplicateURLs(context.Background(), repo, dryRun)
+    if err != nil {
+        return err
+    }
+
+    verb := "merged"
+    if dryRun {
+        verb = "would merge"
+    }
+    fmt.Printf("scanned %d articles: %d moved to their canonical URL, %s %d duplicates\n",
+        report.Scanned, report.Renamed, verb, report.Merged)
+    return nil
+}
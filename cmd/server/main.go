@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"io/fs"
 	// "fmt"
 	"log"
 	"os"
@@ -14,10 +17,16 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"gopkg.in/yaml.v3"
 
+	"news-scraper/internal/circuit"
 	"news-scraper/internal/database"
 	"news-scraper/internal/handlers"
+	"news-scraper/internal/proxy"
+	"news-scraper/internal/ratelimit"
+	"news-scraper/internal/retention"
 	"news-scraper/internal/scheduler"
 	"news-scraper/internal/scraper"
+	"news-scraper/internal/stories"
+	"news-scraper/migrations"
 )
 
 type Config struct {
@@ -26,6 +35,9 @@ type Config struct {
         Host string `yaml:"host"`
     } `yaml:"server"`
     Database struct {
+        Driver   string `yaml:"driver"`
+        Path     string `yaml:"path"`
+        SSLMode  string `yaml:"sslmode"`
         Host     string `yaml:"host"`
         Port     int    `yaml:"port"`
         User     string `yaml:"user"`
@@ -38,47 +50,166 @@ type Config struct {
         RateLimit int    `yaml:"rate_limit"`
         UserAgent string `yaml:"user_agent"`
         Schedule  string `yaml:"schedule"`
+        RobotsAgent    string `yaml:"robots_agent"`
+        RobotsCacheTTL string `yaml:"robots_cache_ttl"`
+        RateLimits     map[string]ratelimit.Rule `yaml:"rate_limits"`
+        MaxRetries       int    `yaml:"max_retries"`
+        RetryBackoff     string `yaml:"retry_backoff"`
+        BreakerThreshold int    `yaml:"breaker_threshold"`
+        BreakerCooldown  string `yaml:"breaker_cooldown"`
+        SourceTimeout    string `yaml:"source_timeout"`
+        ShutdownTimeout  string `yaml:"shutdown_timeout"`
     } `yaml:"scraper"`
+    Queue struct {
+        Workers      int    `yaml:"workers"`
+        PollInterval string `yaml:"poll_interval"`
+        Lease        string `yaml:"lease"`
+        MaxAttempts  int    `yaml:"max_attempts"`
+    } `yaml:"queue"`
+    Proxies struct {
+        CheckURL      string                      `yaml:"check_url"`
+        CheckInterval string                      `yaml:"check_interval"`
+        MaxFailures   int                         `yaml:"max_failures"`
+        Pools         map[string]proxy.PoolConfig `yaml:"pools"`
+    } `yaml:"proxies"`
+    Retention struct {
+        Schedule   string                    `yaml:"schedule"`
+        DryRun     bool                      `yaml:"dry_run"`
+        Default    retention.Rule            `yaml:"default"`
+        Sources    map[string]retention.Rule `yaml:"sources"`
+        Categories map[string]retention.Rule `yaml:"categories"`
+        Archive    retention.ArchiveConfig   `yaml:"archive"`
+    } `yaml:"retention"`
 }
 
-func loadConfig() (*Config, error) {
+// defaultConfig holds the values config.yaml.example ships with
+// Keys missing from config.yaml keep these.
+func defaultConfig() *Config {
+    var cfg Config
+    cfg.Server.Port = "3000"
+    cfg.Server.Host = "0.0.0.0"
+    cfg.Scraper.Workers = 5
+    cfg.Scraper.Timeout = "30s"
+    cfg.Scraper.RateLimit = 10
+    cfg.Scraper.UserAgent = "NewsBot/1.0"
+    cfg.Scraper.Schedule = "0 */6 * * *"
+    cfg.Scraper.RobotsCacheTTL = "24h"
+    cfg.Scraper.MaxRetries = 3
+    cfg.Scraper.RetryBackoff = "1s"
+    cfg.Scraper.BreakerThreshold = 5
+    cfg.Scraper.BreakerCooldown = "30m"
+    cfg.Scraper.SourceTimeout = "10m"
+    cfg.Scraper.ShutdownTimeout = "30s"
+    cfg.Queue.Workers = 3
+    cfg.Queue.PollInterval = "5s"
+    cfg.Queue.Lease = "2m"
+    cfg.Queue.MaxAttempts = 5
+    cfg.Proxies.CheckInterval = "5m"
+    cfg.Proxies.MaxFailures = 3
+    cfg.Retention.Schedule = "0 */2 * * *"
+    return &cfg
+}
+
+// loadConfig reads config.yaml over the defaults
+// Demo mode needs no database settings, so there it may be missing.
+func loadConfig(demo bool) (*Config, error) {
+    cfg := defaultConfig()
+
     data, err := os.ReadFile("config.yaml")
+    if errors.Is(err, fs.ErrNotExist) && demo {
+        return cfg, nil
+    }
     if err != nil {
         return nil, err
     }
 
-    var cfg Config
-    if err := yaml.Unmarshal(data, &cfg); err != nil {
+    if err := yaml.Unmarshal(data, cfg); err != nil {
         return nil, err
     }
 
-    return &cfg, nil
+    return cfg, nil
 }
 
 func main() {
+    // --demo runs on an in-memory database seeded with fixtures,
+    // so the UI can be worked on without a database server
+    demo := flag.Bool("demo", false, "use an in-memory database seeded with sample data")
+    flag.Parse()
+    command := flag.Arg(0)
+
     // Load configuration
-    cfg, err := loadConfig()
+    cfg, err := loadConfig(*demo)
     if err != nil {
         log.Fatal("Failed to load config:", err)
     }
 
-    // Connect to database
-    db, err := database.Connect(database.Config{
-        Host:     cfg.Database.Host,
-        Port:     cfg.Database.Port,
-        User:     cfg.Database.User,
-        Password: cfg.Database.Password,
-        Name:     cfg.Database.Name,
-    })
-    if err != nil {
-        log.Fatal("Failed to connect to database:", err)
+    var repo database.Repository
+    if *demo {
+        if command == "migrate" {
+            log.Fatal("migrate is not available in demo mode")
+        }
+        repo = database.NewMemoryRepository()
+        log.Println("Demo mode: using in-memory database")
+    } else {
+        // Connect to database
+        db, err := database.Connect(database.Config{
+            Driver:   cfg.Database.Driver,
+            Path:     cfg.Database.Path,
+            SSLMode:  cfg.Database.SSLMode,
+            Host:     cfg.Database.Host,
+            Port:     cfg.Database.Port,
+            User:     cfg.Database.User,
+            Password: cfg.Database.Password,
+            Name:     cfg.Database.Name,
+        })
+        if err != nil {
+            log.Fatal("Failed to connect to database:", err)
+        }
+        defer db.Close()
+
+        log.Println("Database connected successfully")
+
+        migrator, err := database.NewMigrator(db, migrations.Files, cfg.Database.Driver)
+        if err != nil {
+            log.Fatal("Failed to load migrations:", err)
+        }
+
+        // `server migrate ...` manages the schema and exits
+        if command == "migrate" {
+            if err := runMigrate(migrator, flag.Args()[1:]); err != nil {
+                log.Fatal("Migration failed: ", err)
+            }
+            return
+        }
+
+        // Refuse to serve against a schema the code doesn't match
+        if err := migrator.CheckCurrent(context.Background()); err != nil {
+            log.Fatalf("%v; run `server migrate up` first", err)
+        }
+
+        // Initialize repository
+        repo, err = database.NewRepository(db, cfg.Database.Driver)
+        if err != nil {
+            log.Fatal("Failed to initialize repository:", err)
+        }
     }
-    defer db.Close()
 
-    log.Println("Database connected successfully")
+    // `server dedupe` merges articles whose URLs canonicalize alike and exits
+    if command == "dedupe" {
+        if err := runDedupe(repo, flag.Args()[1:]); err != nil {
+            log.Fatal("Dedupe failed: ", err)
+        }
+        return
+    }
 
-    // Initialize repository
-    repo := database.NewRepository(db)
+    if *demo {
+        if err := database.SeedDemo(context.Background(), repo); err != nil {
+            log.Fatal("Failed to seed demo data:", err)
+        }
+        if _, err := stories.Assign(context.Background(), repo); err != nil {
+            log.Fatal("Failed to group demo stories:", err)
+        }
+    }
 
     // Parse timeout
     timeout, err := time.ParseDuration(cfg.Scraper.Timeout)
@@ -86,17 +217,84 @@ func main() {
         timeout = 30 * time.Second
     }
 
+    robotsCacheTTL, err := time.ParseDuration(cfg.Scraper.RobotsCacheTTL)
+    if err != nil {
+        robotsCacheTTL = 24 * time.Hour
+    }
+    // Unset or invalid durations fall back to the scraper's defaults
+    retryBackoff, _ := time.ParseDuration(cfg.Scraper.RetryBackoff)
+    breakerCooldown, _ := time.ParseDuration(cfg.Scraper.BreakerCooldown)
+    sourceTimeout, _ := time.ParseDuration(cfg.Scraper.SourceTimeout)
+    shutdownTimeout, err := time.ParseDuration(cfg.Scraper.ShutdownTimeout)
+    if err != nil {
+        shutdownTimeout = 30 * time.Second
+    }
+
+    // Proxy pools sources can be assigned to with sources.proxy_pool
+    checkInterval, _ := time.ParseDuration(cfg.Proxies.CheckInterval)
+    proxies, err := proxy.New(proxy.Config{
+        Pools:         cfg.Proxies.Pools,
+        MaxFailures:   cfg.Proxies.MaxFailures,
+        CheckURL:      cfg.Proxies.CheckURL,
+        CheckInterval: checkInterval,
+    })
+    if err != nil {
+        log.Fatal("Invalid proxy config:", err)
+    }
+    proxies.Start(context.Background())
+    defer proxies.Stop()
+
     // Initialize Colly-based scraper
     scraperInstance := scraper.NewScraper(repo, scraper.Config{
         Workers:   cfg.Scraper.Workers,
         Timeout:   timeout,
         RateLimit: cfg.Scraper.RateLimit,
         UserAgent: cfg.Scraper.UserAgent,
+        MaxJobAttempts: cfg.Queue.MaxAttempts,
+        RobotsAgent:    cfg.Scraper.RobotsAgent,
+        RobotsCacheTTL: robotsCacheTTL,
+        RateLimits:     cfg.Scraper.RateLimits,
+        MaxRetries:     cfg.Scraper.MaxRetries,
+        RetryBackoff:   retryBackoff,
+        Breaker: circuit.Config{
+            Threshold: cfg.Scraper.BreakerThreshold,
+            Cooldown:  breakerCooldown,
+        },
+        Proxies:        proxies,
+        SourceTimeout:  sourceTimeout,
     })
 
+    // Start the article worker pool that drains the job queue
+    pollInterval, err := time.ParseDuration(cfg.Queue.PollInterval)
+    if err != nil {
+        pollInterval = 5 * time.Second
+    }
+    lease, err := time.ParseDuration(cfg.Queue.Lease)
+    if err != nil {
+        lease = 2 * time.Minute
+    }
+
+    articleWorkers := scraper.NewArticleWorkers(scraperInstance, repo, scraper.WorkerConfig{
+        Workers:      cfg.Queue.Workers,
+        PollInterval: pollInterval,
+        Lease:        lease,
+    })
+    articleWorkers.Start(context.Background())
+    defer articleWorkers.Stop()
+
+    // Initialize retention policy
+    retentionService, err := retention.NewService(repo, retention.Policy{
+        Default:    cfg.Retention.Default,
+        Sources:    cfg.Retention.Sources,
+        Categories: cfg.Retention.Categories,
+    }, cfg.Retention.Archive)
+    if err != nil {
+        log.Fatal("Invalid retention config:", err)
+    }
+
     // Initialize scheduler
-    sched := scheduler.NewScheduler(scraperInstance, repo)
-    if err := sched.Start(cfg.Scraper.Schedule); err != nil {
+    sched := scheduler.NewScheduler(scraperInstance, repo, retentionService, cfg.Retention.DryRun)
+    if err := sched.Start(cfg.Scraper.Schedule, cfg.Retention.Schedule); err != nil {
         log.Printf("Warning: Failed to start scheduler: %v", err)
     }
     defer sched.Stop()
@@ -105,6 +303,13 @@ func main() {
     homeHandler := handlers.NewHomeHandler(repo)
     articlesHandler := handlers.NewArticlesHandler(repo)
     scrapeHandler := handlers.NewScrapeHandler(scraperInstance)
+    jobsHandler := handlers.NewJobsHandler(repo)
+    retentionHandler := handlers.NewRetentionHandler(retentionService)
+    revisionsHandler := handlers.NewRevisionsHandler(repo)
+    trendsHandler := handlers.NewTrendsHandler(repo)
+    sentimentHandler := handlers.NewSentimentHandler(repo)
+    runsHandler := handlers.NewRunsHandler(repo)
+    sourcesHandler := handlers.NewSourcesHandler(repo, scraperInstance)
 
     // Create Fiber app
     app := fiber.New(fiber.Config{
@@ -121,6 +326,9 @@ func main() {
     // Routes
     app.Get("/", homeHandler.Index)
     // app.Get("/articles", articlesHandler.RenderArticles)
+    app.Get("/articles/:id/history", revisionsHandler.RenderHistory)
+    app.Get("/trends", trendsHandler.RenderDashboard)
+    app.Get("/sentiment", sentimentHandler.RenderDashboard)
 
     // API routes
     api := app.Group("/api")
@@ -128,11 +336,23 @@ func main() {
     api.Get("/articles/recent", articlesHandler.GetRecentActivity)
     api.Get("/articles/source/:sourceId", articlesHandler.GetBySource)
     api.Post("/scrape", scrapeHandler.TriggerScrape)
+    api.Get("/ratelimits", scrapeHandler.GetRateLimits)
+    api.Get("/proxies", scrapeHandler.GetProxies)
     api.Get("/articles-list", articlesHandler.RenderArticlesList)
+    api.Get("/articles/:id/revisions", revisionsHandler.GetRevisions)
+    api.Get("/jobs/dead", jobsHandler.GetDead)
+    api.Get("/runs", runsHandler.GetRuns)
+    api.Get("/sources", sourcesHandler.GetSources)
+    api.Get("/retention/report", retentionHandler.GetReport)
+    api.Get("/trends", trendsHandler.GetTrends)
+    api.Get("/sentiment", sentimentHandler.GetSentiment)
 
     // Category routes
     api.Get("/categories", articlesHandler.GetCategories)
     api.Get("/articles/category/:category", articlesHandler.RenderArticlesByCategory)
+    api.Get("/articles/language/:language", articlesHandler.RenderArticlesByLanguage)
+    api.Get("/languages", articlesHandler.GetLanguages)
+    api.Get("/entities/:name/articles", articlesHandler.RenderArticlesByEntity)
     // api.Get("/articles/category/:category", articlesHandler.GetByCategory)
 
     // for _, route := range app.GetRoutes() {
@@ -165,4 +385,13 @@ func main() {
     if err := app.Listen(addr); err != nil {
         log.Fatal("Server failed to start:", err)
     }
+
+    // Let running scrapes finish, or cancel them after shutdown_timeout;
+    // either way what they fetched is saved before the deferred stops run
+    log.Println("Waiting for running scrapes...")
+    ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+    defer cancel()
+    if err := scraperInstance.Shutdown(ctx); err != nil {
+        log.Printf("Running scrapes cancelled: %v", err)
+    }
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"news-scraper/internal/database"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  up             apply all pending migrations
+  down [n]       revert the last n applied migrations (default 1)
+  status         list migrations and whether they are applied
+  force <v>      mark migrations up to version v as applied without running them`
+
+// runMigrate implements the `migrate` subcommand
+func runMigrate(migrator *database.Migrator, args []string) error {
+    if len(args) == 0 {
+        return fmt.Errorf("missing command\n%s", migrateUsage)
+    }
+
+    ctx := context.Background()
+
+    switch args[0] {
+    case "up":
+        applied, err := migrator.Up(ctx)
+        for _, m := range applied {
+            fmt.Printf("applied  %03d_%s\n", m.Version, m.Name)
+        }
+        if err != nil {
+            return err
+        }
+        if len(applied) == 0 {
+            fmt.Println("schema is up to date")
+        }
+        return nil
+
+    case "down":
+        steps := 1
+        if len(args) > 1 {
+            n, err := strconv.Atoi(args[1])
+            if err != nil || n < 1 {
+                return fmt.Errorf("invalid step count %q", args[1])
+            }
+            steps = n
+        }
+
+        reverted, err := migrator.Down(ctx, steps)
+        for _, m := range reverted {
+            fmt.Printf("reverted %03d_%s\n", m.Version, m.Name)
+        }
+        return err
+
+    case "status":
+        statuses, err := migrator.Status(ctx)
+        if err != nil {
+            return err
+        }
+        for _, s := range statuses {
+            if s.Dirty {
+                fmt.Printf("[!] %03d_%s (dirty since %s, fix by hand then run force)\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+            } else if s.Applied {
+                fmt.Printf("[x] %03d_%s (applied %s)\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+            } else {
+                fmt.Printf("[ ] %03d_%s\n", s.Version, s.Name)
+            }
+        }
+        return nil
+
+    case "force":
+        if len(args) < 2 {
+            return fmt.Errorf("missing version\n%s", migrateUsage)
+        }
+        version, err := strconv.Atoi(args[1])
+        if err != nil {
+            return fmt.Errorf("invalid version %q", args[1])
+        }
+        if err := migrator.Force(ctx, version); err != nil {
+            return err
+        }
+        fmt.Printf("schema marked at version %d\n", version)
+        return nil
+
+    default:
+        return fmt.Errorf("unknown command %q\n%s", args[0], migrateUsage)
+    }
+}
@@ -0,0 +1,170 @@
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by Allow while a source's circuit is open
+// The error is an *OpenError, which also says when to come back.
+var ErrOpen = errors.New("circuit open")
+
+// OpenError is the ErrOpen returned by Allow
+type OpenError struct {
+    RetryAt time.Time // end of the cool-down; zero while a probe is in flight
+}
+
+func (e *OpenError) Error() string {
+    if e.RetryAt.IsZero() {
+        return ErrOpen.Error() + ": probe in progress"
+    }
+    return ErrOpen.Error() + " until " + e.RetryAt.Format(time.RFC3339)
+}
+
+func (e *OpenError) Is(target error) bool { return target == ErrOpen }
+
+// States of a circuit
+const (
+    Closed   = "closed"    // requests go through
+    Open     = "open"      // requests are refused until the cool-down ends
+    HalfOpen = "half_open" // one probe request is deciding whether to close again
+)
+
+// Config holds breaker settings
+type Config struct {
+    Threshold int           // Consecutive failures that open a circuit (default 5)
+    Cooldown  time.Duration // How long an open circuit refuses requests before a probe (default 30m)
+}
+
+// Breakers keeps one circuit per source
+// A circuit opens after Threshold consecutive failures and refuses every
+// request to the source until Cooldown has passed. The first request
+// after that is let through as a probe: success closes the circuit,
+// failure opens it for another cool-down. State lives in memory and
+// starts closed when the process restarts.
+type Breakers struct {
+    cfg Config
+
+    mu       sync.Mutex
+    circuits map[int]*circuit
+}
+
+type circuit struct {
+    state     string
+    failures  int
+    lastError string
+    openedAt  time.Time
+    retryAt   time.Time
+    probing   bool // a half-open probe is in flight
+}
+
+// Status is the state of one circuit as shown in the sources API
+type Status struct {
+    State     string     `json:"state"`
+    Failures  int        `json:"failures"` // consecutive failures so far
+    LastError string     `json:"last_error,omitempty"`
+    OpenedAt  *time.Time `json:"opened_at,omitempty"`
+    RetryAt   *time.Time `json:"retry_at,omitempty"`
+}
+
+// New creates breakers with cfg, applying defaults to unset fields
+func New(cfg Config) *Breakers {
+    if cfg.Threshold <= 0 {
+        cfg.Threshold = 5
+    }
+    if cfg.Cooldown <= 0 {
+        cfg.Cooldown = 30 * time.Minute
+    }
+    return &Breakers{cfg: cfg, circuits: make(map[int]*circuit)}
+}
+
+// Allow reports whether a request to the source may go out
+// Once the cool-down of an open circuit has passed, the first caller is
+// let through as the probe and the others are refused until it reports.
+func (b *Breakers) Allow(sourceID int) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    c, ok := b.circuits[sourceID]
+    if !ok {
+        return nil
+    }
+    switch c.state {
+    case Open:
+        if time.Now().Before(c.retryAt) {
+            return &OpenError{RetryAt: c.retryAt}
+        }
+        c.state = HalfOpen
+        c.probing = true
+        return nil
+    case HalfOpen:
+        if c.probing {
+            return &OpenError{}
+        }
+        c.probing = true
+    }
+    return nil
+}
+
+// Success closes the source's circuit
+func (b *Breakers) Success(sourceID int) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    delete(b.circuits, sourceID)
+}
+
+// Failure counts a failed request; the circuit opens at the threshold,
+// or straight away when the failure was a half-open probe
+func (b *Breakers) Failure(sourceID int, err error) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    c, ok := b.circuits[sourceID]
+    if !ok {
+        c = &circuit{state: Closed}
+        b.circuits[sourceID] = c
+    }
+    c.failures++
+    if err != nil {
+        c.lastError = err.Error()
+    }
+
+    if c.state == HalfOpen || c.failures >= b.cfg.Threshold {
+        now := time.Now().UTC()
+        c.state = Open
+        c.openedAt = now
+        c.retryAt = now.Add(b.cfg.Cooldown)
+        c.probing = false
+    }
+}
+
+// Abort releases a request that ended without saying anything about the
+// source, e.g. because it was cancelled. A half-open probe is handed to
+// the next caller of Allow instead of keeping the circuit refusing.
+func (b *Breakers) Abort(sourceID int) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    if c, ok := b.circuits[sourceID]; ok {
+        c.probing = false
+    }
+}
+
+// Status returns the state of the source's circuit
+func (b *Breakers) Status(sourceID int) Status {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    c, ok := b.circuits[sourceID]
+    if !ok {
+        return Status{State: Closed}
+    }
+    status := Status{State: c.state, Failures: c.failures, LastError: c.lastError}
+    if c.state != Closed {
+        openedAt, retryAt := c.openedAt, c.retryAt
+        status.OpenedAt, status.RetryAt = &openedAt, &retryAt
+    }
+    return status
+}
@@ -0,0 +1,142 @@
+package circuit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakers(t *testing.T) {
+    const cooldown = 20 * time.Millisecond
+
+    // step is one call on source 1 followed by the state it should leave
+    type step struct {
+        op       string // allow, success, failure, abort or wait (out the cool-down)
+        refused  bool   // allow: want ErrOpen
+        state    string
+        failures int
+    }
+
+    tests := []struct {
+        name  string
+        steps []step
+    }{
+        {"unknown source is closed", []step{
+            {op: "allow", state: Closed},
+        }},
+        {"failures below the threshold stay closed", []step{
+            {op: "failure", state: Closed, failures: 1},
+            {op: "failure", state: Closed, failures: 2},
+            {op: "allow", state: Closed, failures: 2},
+        }},
+        {"success resets the count", []step{
+            {op: "failure", state: Closed, failures: 1},
+            {op: "failure", state: Closed, failures: 2},
+            {op: "success", state: Closed},
+            {op: "failure", state: Closed, failures: 1},
+        }},
+        {"threshold opens", []step{
+            {op: "failure", state: Closed, failures: 1},
+            {op: "failure", state: Closed, failures: 2},
+            {op: "failure", state: Open, failures: 3},
+            {op: "allow", refused: true, state: Open, failures: 3},
+        }},
+        {"probe after the cool-down closes on success", []step{
+            {op: "failure"}, {op: "failure"}, {op: "failure", state: Open, failures: 3},
+            {op: "wait", state: Open, failures: 3},
+            {op: "allow", state: HalfOpen, failures: 3},
+            {op: "allow", refused: true, state: HalfOpen, failures: 3}, // one probe at a time
+            {op: "success", state: Closed},
+            {op: "allow", state: Closed},
+        }},
+        {"failed probe opens again", []step{
+            {op: "failure"}, {op: "failure"}, {op: "failure", state: Open, failures: 3},
+            {op: "wait", state: Open, failures: 3},
+            {op: "allow", state: HalfOpen, failures: 3},
+            {op: "failure", state: Open, failures: 4},
+            {op: "allow", refused: true, state: Open, failures: 4},
+        }},
+        {"aborted probe is handed on", []step{
+            {op: "failure"}, {op: "failure"}, {op: "failure", state: Open, failures: 3},
+            {op: "wait", state: Open, failures: 3},
+            {op: "allow", state: HalfOpen, failures: 3},
+            {op: "abort", state: HalfOpen, failures: 3},
+            {op: "allow", state: HalfOpen, failures: 3},
+            {op: "allow", refused: true, state: HalfOpen, failures: 3},
+        }},
+        {"abort of a closed circuit does nothing", []step{
+            {op: "abort", state: Closed},
+            {op: "failure", state: Closed, failures: 1},
+            {op: "abort", state: Closed, failures: 1},
+        }},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            b := New(Config{Threshold: 3, Cooldown: cooldown})
+            for i, s := range tt.steps {
+                switch s.op {
+                case "allow":
+                    err := b.Allow(1)
+                    if refused := errors.Is(err, ErrOpen); refused != s.refused {
+                        t.Fatalf("step %d: Allow() = %v, want refused %v", i, err, s.refused)
+                    }
+                case "success":
+                    b.Success(1)
+                case "failure":
+                    b.Failure(1, errors.New("HTTP 503"))
+                case "abort":
+                    b.Abort(1)
+                case "wait":
+                    time.Sleep(cooldown + 5*time.Millisecond)
+                }
+
+                if s.state == "" {
+                    continue
+                }
+                if got := b.Status(1); got.State != s.state || got.Failures != s.failures {
+                    t.Fatalf("step %d (%s): %s with %d failures, want %s with %d", i, s.op, got.State, got.Failures, s.state, s.failures)
+                }
+            }
+            if b.Status(2).State != Closed {
+                t.Error("another source's circuit changed")
+            }
+        })
+    }
+}
+
+func TestOpenError(t *testing.T) {
+    b := New(Config{Threshold: 1, Cooldown: time.Hour})
+    b.Failure(1, errors.New("HTTP 503"))
+
+    err := b.Allow(1)
+    var open *OpenError
+    if !errors.As(err, &open) || !errors.Is(err, ErrOpen) {
+        t.Fatalf("Allow() = %v, want an *OpenError matching ErrOpen", err)
+    }
+
+    status := b.Status(1)
+    if status.RetryAt == nil || !open.RetryAt.Equal(*status.RetryAt) || status.LastError != "HTTP 503" {
+        t.Errorf("status %+v, want retry at %v and the last error", status, open.RetryAt)
+    }
+    if status.OpenedAt == nil || status.RetryAt.Sub(*status.OpenedAt) != time.Hour {
+        t.Errorf("opened at %v, retry at %v, want one cool-down apart", status.OpenedAt, status.RetryAt)
+    }
+}
+
+func TestNewDefaults(t *testing.T) {
+    tests := []struct {
+        in   Config
+        want Config
+    }{
+        {Config{}, Config{Threshold: 5, Cooldown: 30 * time.Minute}},
+        {Config{Threshold: -1, Cooldown: -time.Second}, Config{Threshold: 5, Cooldown: 30 * time.Minute}},
+        {Config{Threshold: 2, Cooldown: time.Minute}, Config{Threshold: 2, Cooldown: time.Minute}},
+    }
+
+    for _, tt := range tests {
+        if got := New(tt.in).cfg; got != tt.want {
+            t.Errorf("New(%+v) config = %+v, want %+v", tt.in, got, tt.want)
+        }
+    }
+}
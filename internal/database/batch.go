@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"news-scraper/internal/models"
+)
+
+// saveBatchSize bounds the rows per multi-row INSERT and per IN (...) lookup
+// 200 rows x 11 columns stays well under every driver's placeholder limit.
+const saveBatchSize = 200
+
+// SaveArticles upserts all articles of a scrape run in one transaction
+// Each article is counted as inserted, updated or unchanged by comparing
+// it with the stored row first. Unchanged rows are still written so their
+// scraped_at moves forward, the same as SaveArticle does.
+// If ctx is cancelled the transaction is rolled back and nothing is saved.
+func (r *SQLRepository) SaveArticles(ctx context.Context, articles []models.Article) (models.SaveResult, error) {
+    var result models.SaveResult
+
+    articles = dedupeByKey(articles)
+    if len(articles) == 0 {
+        return result, nil
+    }
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return result, err
+    }
+    defer tx.Rollback()
+
+    now := time.Now().UTC()
+    for start := 0; start < len(articles); start += saveBatchSize {
+        batch := articles[start:min(start+saveBatchSize, len(articles))]
+
+        existing, err := r.storedArticles(ctx, tx, batch)
+        if err != nil {
+            return models.SaveResult{}, err
+        }
+        batch = dedupeByKey(batch)
+        var changed []string
+        for _, a := range batch {
+            stored, ok := existing[a.CanonicalURL]
+            switch {
+            case !ok:
+                result.Inserted++
+                changed = append(changed, a.CanonicalURL)
+            case stored.Title != a.Title || (a.Summary != "" && stored.Summary != a.Summary) || stored.Category != a.Category:
+                result.Updated++
+                changed = append(changed, a.CanonicalURL)
+            default:
+                result.Unchanged++
+            }
+        }
+
+        query := `INSERT INTO articles (source_id, source_name, title, url, canonical_url, summary, category, language, sentiment, scraped_at, created_at)
+                  VALUES ` + strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?), ", len(batch)), ", ") + ` ` + r.d.upsertArticle
+
+        args := make([]any, 0, len(batch)*11)
+        for _, a := range batch {
+            args = append(args, a.SourceID, a.SourceName, a.Title, a.URL, a.CanonicalURL, a.Summary, a.Category, a.Language, savedSentiment(a, existing), now, now)
+        }
+        if _, err := r.d.exec(ctx, tx, query, args...); err != nil {
+            return models.SaveResult{}, err
+        }
+
+        if err := r.recordRevisions(ctx, tx, changed); err != nil {
+            return models.SaveResult{}, err
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.SaveResult{}, err
+    }
+    return result, nil
+}
+
+// storedArticles returns the stored title, summary, category and language of a batch, keyed by canonical URL
+// An article whose URL is stored under another key, because the worker
+// re-keyed it to the page's rel=canonical, takes over that key in batch.
+func (r *SQLRepository) storedArticles(ctx context.Context, q querier, batch []models.Article) (map[string]models.Article, error) {
+    args := make([]any, 0, 2*len(batch))
+    for _, a := range batch {
+        args = append(args, a.CanonicalURL)
+    }
+    for _, a := range batch {
+        args = append(args, a.URL)
+    }
+
+    query := `SELECT url, canonical_url, title, COALESCE(summary, ''), COALESCE(category, ''), COALESCE(language, '') FROM articles
+              WHERE canonical_url IN (` + placeholders(len(batch)) + `) OR url IN (` + placeholders(len(batch)) + `)`
+    rows, err := r.d.query(ctx, q, query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    stored := make(map[string]models.Article)
+    keyByURL := make(map[string]string)
+    for rows.Next() {
+        var a models.Article
+        if err := rows.Scan(&a.URL, &a.CanonicalURL, &a.Title, &a.Summary, &a.Category, &a.Language); err != nil {
+            return nil, err
+        }
+        stored[a.CanonicalURL] = a
+        keyByURL[a.URL] = a.CanonicalURL
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    for i, a := range batch {
+        if _, ok := stored[a.CanonicalURL]; !ok && keyByURL[a.URL] != "" {
+            batch[i].CanonicalURL = keyByURL[a.URL]
+        }
+    }
+    return stored, nil
+}
+
+// dedupeByKey keeps the last occurrence of each canonical URL, filling
+// in the key from URL where it is empty
+// A multi-row upsert may not touch the same row twice on PostgreSQL.
+func dedupeByKey(articles []models.Article) []models.Article {
+    index := make(map[string]int, len(articles))
+    var out []models.Article
+    for _, a := range articles {
+        if a.CanonicalURL == "" {
+            a.CanonicalURL = a.URL
+        }
+        if i, ok := index[a.CanonicalURL]; ok {
+            out[i] = a
+            continue
+        }
+        index[a.CanonicalURL] = len(out)
+        out = append(out, a)
+    }
+    return out
+}
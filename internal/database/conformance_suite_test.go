@@ -0,0 +1,868 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"news-scraper/internal/models"
+)
+
+// runConformance exercises every Repository operation and reports where a
+// backend deviates from the expected behaviour. All backends (MySQL,
+// PostgreSQL, SQLite and in-memory) must pass it.
+//
+// It writes sources, articles and jobs, so it must run against an empty,
+// fully migrated scratch database; it refuses to start if articles exist.
+func runConformance(ctx context.Context, repo Repository) error {
+    existing, err := repo.GetRecentArticles(ctx, 1)
+    if err != nil {
+        return fmt.Errorf("GetRecentArticles: %w", err)
+    }
+    if len(existing) > 0 {
+        return errors.New("conformance must run against an empty database")
+    }
+
+    c := &conformance{ctx: ctx, repo: repo}
+    c.sources()
+    c.articles()
+    c.jobs()
+    c.retention()
+    c.batch()
+    c.runs()
+    c.pages()
+    c.cleanup()
+
+    return errors.Join(c.failures...)
+}
+
+type conformance struct {
+    ctx      context.Context
+    repo     Repository
+    failures []error
+
+    source   models.Source
+    inactive models.Source
+}
+
+func (c *conformance) fail(format string, args ...any) {
+    c.failures = append(c.failures, fmt.Errorf(format, args...))
+}
+
+// must records err and reports whether the caller may continue
+func (c *conformance) must(op string, err error) bool {
+    if err != nil {
+        c.fail("%s: %v", op, err)
+        return false
+    }
+    return true
+}
+
+func (c *conformance) sources() {
+    c.source = models.Source{
+        Name:            "Conformance Source",
+        URL:             "https://conformance.example.com",
+        SelectorTitle:   "h2 a",
+        SelectorLink:    "h2 a",
+        SelectorSummary: "p.summary",
+        SelectorBody:    "article p",
+        UserAgent:       "Mozilla/5.0 (conformance)",
+        TimeoutSeconds:  45,
+        DeadlineSeconds: 300,
+        Headers:         map[string]string{"Accept-Language": "de-DE,de;q=0.9"},
+        Cookies:         "consent=yes; region=eu",
+        SelectorNext:    "a[rel=next]",
+        PageURLTemplate: "?page={n}",
+        MaxPages:        3,
+        StopAfterKnown:  10,
+        DefaultCategory: "general",
+        Active:          true,
+    }
+    if !c.must("CreateSource", c.repo.CreateSource(c.ctx, &c.source)) {
+        return
+    }
+    if c.source.ID == 0 {
+        c.fail("CreateSource: ID not set")
+    }
+
+    c.inactive = models.Source{
+        Name:          "Conformance Inactive",
+        URL:           "https://inactive.conformance.example.com",
+        SelectorTitle: "h2 a",
+        SelectorLink:  "h2 a",
+        IgnoreRobots:  true,
+        ProxyPool:     "residential",
+        Active:        false,
+    }
+    c.must("CreateSource (inactive)", c.repo.CreateSource(c.ctx, &c.inactive))
+
+    got, err := c.repo.GetSourceByID(c.ctx, c.source.ID)
+    if c.must("GetSourceByID", err) {
+        switch {
+        case got == nil:
+            c.fail("GetSourceByID: source %d not found", c.source.ID)
+        case got.Name != c.source.Name || got.URL != c.source.URL || got.SelectorBody != c.source.SelectorBody ||
+            got.DefaultCategory != c.source.DefaultCategory || !got.Active || got.IgnoreRobots || got.ProxyPool != "":
+            c.fail("GetSourceByID: got %+v, want %+v", *got, c.source)
+        case got.UserAgent != c.source.UserAgent || got.TimeoutSeconds != c.source.TimeoutSeconds ||
+            got.DeadlineSeconds != c.source.DeadlineSeconds ||
+            len(got.Headers) != 1 || got.Headers["Accept-Language"] != c.source.Headers["Accept-Language"] ||
+            got.Cookies != c.source.Cookies:
+            c.fail("GetSourceByID: request settings not stored, got %+v", *got)
+        case got.SelectorNext != c.source.SelectorNext || got.PageURLTemplate != c.source.PageURLTemplate ||
+            got.MaxPages != c.source.MaxPages || got.StopAfterKnown != c.source.StopAfterKnown:
+            c.fail("GetSourceByID: pagination not stored, got %+v", *got)
+        }
+    }
+    if got, err := c.repo.GetSourceByID(c.ctx, c.inactive.ID); c.must("GetSourceByID", err) && (got == nil || !got.IgnoreRobots || got.ProxyPool != "residential" || got.Headers != nil) {
+        c.fail("GetSourceByID: ignore_robots or proxy_pool not stored, got %+v", got)
+    }
+
+    missing, err := c.repo.GetSourceByID(c.ctx, c.source.ID+c.inactive.ID+1000)
+    if c.must("GetSourceByID (missing)", err) && missing != nil {
+        c.fail("GetSourceByID: expected nil for unknown id, got %+v", *missing)
+    }
+
+    active, err := c.repo.GetActiveSources(c.ctx)
+    if c.must("GetActiveSources", err) {
+        var foundActive, foundInactive bool
+        for _, s := range active {
+            foundActive = foundActive || s.ID == c.source.ID
+            foundInactive = foundInactive || s.ID == c.inactive.ID
+        }
+        if !foundActive {
+            c.fail("GetActiveSources: active source missing")
+        }
+        if foundInactive {
+            c.fail("GetActiveSources: inactive source returned")
+        }
+    }
+
+    all, err := c.repo.GetSources(c.ctx)
+    if c.must("GetSources", err) {
+        var found int
+        for _, s := range all {
+            if s.ID == c.source.ID || s.ID == c.inactive.ID {
+                found++
+            }
+        }
+        if found != 2 {
+            c.fail("GetSources: expected active and inactive source, found %d of them", found)
+        }
+    }
+}
+
+func (c *conformance) articles() {
+    if c.source.ID == 0 {
+        return
+    }
+
+    first := &models.Article{
+        SourceID: c.source.ID, SourceName: c.source.Name,
+        Title: "First headline", URL: "https://conformance.example.com/a/1",
+        Summary: "", Category: "technology",
+    }
+    second := &models.Article{
+        SourceID: c.source.ID, SourceName: c.source.Name,
+        Title: "Second headline", URL: "https://conformance.example.com/a/2",
+        Summary: "Teaser", Category: "sports",
+    }
+    if !c.must("SaveArticle", c.repo.SaveArticle(c.ctx, first)) {
+        return
+    }
+    // Keep scraped_at distinct so ordering is deterministic
+    time.Sleep(1100 * time.Millisecond)
+    c.must("SaveArticle", c.repo.SaveArticle(c.ctx, second))
+
+    // Upsert: same URL updates in place
+    first.Title = "First headline, revised"
+    c.must("SaveArticle (upsert)", c.repo.SaveArticle(c.ctx, first))
+    c.must("SaveArticle (unchanged)", c.repo.SaveArticle(c.ctx, first))
+
+    bySource, err := c.repo.GetArticlesBySource(c.ctx, c.source.ID)
+    if c.must("GetArticlesBySource", err) {
+        if len(bySource) != 2 {
+            c.fail("GetArticlesBySource: got %d articles, want 2 (upsert must not duplicate)", len(bySource))
+        }
+        for _, a := range bySource {
+            if a.URL == first.URL && a.Title != first.Title {
+                c.fail("SaveArticle (upsert): title %q, want %q", a.Title, first.Title)
+            }
+            if a.SourceName != c.source.Name {
+                c.fail("GetArticlesBySource: source_name %q, want %q", a.SourceName, c.source.Name)
+            }
+        }
+    }
+
+    urls, err := c.repo.GetArticleURLsBySource(c.ctx, c.source.ID)
+    if c.must("GetArticleURLsBySource", err) {
+        found := slices.ContainsFunc(urls, func(a models.Article) bool { return a.URL == first.URL && a.CanonicalURL == first.URL })
+        if len(urls) != 2 || !found {
+            c.fail("GetArticleURLsBySource: got %v, want 2 URLs including %s, keyed on itself", urls, first.URL)
+        }
+    }
+    if urls, err := c.repo.GetArticleURLsBySource(c.ctx, c.inactive.ID); c.must("GetArticleURLsBySource", err) && len(urls) != 0 {
+        c.fail("GetArticleURLsBySource: got %v for a source without articles", urls)
+    }
+
+    recent, err := c.repo.GetRecentArticles(c.ctx, 1)
+    if c.must("GetRecentArticles", err) {
+        if len(recent) != 1 {
+            c.fail("GetRecentArticles: limit not applied, got %d", len(recent))
+        } else if recent[0].URL != first.URL {
+            c.fail("GetRecentArticles: newest first, got %s", recent[0].URL)
+        }
+    }
+
+    byCategory, err := c.repo.GetArticlesByCategory(c.ctx, "sports", 10)
+    if c.must("GetArticlesByCategory", err) {
+        if len(byCategory) != 1 || byCategory[0].URL != second.URL {
+            c.fail("GetArticlesByCategory: got %d articles, want only %s", len(byCategory), second.URL)
+        }
+    }
+
+    categories, err := c.repo.GetCategories(c.ctx)
+    if c.must("GetCategories", err) {
+        if len(categories) != 2 || categories[0] != "sports" || categories[1] != "technology" {
+            c.fail("GetCategories: got %v, want [sports technology]", categories)
+        }
+    }
+
+    // The page description fills an empty summary but never replaces a teaser
+    content := models.ArticleContent{
+        Body:        "Paragraph one.\n\nParagraph two.",
+        Description: "From the page",
+        ImageURL:    "https://conformance.example.com/img.jpg",
+        Author:      "Reporter",
+        PublishedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+    }
+    c.must("UpdateArticleContent", c.repo.UpdateArticleContent(c.ctx, first.URL, content))
+    c.must("UpdateArticleContent", c.repo.UpdateArticleContent(c.ctx, second.URL, content))
+
+    if len(bySource) == 2 {
+        ids := []int{bySource[0].ID, bySource[1].ID}
+        full, err := c.repo.GetArticlesByIDs(c.ctx, ids)
+        if c.must("GetArticlesByIDs", err) {
+            if len(full) != 2 {
+                c.fail("GetArticlesByIDs: got %d articles, want 2", len(full))
+            }
+            for _, a := range full {
+                if a.Body != content.Body || a.ImageURL != content.ImageURL || a.Author != content.Author || !a.PublishedAt.Equal(content.PublishedAt) {
+                    c.fail("UpdateArticleContent: content not stored for %s", a.URL)
+                }
+                if a.CanonicalURL != a.URL || a.Sentiment == nil {
+                    c.fail("GetArticlesByIDs: %s has canonical URL %q and sentiment %v", a.URL, a.CanonicalURL, a.Sentiment)
+                }
+                if a.URL == first.URL && a.Summary != content.Description {
+                    c.fail("UpdateArticleContent: empty summary not filled, got %q", a.Summary)
+                }
+                if a.URL == second.URL && a.Summary != second.Summary {
+                    c.fail("UpdateArticleContent: teaser overwritten, got %q", a.Summary)
+                }
+            }
+        }
+    }
+
+    // Each distinct title/summary/body is a revision; an unchanged save is not
+    for _, a := range bySource {
+        revisions, err := c.repo.GetArticleRevisions(c.ctx, a.ID)
+        if !c.must("GetArticleRevisions", err) {
+            continue
+        }
+
+        want := []string{"Second headline", "Second headline"}
+        if a.URL == first.URL {
+            want = []string{"First headline", "First headline, revised", "First headline, revised"}
+        }
+        if len(revisions) != len(want) {
+            c.fail("GetArticleRevisions: got %d revisions for %s, want %d", len(revisions), a.URL, len(want))
+            continue
+        }
+        for i, rev := range revisions {
+            if rev.Title != want[i] || rev.ArticleID != a.ID || rev.ContentHash == "" {
+                c.fail("GetArticleRevisions: revision %d of %s is %+v", i, a.URL, rev)
+            }
+        }
+        if last := revisions[len(revisions)-1]; last.Body != content.Body {
+            c.fail("GetArticleRevisions: latest revision of %s has no body", a.URL)
+        }
+    }
+
+
+    c.summaries(first, content)
+    c.languages(second)
+    c.entities(first, second)
+    c.sentiment()
+}
+
+// summaries checks which summary an article ends up with: the listing
+// teaser, then the page description, then one generated from the body
+func (c *conformance) summaries(first *models.Article, content models.ArticleContent) {
+    generated := models.ArticleContent{Body: content.Body, Summary: "Generated from the body"}
+
+    // A listing without a summary keeps the one filled from the page,
+    // and a generated summary never replaces it
+    c.must("SaveArticle (no summary)", c.repo.SaveArticle(c.ctx, first))
+    c.must("UpdateArticleContent (generated)", c.repo.UpdateArticleContent(c.ctx, first.URL, generated))
+
+    third := &models.Article{
+        SourceID: c.source.ID, SourceName: c.source.Name,
+        Title: "Third headline", URL: "https://conformance.example.com/a/3", Category: "technology",
+    }
+    if !c.must("SaveArticle", c.repo.SaveArticle(c.ctx, third)) {
+        return
+    }
+    c.must("UpdateArticleContent (generated)", c.repo.UpdateArticleContent(c.ctx, third.URL, generated))
+
+    articles, err := c.repo.GetArticlesBySource(c.ctx, c.source.ID)
+    if !c.must("GetArticlesBySource", err) {
+        return
+    }
+    for _, a := range articles {
+        switch a.URL {
+        case first.URL:
+            if a.Summary != content.Description || a.SummaryGenerated {
+                c.fail("summary of %s: got %q (generated %v), want the page description", a.URL, a.Summary, a.SummaryGenerated)
+            }
+        case third.URL:
+            if a.Summary != generated.Summary || !a.SummaryGenerated {
+                c.fail("summary of %s: got %q (generated %v), want the generated one", a.URL, a.Summary, a.SummaryGenerated)
+            }
+            third.ID = a.ID
+        }
+    }
+
+    // A real description replaces the generated summary
+    c.must("UpdateArticleContent", c.repo.UpdateArticleContent(c.ctx, third.URL, content))
+    if full, err := c.repo.GetArticlesByIDs(c.ctx, []int{third.ID}); c.must("GetArticlesByIDs", err) {
+        if len(full) != 1 || full[0].Summary != content.Description || full[0].SummaryGenerated {
+            c.fail("UpdateArticleContent: generated summary not replaced by the description, got %+v", full)
+        }
+    }
+
+    _, err = c.repo.DeleteArticles(c.ctx, []int{third.ID})
+    c.must("DeleteArticles", err)
+}
+
+// languages checks that a listing never replaces a stored language but the
+// fetched page does
+func (c *conformance) languages(article *models.Article) {
+    article.Language = "hi"
+    c.must("SaveArticle (language)", c.repo.SaveArticle(c.ctx, article))
+    article.Language = "en"
+    c.must("SaveArticle (language)", c.repo.SaveArticle(c.ctx, article))
+
+    check := func(want string) {
+        found, err := c.repo.GetArticlesByLanguage(c.ctx, want, 10)
+        if c.must("GetArticlesByLanguage", err) && (len(found) != 1 || found[0].URL != article.URL || found[0].Language != want) {
+            c.fail("GetArticlesByLanguage(%s): got %d articles, want only %s", want, len(found), article.URL)
+        }
+        languages, err := c.repo.GetLanguages(c.ctx)
+        if c.must("GetLanguages", err) && (len(languages) != 1 || languages[0] != want) {
+            c.fail("GetLanguages: got %v, want [%s]", languages, want)
+        }
+    }
+    check("hi")
+
+    page := models.ArticleContent{Body: "Paragraph one.\n\nParagraph two.", Language: "mr"}
+    c.must("UpdateArticleContent (language)", c.repo.UpdateArticleContent(c.ctx, article.URL, page))
+    check("mr")
+}
+
+// sentiment checks that saves score the stored title and summary, so a
+// listing without a summary keeps the summary's score
+func (c *conformance) sentiment() {
+    article := &models.Article{
+        SourceID: c.source.ID, SourceName: c.source.Name,
+        Title: "Fourth headline", URL: "https://conformance.example.com/a/4",
+        Summary: "A great win and a happy celebration", Category: "sports",
+    }
+    check := func(op string, positive bool) {
+        articles, err := c.repo.GetArticlesCreatedSince(c.ctx, time.Now().Add(-time.Hour))
+        if !c.must("GetArticlesCreatedSince", err) {
+            return
+        }
+        for _, a := range articles {
+            if a.Title != article.Title {
+                continue
+            }
+            article.ID = a.ID
+            if a.Sentiment == nil || (*a.Sentiment > 0) != positive {
+                c.fail("%s: sentiment %v, want positive %v", op, a.Sentiment, positive)
+            }
+            return
+        }
+        c.fail("GetArticlesCreatedSince: %q not found", article.Title)
+    }
+
+    c.must("SaveArticle", c.repo.SaveArticle(c.ctx, article))
+    check("SaveArticle", true)
+
+    article.Summary = ""
+    _, err := c.repo.SaveArticles(c.ctx, []models.Article{*article})
+    c.must("SaveArticles (no summary)", err)
+    check("SaveArticles (no summary)", true)
+
+    article.Summary = "A deadly crash killed three and injured dozens"
+    c.must("SaveArticle", c.repo.SaveArticle(c.ctx, article))
+    check("SaveArticle (new summary)", false)
+
+    if article.ID != 0 {
+        _, err = c.repo.DeleteArticles(c.ctx, []int{article.ID})
+        c.must("DeleteArticles", err)
+    }
+}
+
+func (c *conformance) entities(first, second *models.Article) {
+    person := models.Entity{Type: models.EntityPerson, Name: "Ada Lovelace", NormalizedName: "ada lovelace", Mentions: 2}
+    org := models.Entity{Type: models.EntityOrganization, Name: "Analytical Engine Co", NormalizedName: "analytical engine co", Mentions: 1}
+
+    c.must("SetArticleEntities", c.repo.SetArticleEntities(c.ctx, first.URL, []models.Entity{org, person}))
+    c.must("SetArticleEntities", c.repo.SetArticleEntities(c.ctx, second.URL, []models.Entity{person}))
+    c.must("SetArticleEntities (unknown article)", c.repo.SetArticleEntities(c.ctx, "https://example.com/conformance/missing", []models.Entity{person}))
+
+    mentioning, err := c.repo.GetArticlesByEntity(c.ctx, person.NormalizedName, 10)
+    if !c.must("GetArticlesByEntity", err) {
+        return
+    }
+    if len(mentioning) != 2 {
+        c.fail("GetArticlesByEntity: got %d articles, want 2", len(mentioning))
+        return
+    }
+    ids := []int{mentioning[0].ID, mentioning[1].ID}
+
+    entities, err := c.repo.GetArticleEntities(c.ctx, ids)
+    if c.must("GetArticleEntities", err) {
+        for _, id := range ids {
+            if len(entities[id]) == 0 || entities[id][0] != person {
+                c.fail("GetArticleEntities: article %d has %+v, want %+v first", id, entities[id], person)
+            }
+        }
+    }
+
+    since := time.Now().Add(-time.Hour)
+    today := time.Now().UTC().Format("2006-01-02")
+    for _, tt := range []struct {
+        query string
+        want  int
+    }{
+        {"", 2},
+        {"FIRST headline", 1},
+        {"headline%", 0},
+    } {
+        counts, err := c.repo.GetSentimentCounts(c.ctx, since, person.NormalizedName, tt.query)
+        if !c.must("GetSentimentCounts", err) {
+            continue
+        }
+        total := 0
+        for _, count := range counts {
+            total += count.Articles
+            if count.SourceName != c.source.Name || count.Day != today || count.Positive+count.Neutral+count.Negative != count.Scored {
+                c.fail("GetSentimentCounts(%q): group %+v, want %s on %s", tt.query, count, c.source.Name, today)
+            }
+        }
+        if total != tt.want {
+            c.fail("GetSentimentCounts(%q): got %d articles, want %d", tt.query, total, tt.want)
+        }
+    }
+
+    // Setting entities replaces the old ones
+    c.must("SetArticleEntities (replace)", c.repo.SetArticleEntities(c.ctx, first.URL, []models.Entity{org}))
+    mentioning, err = c.repo.GetArticlesByEntity(c.ctx, person.NormalizedName, 10)
+    if c.must("GetArticlesByEntity", err) && (len(mentioning) != 1 || mentioning[0].URL != second.URL) {
+        c.fail("GetArticlesByEntity after replace: got %d articles, want only %s", len(mentioning), second.URL)
+    }
+}
+
+func (c *conformance) jobs() {
+    created, err := c.repo.EnqueueJob(c.ctx, models.JobFetchArticle, "conformance/job/1", `{"url":"1"}`, 2)
+    if !c.must("EnqueueJob", err) {
+        return
+    }
+    if !created {
+        c.fail("EnqueueJob: new job not reported as created")
+    }
+
+    created, err = c.repo.EnqueueJob(c.ctx, models.JobFetchArticle, "conformance/job/1", `{"url":"1"}`, 2)
+    if c.must("EnqueueJob (duplicate)", err) && created {
+        c.fail("EnqueueJob: duplicate reported as created")
+    }
+
+    jobs, err := c.repo.LeaseJobs(c.ctx, 10, time.Minute)
+    if !c.must("LeaseJobs", err) {
+        return
+    }
+    if len(jobs) != 1 {
+        c.fail("LeaseJobs: got %d jobs, want 1", len(jobs))
+        return
+    }
+    job := jobs[0]
+    if job.Attempts != 1 || job.Status != models.JobLeased || job.LeaseToken == "" || job.Payload != `{"url":"1"}` {
+        c.fail("LeaseJobs: unexpected job %+v", job)
+    }
+
+    again, err := c.repo.LeaseJobs(c.ctx, 10, time.Minute)
+    if c.must("LeaseJobs (held)", err) && len(again) != 0 {
+        c.fail("LeaseJobs: leased job handed out twice")
+    }
+
+    // First failure goes back to pending and is due immediately
+    c.must("FailJob", c.repo.FailJob(c.ctx, job, errors.New("boom"), time.Now().Add(-time.Second)))
+
+    jobs, err = c.repo.LeaseJobs(c.ctx, 10, time.Minute)
+    if !c.must("LeaseJobs (retry)", err) {
+        return
+    }
+    if len(jobs) != 1 || jobs[0].Attempts != 2 || jobs[0].LastError != "boom" {
+        c.fail("LeaseJobs (retry): got %+v, want one job on attempt 2", jobs)
+        return
+    }
+
+    if err := c.repo.AckJob(c.ctx, job); err == nil {
+        c.fail("AckJob: stale lease token accepted")
+    }
+
+    // Second failure exhausts max_attempts and dead-letters the job
+    c.must("FailJob (final)", c.repo.FailJob(c.ctx, jobs[0], errors.New("boom again"), time.Now().Add(-time.Second)))
+
+    dead, err := c.repo.GetDeadJobs(c.ctx, 10)
+    if c.must("GetDeadJobs", err) && (len(dead) != 1 || dead[0].ID != job.ID || dead[0].LastError != "boom again") {
+        c.fail("GetDeadJobs: got %+v, want job %d", dead, job.ID)
+    }
+
+    c.must("EnqueueJob", func() error {
+        _, err := c.repo.EnqueueJob(c.ctx, models.JobFetchArticle, "conformance/job/2", `{"url":"2"}`, 3)
+        return err
+    }())
+    // A rescheduled job gives its attempt back
+    jobs, err = c.repo.LeaseJobs(c.ctx, 10, time.Minute)
+    if c.must("LeaseJobs", err) && len(jobs) == 1 {
+        c.must("RescheduleJob", c.repo.RescheduleJob(c.ctx, jobs[0], time.Now().Add(-time.Second)))
+    }
+    jobs, err = c.repo.LeaseJobs(c.ctx, 10, time.Minute)
+    if c.must("LeaseJobs", err) && len(jobs) == 1 {
+        if jobs[0].Attempts != 1 {
+            c.fail("RescheduleJob: job on attempt %d, want 1", jobs[0].Attempts)
+        }
+        c.must("AckJob", c.repo.AckJob(c.ctx, jobs[0]))
+        if err := c.repo.AckJob(c.ctx, jobs[0]); err == nil {
+            c.fail("AckJob: acked twice")
+        }
+    } else {
+        c.fail("LeaseJobs: got %d jobs, want 1", len(jobs))
+    }
+
+    purged, err := c.repo.PurgeDoneJobs(c.ctx, -time.Minute)
+    if c.must("PurgeDoneJobs", err) && purged != 1 {
+        c.fail("PurgeDoneJobs: purged %d, want 1", purged)
+    }
+}
+
+func (c *conformance) retention() {
+    candidates, err := c.repo.GetRetentionCandidates(c.ctx)
+    if !c.must("GetRetentionCandidates", err) {
+        return
+    }
+    if len(candidates) != 2 {
+        c.fail("GetRetentionCandidates: got %d, want 2", len(candidates))
+        return
+    }
+    if !candidates[0].ScrapedAt.After(candidates[1].ScrapedAt) && !candidates[0].ScrapedAt.Equal(candidates[1].ScrapedAt) {
+        c.fail("GetRetentionCandidates: not ordered newest first")
+    }
+
+    archived, err := c.repo.ArchiveArticles(c.ctx, []int{candidates[0].ID})
+    if c.must("ArchiveArticles", err) && archived != 1 {
+        c.fail("ArchiveArticles: archived %d, want 1", archived)
+    }
+
+    deleted, err := c.repo.DeleteArticles(c.ctx, []int{candidates[1].ID})
+    if c.must("DeleteArticles", err) && deleted != 1 {
+        c.fail("DeleteArticles: deleted %d, want 1", deleted)
+    }
+
+    left, err := c.repo.GetRetentionCandidates(c.ctx)
+    if c.must("GetRetentionCandidates", err) && len(left) != 0 {
+        c.fail("ArchiveArticles/DeleteArticles: %d articles left", len(left))
+    }
+}
+
+func (c *conformance) batch() {
+    if c.source.ID == 0 {
+        return
+    }
+
+    article := func(n int, title string) models.Article {
+        return models.Article{
+            SourceID: c.source.ID, SourceName: c.source.Name,
+            Title: title, URL: fmt.Sprintf("https://conformance.example.com/b/%d", n),
+            Summary: "Teaser", Category: "general",
+        }
+    }
+
+    // A URL repeated within one batch is saved once
+    saved, err := c.repo.SaveArticles(c.ctx, []models.Article{article(1, "One"), article(2, "Two"), article(2, "Two")})
+    if c.must("SaveArticles", err) && saved != (models.SaveResult{Inserted: 2}) {
+        c.fail("SaveArticles: got %+v, want 2 inserted", saved)
+    }
+
+    saved, err = c.repo.SaveArticles(c.ctx, []models.Article{article(1, "One"), article(2, "Two, revised"), article(3, "Three")})
+    if c.must("SaveArticles", err) && saved != (models.SaveResult{Inserted: 1, Updated: 1, Unchanged: 1}) {
+        c.fail("SaveArticles: got %+v, want 1 inserted, 1 updated, 1 unchanged", saved)
+    }
+
+    // A cancelled run writes nothing
+    cancelled, cancel := context.WithCancel(c.ctx)
+    cancel()
+    if _, err := c.repo.SaveArticles(cancelled, []models.Article{article(4, "Four")}); err == nil {
+        c.fail("SaveArticles: cancelled context not reported")
+    }
+
+    stored, err := c.repo.GetArticlesBySource(c.ctx, c.source.ID)
+    if c.must("GetArticlesBySource", err) && len(stored) != 3 {
+        c.fail("SaveArticles: got %d articles stored, want 3", len(stored))
+    }
+
+    // Merging into an existing key folds the duplicate and its revisions in;
+    // merging into a new key re-keys the article but keeps its URL
+    keep := article(1, "")
+    c.must("MergeArticleURL", c.repo.MergeArticleURL(c.ctx, article(3, "").URL, keep.URL))
+    c.must("MergeArticleURL", c.repo.MergeArticleURL(c.ctx, article(2, "").URL, article(9, "").URL))
+
+    // The re-keyed article is still found by its URL
+    saved, err = c.repo.SaveArticles(c.ctx, []models.Article{article(2, "Two, revised")})
+    if c.must("SaveArticles (re-keyed)", err) && saved != (models.SaveResult{Unchanged: 1}) {
+        c.fail("SaveArticles (re-keyed): got %+v, want 1 unchanged", saved)
+    }
+
+    urls, err := c.repo.GetArticleURLs(c.ctx)
+    if c.must("GetArticleURLs", err) {
+        var got []string
+        for _, a := range urls {
+            got = append(got, a.CanonicalURL)
+        }
+        if len(got) != 2 || got[0] != keep.URL || got[1] != article(9, "").URL {
+            c.fail("MergeArticleURL: got keys %v, want [%s %s]", got, keep.URL, article(9, "").URL)
+        }
+        if len(urls) == 2 && urls[1].URL != article(2, "").URL {
+            c.fail("MergeArticleURL: URL changed to %s, want %s", urls[1].URL, article(2, "").URL)
+        }
+        if len(urls) > 0 {
+            revisions, err := c.repo.GetArticleRevisions(c.ctx, urls[0].ID)
+            if c.must("GetArticleRevisions", err) && len(revisions) != 2 {
+                c.fail("MergeArticleURL: got %d revisions after merge, want 2", len(revisions))
+            }
+        }
+    }
+
+    c.stories(keep.URL)
+
+    stored, err = c.repo.GetArticlesBySource(c.ctx, c.source.ID)
+    c.must("GetArticlesBySource", err)
+
+    var ids []int
+    for _, a := range stored {
+        ids = append(ids, a.ID)
+    }
+    _, err = c.repo.DeleteArticles(c.ctx, ids)
+    c.must("DeleteArticles", err)
+}
+
+// stories expects exactly two articles, clusters one and checks both views
+func (c *conformance) stories(clusteredURL string) {
+    since := time.Now().Add(-time.Hour)
+
+    pending, err := c.repo.GetUnclusteredArticles(c.ctx, since)
+    if !c.must("GetUnclusteredArticles", err) {
+        return
+    }
+    if len(pending) != 2 {
+        c.fail("GetUnclusteredArticles: got %d, want 2", len(pending))
+        return
+    }
+
+    var clustered models.Article
+    for _, a := range pending {
+        if a.URL == clusteredURL {
+            clustered = a
+        }
+    }
+    if clustered.ID == 0 {
+        c.fail("GetUnclusteredArticles: article %s missing", clusteredURL)
+        return
+    }
+
+    // Fingerprints are written with the content, body included; the top bit
+    // must survive storage as a signed 64-bit value
+    stored, err := c.repo.GetArticlesByIDs(c.ctx, []int{clustered.ID})
+    if c.must("GetArticlesByIDs", err) && len(stored) == 1 {
+        want := articleFingerprint(stored[0].Title, stored[0].Summary, stored[0].Body)
+        if clustered.Fingerprint != want {
+            c.fail("GetUnclusteredArticles: fingerprint %x, want %x", clustered.Fingerprint, want)
+        }
+    }
+
+    byURL, err := c.repo.GetUnclusteredArticlesByURL(c.ctx, []string{clusteredURL, clusteredURL, "https://example.com/missing"})
+    if c.must("GetUnclusteredArticlesByURL", err) && (len(byURL) != 1 || byURL[0].ID != clustered.ID || byURL[0].Fingerprint != clustered.Fingerprint) {
+        c.fail("GetUnclusteredArticlesByURL: got %+v, want article %d", byURL, clustered.ID)
+    }
+
+    storyID, err := c.repo.CreateStory(c.ctx, clustered.Title)
+    if !c.must("CreateStory", err) {
+        return
+    }
+    c.must("SetArticleStory", c.repo.SetArticleStory(c.ctx, clustered.ID, storyID))
+
+    pool, err := c.repo.GetStoryFingerprints(c.ctx, since)
+    if c.must("GetStoryFingerprints", err) {
+        if len(pool) != 1 || pool[0].ID != clustered.ID || pool[0].StoryID != storyID || pool[0].Fingerprint != clustered.Fingerprint {
+            c.fail("GetStoryFingerprints: got %+v, want article %d in story %d with fingerprint %x", pool, clustered.ID, storyID, clustered.Fingerprint)
+        }
+    }
+
+    byURL, err = c.repo.GetUnclusteredArticlesByURL(c.ctx, []string{clusteredURL})
+    if c.must("GetUnclusteredArticlesByURL", err) && len(byURL) != 0 {
+        c.fail("GetUnclusteredArticlesByURL: clustered article still listed")
+    }
+
+    pending, err = c.repo.GetUnclusteredArticles(c.ctx, since)
+    if c.must("GetUnclusteredArticles", err) && (len(pending) != 1 || pending[0].ID == clustered.ID) {
+        c.fail("GetUnclusteredArticles: clustered article still listed")
+    }
+
+    listed, err := c.repo.GetArticlesBySource(c.ctx, c.source.ID)
+    if c.must("GetArticlesBySource", err) {
+        for _, a := range listed {
+            if a.ID == clustered.ID && a.StoryID != storyID {
+                c.fail("GetArticlesBySource: story_id %d, want %d", a.StoryID, storyID)
+            }
+        }
+    }
+}
+
+// runs checks the scrape history: newest first, filtered by source,
+// blocked URLs kept with their run, and purged by age
+func (c *conformance) runs() {
+    if c.source.ID == 0 || c.inactive.ID == 0 {
+        return
+    }
+
+    now := time.Now().UTC().Truncate(time.Second)
+    old := &models.ScrapeRun{
+        SourceID: c.source.ID, SourceName: c.source.Name, Status: models.RunOK,
+        ArticlesFound: 3, ArticlesNew: 1, ArticlesKnown: 2, ArticlesUpdated: 1,
+        StartedAt: now.Add(-48 * time.Hour), FinishedAt: now.Add(-48*time.Hour + time.Minute),
+    }
+    blocked := &models.ScrapeRun{
+        SourceID: c.source.ID, SourceName: c.source.Name, Status: models.RunFailed,
+        Error: "listing page blocked", ErrorKind: models.ErrorKindRobots,
+        BlockedURLs: []string{"https://conformance.example.com/", "https://conformance.example.com/private/1"},
+        StartedAt:   now.Add(-time.Hour), FinishedAt: now.Add(-time.Hour + time.Second),
+    }
+    other := &models.ScrapeRun{
+        SourceID: c.inactive.ID, SourceName: c.inactive.Name, Status: models.RunOK,
+        StartedAt: now, FinishedAt: now,
+    }
+    for _, run := range []*models.ScrapeRun{old, blocked, other} {
+        if c.must("RecordScrapeRun", c.repo.RecordScrapeRun(c.ctx, run)) && run.ID == 0 {
+            c.fail("RecordScrapeRun: ID not set")
+        }
+    }
+
+    runs, err := c.repo.GetScrapeRuns(c.ctx, c.source.ID, 10)
+    if c.must("GetScrapeRuns", err) {
+        if len(runs) != 2 || runs[0].ID != blocked.ID || runs[1].ID != old.ID {
+            c.fail("GetScrapeRuns: got %+v, want runs %d and %d, newest first", runs, blocked.ID, old.ID)
+        } else {
+            got := runs[0]
+            if got.Status != blocked.Status || got.Error != blocked.Error || got.ErrorKind != blocked.ErrorKind || !got.StartedAt.Equal(blocked.StartedAt) ||
+                len(got.BlockedURLs) != 2 || got.BlockedURLs[1] != blocked.BlockedURLs[1] {
+                c.fail("GetScrapeRuns: got %+v, want %+v", got, *blocked)
+            }
+            if len(runs[1].BlockedURLs) != 0 || runs[1].ArticlesFound != old.ArticlesFound ||
+                runs[1].ArticlesNew != old.ArticlesNew || runs[1].ArticlesKnown != old.ArticlesKnown ||
+                runs[1].ArticlesUpdated != old.ArticlesUpdated || runs[1].ErrorKind != "" {
+                c.fail("GetScrapeRuns: got %+v, want %+v", runs[1], *old)
+            }
+        }
+    }
+
+    all, err := c.repo.GetScrapeRuns(c.ctx, 0, 2)
+    if c.must("GetScrapeRuns (all)", err) && (len(all) != 2 || all[0].ID != other.ID) {
+        c.fail("GetScrapeRuns (all): got %d runs, want 2 starting with %d", len(all), other.ID)
+    }
+
+    purged, err := c.repo.PurgeScrapeRuns(c.ctx, 24*time.Hour)
+    if c.must("PurgeScrapeRuns", err) && purged != 1 {
+        c.fail("PurgeScrapeRuns: purged %d, want 1", purged)
+    }
+}
+
+func (c *conformance) pages() {
+    if c.source.ID == 0 {
+        return
+    }
+
+    const pageURL = "https://conformance.example.com/"
+    missing, err := c.repo.GetPageCache(c.ctx, pageURL)
+    if c.must("GetPageCache (missing)", err) && missing != nil {
+        c.fail("GetPageCache: expected nil for unknown page, got %+v", *missing)
+    }
+
+    now := time.Now().UTC().Truncate(time.Second)
+    first := &models.PageCache{
+        SourceID: c.source.ID, URL: pageURL, ETag: `"v1"`,
+        LastModified: "Mon, 02 Jan 2006 15:04:05 GMT", ContentHash: "abc", CheckedAt: now.Add(-time.Hour),
+    }
+    c.must("SavePageCache", c.repo.SavePageCache(c.ctx, first))
+
+    // Saving again replaces the validators; an empty ETag stays empty
+    second := &models.PageCache{SourceID: c.source.ID, URL: pageURL, ContentHash: "def", CheckedAt: now}
+    c.must("SavePageCache (replace)", c.repo.SavePageCache(c.ctx, second))
+
+    got, err := c.repo.GetPageCache(c.ctx, pageURL)
+    if c.must("GetPageCache", err) {
+        if got == nil {
+            c.fail("GetPageCache: saved page missing")
+        } else if got.ETag != "" || got.LastModified != "" || got.ContentHash != "def" ||
+            got.SourceID != c.source.ID || !got.CheckedAt.Equal(now) {
+            c.fail("GetPageCache: got %+v, want %+v", *got, *second)
+        }
+    }
+}
+
+func (c *conformance) cleanup() {
+    if c.source.ID == 0 {
+        return
+    }
+
+    // Deleting a source also deletes its articles
+    c.must("SaveArticle", c.repo.SaveArticle(c.ctx, &models.Article{
+        SourceID: c.source.ID, SourceName: c.source.Name,
+        Title: "Orphan", URL: "https://conformance.example.com/a/3", Category: "general",
+    }))
+    c.must("DeleteSource", c.repo.DeleteSource(c.ctx, c.source.ID))
+    c.must("DeleteSource", c.repo.DeleteSource(c.ctx, c.inactive.ID))
+
+    got, err := c.repo.GetSourceByID(c.ctx, c.source.ID)
+    if c.must("GetSourceByID", err) && got != nil {
+        c.fail("DeleteSource: source still present")
+    }
+
+    articles, err := c.repo.GetArticlesBySource(c.ctx, c.source.ID)
+    if c.must("GetArticlesBySource", err) && len(articles) != 0 {
+        c.fail("DeleteSource: %d articles not cascaded", len(articles))
+    }
+
+    runs, err := c.repo.GetScrapeRuns(c.ctx, 0, 10)
+    if c.must("GetScrapeRuns", err) && len(runs) != 0 {
+        c.fail("DeleteSource: %d scrape runs not cascaded", len(runs))
+    }
+
+    page, err := c.repo.GetPageCache(c.ctx, "https://conformance.example.com/")
+    if c.must("GetPageCache", err) && page != nil {
+        c.fail("DeleteSource: page cache not cascaded")
+    }
+}
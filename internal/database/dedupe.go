@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"news-scraper/internal/models"
+)
+
+// GetArticleURLs returns the id, source, URL and canonical URL of every article, oldest first
+// Used by the one-off dedupe command to find URLs that canonicalize alike.
+func (r *SQLRepository) GetArticleURLs(ctx context.Context) ([]models.Article, error) {
+    rows, err := r.d.query(ctx, r.db, `SELECT id, source_id, url, canonical_url FROM articles ORDER BY id`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var articles []models.Article
+    for rows.Next() {
+        var a models.Article
+        if err := rows.Scan(&a.ID, &a.SourceID, &a.URL, &a.CanonicalURL); err != nil {
+            return nil, err
+        }
+        articles = append(articles, a)
+    }
+    return articles, rows.Err()
+}
+
+// MergeArticleURL moves the article with canonical URL from to the canonical URL to
+// If no article has canonical URL to yet, the row is simply re-keyed and
+// keeps its URL. Otherwise the two are duplicates: from's revisions move
+// to the existing article and from is deleted. Missing from is not an error.
+func (r *SQLRepository) MergeArticleURL(ctx context.Context, from, to string) error {
+    if from == to {
+        return nil
+    }
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var fromID, toID int
+    err = r.d.queryRow(ctx, tx, `SELECT id FROM articles WHERE canonical_url = ?`, from).Scan(&fromID)
+    if err == sql.ErrNoRows {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    err = r.d.queryRow(ctx, tx, `SELECT id FROM articles WHERE canonical_url = ?`, to).Scan(&toID)
+    switch {
+    case err == sql.ErrNoRows:
+        if _, err := r.d.exec(ctx, tx, `UPDATE articles SET canonical_url = ? WHERE id = ?`, to, fromID); err != nil {
+            return err
+        }
+    case err != nil:
+        return err
+    default:
+        if _, err := r.d.exec(ctx, tx, `UPDATE article_revisions SET article_id = ? WHERE article_id = ?`, toID, fromID); err != nil {
+            return err
+        }
+        if _, err := r.d.exec(ctx, tx, `DELETE FROM articles WHERE id = ?`, fromID); err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported values of the database.driver config key
+const (
+    DriverMySQL    = "mysql"
+    DriverPostgres = "postgres"
+    DriverSQLite   = "sqlite"
+)
+
+// dialect captures the SQL differences between the supported databases
+// Queries are written once with ? placeholders and MySQL/SQLite-style
+// syntax where all three agree; anything that differs lives here.
+type dialect struct {
+    name string
+
+    // numbered placeholders ($1, $2, ...) instead of ?
+    numbered bool
+
+    // upsertArticle is the conflict clause for SaveArticle
+    // An empty listing summary keeps the stored one (description or generated),
+    // and a language detected from the full page is not replaced by the listing's.
+    upsertArticle string
+
+    // insertIgnoreJob is the conflict clause that makes EnqueueJob a no-op for duplicates
+    insertIgnoreJob string
+
+    // lockSkipLocked is appended to the job lease query
+    lockSkipLocked string
+
+    // returningID makes inserts return the new id with RETURNING instead of LastInsertId
+    returningID bool
+
+    // timeAsText binds time.Time as fixed-width UTC text so that string
+    // comparisons order correctly (SQLite has no native time type)
+    timeAsText bool
+
+    // createdDay is articles.created_at as YYYY-MM-DD text, for grouping by day
+    createdDay string
+
+    // migrationsTable is the DDL for schema_migrations
+    migrationsTable string
+
+    // columnExists counts the columns named by its (table, column) arguments
+    columnExists string
+
+    // transactionalDDL runs a migration and its schema_migrations row in
+    // one transaction; without it (MySQL) the row is marked dirty first
+    transactionalDDL bool
+}
+
+// dialectFor returns the dialect of a supported driver
+func dialectFor(driver string) (dialect, error) {
+    switch driver {
+    case "", DriverMySQL:
+        return mysqlDialect, nil
+    case DriverPostgres:
+        return postgresDialect, nil
+    case DriverSQLite:
+        return sqliteDialect, nil
+    default:
+        return dialect{}, fmt.Errorf("unsupported database driver %q", driver)
+    }
+}
+
+// rebind rewrites ? placeholders for dialects that number them
+func (d dialect) rebind(query string) string {
+    if !d.numbered {
+        return query
+    }
+
+    var b strings.Builder
+    n := 0
+    for _, ch := range query {
+        if ch == '?' {
+            n++
+            b.WriteString("$" + strconv.Itoa(n))
+            continue
+        }
+        b.WriteRune(ch)
+    }
+    return b.String()
+}
+
+// bindArgs converts arguments into the form the driver stores consistently
+func (d dialect) bindArgs(args []any) []any {
+    if !d.timeAsText {
+        return args
+    }
+
+    out := make([]any, len(args))
+    for i, arg := range args {
+        switch v := arg.(type) {
+        case time.Time:
+            out[i] = v.UTC().Format("2006-01-02 15:04:05.000000")
+        case sql.NullTime:
+            if v.Valid {
+                out[i] = v.Time.UTC().Format("2006-01-02 15:04:05.000000")
+            } else {
+                out[i] = nil
+            }
+        default:
+            out[i] = arg
+        }
+    }
+    return out
+}
+
+// querier is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type querier interface {
+    ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+    QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+    QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (d dialect) exec(ctx context.Context, q querier, query string, args ...any) (sql.Result, error) {
+    return q.ExecContext(ctx, d.rebind(query), d.bindArgs(args)...)
+}
+
+func (d dialect) query(ctx context.Context, q querier, query string, args ...any) (*sql.Rows, error) {
+    return q.QueryContext(ctx, d.rebind(query), d.bindArgs(args)...)
+}
+
+func (d dialect) queryRow(ctx context.Context, q querier, query string, args ...any) *sql.Row {
+    return q.QueryRowContext(ctx, d.rebind(query), d.bindArgs(args)...)
+}
+
+// insertID runs an INSERT and returns the generated id
+func (d dialect) insertID(ctx context.Context, q querier, query string, args ...any) (int64, error) {
+    if d.returningID {
+        var id int64
+        err := d.queryRow(ctx, q, query+" RETURNING id", args...).Scan(&id)
+        return id, err
+    }
+
+    result, err := d.exec(ctx, q, query, args...)
+    if err != nil {
+        return 0, err
+    }
+    return result.LastInsertId()
+}
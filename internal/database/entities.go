@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"news-scraper/internal/models"
+)
+
+// SetArticleEntities replaces the entities of the article with the given canonical URL
+// Articles that no longer exist are ignored, like UpdateArticleContent does.
+func (r *SQLRepository) SetArticleEntities(ctx context.Context, canonicalURL string, entities []models.Entity) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var articleID int
+    err = r.d.queryRow(ctx, tx, `SELECT id FROM articles WHERE canonical_url = ?`, canonicalURL).Scan(&articleID)
+    if err == sql.ErrNoRows {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+
+    if _, err := r.d.exec(ctx, tx, `DELETE FROM article_entities WHERE article_id = ?`, articleID); err != nil {
+        return err
+    }
+    for _, e := range entities {
+        _, err := r.d.exec(ctx, tx,
+            `INSERT INTO article_entities (article_id, type, name, normalized_name, mentions) VALUES (?, ?, ?, ?, ?)`,
+            articleID, e.Type, truncate(e.Name, 255), truncate(e.NormalizedName, 255), e.Mentions)
+        if err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+// GetArticleEntities returns the entities of each article, most mentioned first
+func (r *SQLRepository) GetArticleEntities(ctx context.Context, articleIDs []int) (map[int][]models.Entity, error) {
+    entities := make(map[int][]models.Entity)
+
+    for _, batch := range batchIDs(articleIDs) {
+        query := `SELECT article_id, type, name, normalized_name, mentions FROM article_entities
+                  WHERE article_id IN (` + placeholders(len(batch)) + `) ORDER BY article_id, mentions DESC, name`
+
+        rows, err := r.d.query(ctx, r.db, query, intArgs(batch)...)
+        if err != nil {
+            return nil, err
+        }
+
+        for rows.Next() {
+            var id int
+            var e models.Entity
+            if err := rows.Scan(&id, &e.Type, &e.Name, &e.NormalizedName, &e.Mentions); err != nil {
+                rows.Close()
+                return nil, err
+            }
+            entities[id] = append(entities[id], e)
+        }
+        rows.Close()
+        if err := rows.Err(); err != nil {
+            return nil, err
+        }
+    }
+    return entities, nil
+}
+
+// GetArticlesByEntity retrieves the most recent articles mentioning an entity
+func (r *SQLRepository) GetArticlesByEntity(ctx context.Context, normalizedName string, limit int) ([]models.Article, error) {
+    query := `SELECT ` + articleColumns + ` FROM articles
+              WHERE id IN (SELECT article_id FROM article_entities WHERE normalized_name = ?)
+              ORDER BY scraped_at DESC LIMIT ?`
+
+    rows, err := r.d.query(ctx, r.db, query, normalizedName, limit)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    return scanArticles(rows)
+}
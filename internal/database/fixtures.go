@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+	"news-scraper/internal/summarize"
+)
+
+// demoSources mirror the sample sources from the initial migration
+var demoSources = []models.Source{
+    {Name: "TechCrunch", URL: "https://techcrunch.com", SelectorTitle: `[class*="post"] a[href*="techcrunch"]`, SelectorLink: `[class*="post"] a[href*="techcrunch"]`, SelectorSummary: "div.post-block__content", DefaultCategory: "technology", Active: true},
+    {Name: "BBC News", URL: "https://www.bbc.com/news", SelectorTitle: `a[data-testid="internal-link"]`, SelectorLink: `a[data-testid="internal-link"]`, SelectorSummary: "p.gs-c-promo-summary", DefaultCategory: "world", Active: true},
+    {Name: "The Guardian", URL: "https://www.theguardian.com/international", SelectorTitle: `a[href*="/2025/"]`, SelectorLink: `a[href*="/2025/"]`, SelectorSummary: `div[data-link-name*="article title"] p`, DefaultCategory: "general", Active: true},
+    {Name: "Reuters", URL: "https://www.reuters.com", SelectorTitle: `a[href^="/world/"]`, SelectorLink: `a[href^="/world/"]`, SelectorSummary: `p[data-testid="Text"]`, DefaultCategory: "business", Active: true},
+}
+
+// demoArticles are title/summary/category triples spread over the demo sources
+var demoArticles = [][3]string{
+    {"Chipmakers race to build the next generation of AI accelerators", "New designs promise faster training at a fraction of the power draw.", "technology"},
+    {"Open-source database hits its 10th major release", "The project adds logical replication and a faster query planner.", "technology"},
+    {"Startup raises $40m to modernise freight logistics", "Investors bet on software for the industry's paper-heavy workflows.", "business"},
+    {"Central bank holds interest rates steady", "Policymakers signalled that cuts may come later in the year.", "business"},
+    {"Markets rally as inflation cools for a third month", "Stocks closed higher across Europe and Asia.", "business"},
+    {"Leaders gather for climate summit talks", "Negotiators aim to agree on a new emissions framework.", "world"},
+    {"Election results expected after record turnout", "Officials said counting would continue through the night.", "politics"},
+    {"Parliament debates new data protection bill", "The bill would give regulators powers to fine large platforms.", "politics"},
+    {"Underdogs clinch title in final-day thriller", "A late goal settled the league after a season-long chase.", "sports"},
+    {"Marathon record falls on a cool autumn morning", "The winner shaved 20 seconds off the previous best.", "sports"},
+    {"Festival line-up announced with surprise headliner", "Tickets go on sale next week.", "entertainment"},
+    {"Streaming series renewed for a final season", "The cast will return for eight more episodes.", "entertainment"},
+    {"Study links sleep patterns to heart health", "Researchers followed 50,000 adults over a decade.", "health"},
+    {"Hospitals trial AI triage in emergency rooms", "Early results show shorter waiting times.", "health"},
+    {"Telescope captures the most distant galaxy yet", "Astronomers say the light left it 13.4 billion years ago.", "science"},
+    {"Researchers map the genome of an ancient crop", "The findings could help breed drought-resistant varieties.", "science"},
+    {"Drought pushes reservoirs to record lows", "", "world"},
+    {"भारत ने रोमांचक मैच में ऑस्ट्रेलिया को हराया", "आखिरी ओवर में गेंदबाजों ने टीम को जीत दिलाई।", "sports"},
+    {"ISRO launches weather satellite ahead of the monsoon", "The Indian Space Research Organisation said the satellite would sharpen rainfall forecasts across India.", "science"},
+    {"NASA and ISRO agree to share Earth observation data", "Officials in Washington said the deal would help track floods and droughts.", "science"},
+    // The same wire stories again, so other sources pick them up as one story
+    {"Central bank holds interest rates steady as policymakers signal cuts may come later in the year", "", "business"},
+    {"Central bank holds interest rates steady, signals cuts may come later in the year", "Policymakers signalled that cuts may come later in the year.", "business"},
+}
+
+// demoBodies are article texts keyed by title, as the worker pool would fetch them
+var demoBodies = map[string]string{
+    "Drought pushes reservoirs to record lows": `Reservoirs across the region fell to their lowest levels on record this week after the driest spring in decades.
+
+Water companies said hosepipe bans could follow within weeks if the drought continues. Farmers warned that irrigation limits would cut harvests of vegetables and grain.
+
+Advertisement
+
+Officials urged households to save water, and said the reservoirs would need a wet autumn to recover. Forecasters expect the dry weather to last at least another fortnight.`,
+}
+
+// SeedDemo fills an empty repository with sample sources and articles
+// It is used by the server's --demo mode so the UI has something to show.
+func SeedDemo(ctx context.Context, repo Repository) error {
+    var sources []models.Source
+    for _, s := range demoSources {
+        source := s
+        if err := repo.CreateSource(ctx, &source); err != nil {
+            return fmt.Errorf("failed to seed source %s: %w", s.Name, err)
+        }
+        sources = append(sources, source)
+    }
+
+    for i, a := range demoArticles {
+        source := sources[i%len(sources)]
+        article := &models.Article{
+            SourceID:   source.ID,
+            SourceName: source.Name,
+            Title:      a[0],
+            URL:        fmt.Sprintf("%s/demo/%d-%s", strings.TrimSuffix(source.URL, "/"), i+1, slug(a[0])),
+            Summary:    a[1],
+            Category:   a[2],
+            Language:   nlp.DetectLanguage(a[0]+" "+a[1], ""),
+        }
+        if err := repo.SaveArticle(ctx, article); err != nil {
+            return fmt.Errorf("failed to seed article %q: %w", a[0], err)
+        }
+
+        // An article without a teaser gets a body, and a summary generated from it
+        body, hasBody := demoBodies[a[0]]
+        if hasBody {
+            content := models.ArticleContent{Body: body, Summary: summarize.Summarize(body, summarize.Sentences)}
+            if err := repo.UpdateArticleContent(ctx, article.URL, content); err != nil {
+                return fmt.Errorf("failed to seed content of %q: %w", a[0], err)
+            }
+        }
+        entities := nlp.ExtractEntities(a[0], strings.TrimSpace(a[1]+"\n\n"+body))
+        if err := repo.SetArticleEntities(ctx, article.URL, entities); err != nil {
+            return fmt.Errorf("failed to seed entities of %q: %w", a[0], err)
+        }
+
+        // Rewrite the first headline so the history view has a diff to show
+        if i == 0 {
+            article.Title = "Chipmakers race to ship the first generation of low-power AI accelerators"
+            if err := repo.SaveArticle(ctx, article); err != nil {
+                return fmt.Errorf("failed to seed article %q: %w", a[0], err)
+            }
+        }
+    }
+
+    return nil
+}
+
+// slug turns a title into a lowercase, dash-separated URL segment
+func slug(title string) string {
+    var b strings.Builder
+    dash := false
+    for _, r := range strings.ToLower(title) {
+        if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+            b.WriteRune(r)
+            dash = false
+        } else if !dash && b.Len() > 0 {
+            b.WriteByte('-')
+            dash = true
+        }
+    }
+    return strings.TrimSuffix(b.String(), "-")
+}
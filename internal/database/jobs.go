@@ -0,0 +1,234 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"news-scraper/internal/models"
+)
+
+// Job queue operations
+// The jobs table is a durable work queue: the listing scrape enqueues
+// per-article work and the worker pool leases, acks or fails it.
+// A lease expires on its own, so jobs held by a crashed or restarted
+// process are picked up again instead of being lost.
+
+// EnqueueJob adds a job unless one with the same kind and dedupe key already exists
+// Returns true if a new job was created
+func (r *SQLRepository) EnqueueJob(ctx context.Context, kind, dedupeKey, payload string, maxAttempts int) (bool, error) {
+    query := `INSERT INTO jobs (kind, dedupe_key, payload, status, attempts, max_attempts, run_after, created_at, updated_at)
+              VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?) ` + r.d.insertIgnoreJob
+
+    now := time.Now().UTC()
+    result, err := r.d.exec(ctx, r.db, query,
+        kind, hashKey(dedupeKey), payload, models.JobPending, maxAttempts, now, now, now)
+    if err != nil {
+        return false, err
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return false, err
+    }
+    return rowsAffected == 1, nil
+}
+
+// LeaseJobs claims up to limit runnable jobs for the given lease duration
+// Runnable means pending and due, or leased by someone whose lease has expired.
+// SKIP LOCKED lets several worker processes lease concurrently without
+// handing out the same row twice; SQLite gets the same guarantee from
+// taking the write lock when the transaction begins.
+func (r *SQLRepository) LeaseJobs(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error) {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    now := time.Now().UTC()
+    query := `SELECT id, kind, dedupe_key, payload, status, attempts, max_attempts, COALESCE(last_error, ''), run_after, created_at, updated_at
+              FROM jobs
+              WHERE (status = ? AND run_after <= ?) OR (status = ? AND leased_until < ?)
+              ORDER BY run_after
+              LIMIT ? ` + r.d.lockSkipLocked
+
+    rows, err := r.d.query(ctx, tx, query, models.JobPending, now, models.JobLeased, now, limit)
+    if err != nil {
+        return nil, err
+    }
+
+    var candidates []models.Job
+    for rows.Next() {
+        var j models.Job
+        err := rows.Scan(&j.ID, &j.Kind, &j.DedupeKey, &j.Payload, &j.Status, &j.Attempts, &j.MaxAttempts,
+            &j.LastError, &j.RunAfter, &j.CreatedAt, &j.UpdatedAt)
+        if err != nil {
+            rows.Close()
+            return nil, err
+        }
+        candidates = append(candidates, j)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    var jobs []models.Job
+    for _, j := range candidates {
+        // A job whose lease expired after its last allowed attempt
+        // crashed its worker every time; stop handing it out
+        if j.Status == models.JobLeased && j.Attempts >= j.MaxAttempts {
+            _, err := r.d.exec(ctx, tx, `UPDATE jobs SET status = ?, last_error = ?, lease_token = NULL, leased_until = NULL, updated_at = ? WHERE id = ?`,
+                models.JobDead, "lease expired on final attempt", now, j.ID)
+            if err != nil {
+                return nil, err
+            }
+            continue
+        }
+
+        token, err := newLeaseToken()
+        if err != nil {
+            return nil, err
+        }
+
+        _, err = r.d.exec(ctx, tx, `UPDATE jobs SET status = ?, attempts = attempts + 1, lease_token = ?, leased_until = ?, updated_at = ? WHERE id = ?`,
+            models.JobLeased, token, now.Add(lease), now, j.ID)
+        if err != nil {
+            return nil, err
+        }
+
+        j.Status = models.JobLeased
+        j.Attempts++
+        j.LeaseToken = token
+        j.UpdatedAt = now
+        jobs = append(jobs, j)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return jobs, nil
+}
+
+// AckJob marks a leased job as done
+// The lease token guards against a worker acking a job that was
+// re-leased to someone else after its lease expired.
+func (r *SQLRepository) AckJob(ctx context.Context, job models.Job) error {
+    query := `UPDATE jobs SET status = ?, last_error = NULL, lease_token = NULL, leased_until = NULL, updated_at = ?
+              WHERE id = ? AND lease_token = ?`
+
+    result, err := r.d.exec(ctx, r.db, query, models.JobDone, time.Now().UTC(), job.ID, job.LeaseToken)
+    if err != nil {
+        return err
+    }
+    return checkLeaseHeld(result, job)
+}
+
+// FailJob records a failed attempt
+// The job goes back to pending until retryAt, or to dead once it
+// has used all of its attempts.
+func (r *SQLRepository) FailJob(ctx context.Context, job models.Job, jobErr error, retryAt time.Time) error {
+    status := models.JobPending
+    if job.Attempts >= job.MaxAttempts {
+        status = models.JobDead
+    }
+
+    query := `UPDATE jobs SET status = ?, last_error = ?, run_after = ?, lease_token = NULL, leased_until = NULL, updated_at = ?
+              WHERE id = ? AND lease_token = ?`
+
+    result, err := r.d.exec(ctx, r.db, query,
+        status, truncate(jobErr.Error(), 2000), retryAt.UTC(), time.Now().UTC(), job.ID, job.LeaseToken)
+    if err != nil {
+        return err
+    }
+    return checkLeaseHeld(result, job)
+}
+
+// RescheduleJob hands a leased job back until retryAt without counting the attempt
+// Used when the job couldn't run at all, e.g. while its source's circuit is open.
+func (r *SQLRepository) RescheduleJob(ctx context.Context, job models.Job, retryAt time.Time) error {
+    query := `UPDATE jobs SET status = ?, attempts = attempts - 1, run_after = ?, lease_token = NULL, leased_until = NULL, updated_at = ?
+              WHERE id = ? AND lease_token = ?`
+
+    result, err := r.d.exec(ctx, r.db, query, models.JobPending, retryAt.UTC(), time.Now().UTC(), job.ID, job.LeaseToken)
+    if err != nil {
+        return err
+    }
+    return checkLeaseHeld(result, job)
+}
+
+// GetDeadJobs returns dead-lettered jobs for inspection, newest first
+func (r *SQLRepository) GetDeadJobs(ctx context.Context, limit int) ([]models.Job, error) {
+    query := `SELECT id, kind, dedupe_key, payload, status, attempts, max_attempts, COALESCE(last_error, ''), run_after, created_at, updated_at
+              FROM jobs WHERE status = ? ORDER BY updated_at DESC LIMIT ?`
+
+    rows, err := r.d.query(ctx, r.db, query, models.JobDead, limit)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var jobs []models.Job
+    for rows.Next() {
+        var j models.Job
+        err := rows.Scan(&j.ID, &j.Kind, &j.DedupeKey, &j.Payload, &j.Status, &j.Attempts, &j.MaxAttempts,
+            &j.LastError, &j.RunAfter, &j.CreatedAt, &j.UpdatedAt)
+        if err != nil {
+            return nil, err
+        }
+        jobs = append(jobs, j)
+    }
+    return jobs, rows.Err()
+}
+
+// PurgeDoneJobs deletes completed jobs older than the given age
+// Done rows are kept for a while so the same article isn't fetched
+// again on every scrape of its listing page.
+func (r *SQLRepository) PurgeDoneJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+    query := `DELETE FROM jobs WHERE status = ? AND updated_at < ?`
+
+    result, err := r.d.exec(ctx, r.db, query, models.JobDone, time.Now().UTC().Add(-olderThan))
+    if err != nil {
+        return 0, err
+    }
+    return result.RowsAffected()
+}
+
+func checkLeaseHeld(result sql.Result, job models.Job) error {
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if rowsAffected == 0 {
+        return fmt.Errorf("job %d: lease no longer held", job.ID)
+    }
+    return nil
+}
+
+// hashKey keeps the unique index small regardless of URL length
+func hashKey(key string) string {
+    sum := sha256.Sum256([]byte(key))
+    return hex.EncodeToString(sum[:])
+}
+
+func newLeaseToken() (string, error) {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(b), nil
+}
+
+func truncate(s string, max int) string {
+    s = strings.ToValidUTF8(s, "")
+    if len(s) <= max {
+        return s
+    }
+    return strings.ToValidUTF8(s[:max], "")
+}
@@ -0,0 +1,972 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+)
+
+// MemoryRepository is a thread-safe in-memory Repository
+// It keeps the same semantics as the SQL backends (upsert on canonical URL, cascade
+// on source delete, leased jobs with expiry) and passes the conformance suite.
+// Used for unit tests and the server's --demo mode; nothing survives a restart.
+type MemoryRepository struct {
+    mu sync.RWMutex
+
+    sources      map[int]models.Source
+    nextSourceID int
+
+    articles      map[int]*memArticle
+    articleByKey  map[string]int // by canonical URL
+    articleByURL  map[string]int
+    nextArticleID int
+
+    jobs      map[int64]*memJob
+    jobByKey  map[string]int64
+    nextJobID int64
+
+    revisions      map[int][]models.ArticleRevision
+    nextRevisionID int64
+
+    stories     map[int]string
+    nextStoryID int
+
+    entities map[int][]models.Entity
+
+    runs      []models.ScrapeRun
+    nextRunID int64
+    pages     map[string]models.PageCache // by URL
+
+    archive []models.Article
+}
+
+type memArticle struct {
+    models.Article
+    fetchedAt time.Time
+}
+
+type memJob struct {
+    models.Job
+    leasedUntil time.Time
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+    return &MemoryRepository{
+        sources:      make(map[int]models.Source),
+        articles:     make(map[int]*memArticle),
+        articleByKey: make(map[string]int),
+        articleByURL: make(map[string]int),
+        jobs:         make(map[int64]*memJob),
+        jobByKey:     make(map[string]int64),
+        revisions:    make(map[int][]models.ArticleRevision),
+        stories:      make(map[int]string),
+        entities:     make(map[int][]models.Entity),
+        pages:        make(map[string]models.PageCache),
+    }
+}
+
+// CreateSource adds a source and sets its ID
+func (m *MemoryRepository) CreateSource(ctx context.Context, source *models.Source) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, s := range m.sources {
+        if s.URL == source.URL {
+            return fmt.Errorf("source with url %s already exists", source.URL)
+        }
+    }
+
+    if source.DefaultCategory == "" {
+        source.DefaultCategory = "general"
+    }
+
+    m.nextSourceID++
+    now := time.Now().UTC()
+    source.ID = m.nextSourceID
+    source.CreatedAt = now
+    source.UpdatedAt = now
+    stored := *source
+    stored.Headers = maps.Clone(source.Headers)
+    m.sources[source.ID] = stored
+    return nil
+}
+
+// DeleteSource removes a source and its articles
+func (m *MemoryRepository) DeleteSource(ctx context.Context, id int) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.sources, id)
+    for articleID, a := range m.articles {
+        if a.SourceID == id {
+            m.deleteArticleLocked(articleID)
+        }
+    }
+    m.runs = slices.DeleteFunc(m.runs, func(r models.ScrapeRun) bool { return r.SourceID == id })
+    maps.DeleteFunc(m.pages, func(_ string, p models.PageCache) bool { return p.SourceID == id })
+    return nil
+}
+
+// GetActiveSources retrieves all active sources ordered by name
+func (m *MemoryRepository) GetActiveSources(ctx context.Context) ([]models.Source, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var sources []models.Source
+    for _, s := range m.sources {
+        if s.Active {
+            sources = append(sources, s)
+        }
+    }
+    sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+    return sources, nil
+}
+
+// GetSources retrieves every source ordered by name
+func (m *MemoryRepository) GetSources(ctx context.Context) ([]models.Source, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    sources := make([]models.Source, 0, len(m.sources))
+    for _, s := range m.sources {
+        sources = append(sources, s)
+    }
+    sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+    return sources, nil
+}
+
+// GetSourceByID returns nil if the source doesn't exist
+func (m *MemoryRepository) GetSourceByID(ctx context.Context, id int) (*models.Source, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    s, ok := m.sources[id]
+    if !ok {
+        return nil, nil
+    }
+    return &s, nil
+}
+
+// SaveArticle inserts an article or updates the one with the same canonical URL
+func (m *MemoryRepository) SaveArticle(ctx context.Context, article *models.Article) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, ok := m.sources[article.SourceID]; !ok {
+        return fmt.Errorf("source %d does not exist", article.SourceID)
+    }
+
+    if article.CanonicalURL == "" {
+        article.CanonicalURL = article.URL
+    }
+    now := time.Now().UTC()
+    if id, ok := m.storedArticleLocked(*article); ok {
+        a := m.articles[id]
+        article.CanonicalURL = a.CanonicalURL
+        a.Title = article.Title
+        setListingSummary(a, article.Summary)
+        a.Category = article.Category
+        if a.Language == "" {
+            a.Language = article.Language
+        }
+        a.ScrapedAt = now
+        scoreSentiment(a)
+        m.recordRevisionLocked(a)
+        return nil
+    }
+
+    m.nextArticleID++
+    a := &memArticle{Article: *article}
+    a.ID = m.nextArticleID
+    a.ScrapedAt = now
+    a.CreatedAt = now
+    m.articles[a.ID] = a
+    m.articleByKey[a.CanonicalURL] = a.ID
+    m.articleByURL[a.URL] = a.ID
+    scoreSentiment(a)
+    m.recordRevisionLocked(a)
+    return nil
+}
+
+// storedArticleLocked finds an article by canonical URL, or by URL when
+// the worker re-keyed it to the page's rel=canonical
+func (m *MemoryRepository) storedArticleLocked(article models.Article) (int, bool) {
+    if id, ok := m.articleByKey[article.CanonicalURL]; ok {
+        return id, true
+    }
+    id, ok := m.articleByURL[article.URL]
+    return id, ok
+}
+
+// SaveArticles upserts a batch atomically and counts what changed
+// The context is checked before anything is written, so a cancelled
+// run leaves the repository untouched like the SQL rollback does.
+func (m *MemoryRepository) SaveArticles(ctx context.Context, articles []models.Article) (models.SaveResult, error) {
+    var result models.SaveResult
+    if err := ctx.Err(); err != nil {
+        return result, err
+    }
+
+    articles = dedupeByKey(articles)
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, a := range articles {
+        if _, ok := m.sources[a.SourceID]; !ok {
+            return models.SaveResult{}, fmt.Errorf("source %d does not exist", a.SourceID)
+        }
+    }
+
+    now := time.Now().UTC()
+    for _, article := range articles {
+        if id, ok := m.storedArticleLocked(article); ok {
+            a := m.articles[id]
+            if a.Title != article.Title || (article.Summary != "" && a.Summary != article.Summary) || a.Category != article.Category {
+                result.Updated++
+            } else {
+                result.Unchanged++
+            }
+            a.Title = article.Title
+            setListingSummary(a, article.Summary)
+            a.Category = article.Category
+            if a.Language == "" {
+                a.Language = article.Language
+            }
+            a.ScrapedAt = now
+            scoreSentiment(a)
+            m.recordRevisionLocked(a)
+            continue
+        }
+
+        m.nextArticleID++
+        a := &memArticle{Article: article}
+        a.ID = m.nextArticleID
+        a.ScrapedAt = now
+        a.CreatedAt = now
+        m.articles[a.ID] = a
+        m.articleByKey[a.CanonicalURL] = a.ID
+        m.articleByURL[a.URL] = a.ID
+        scoreSentiment(a)
+        m.recordRevisionLocked(a)
+        result.Inserted++
+    }
+    return result, nil
+}
+
+// UpdateArticleContent stores fetched body and metadata; see pickSummary for the summary
+func (m *MemoryRepository) UpdateArticleContent(ctx context.Context, canonicalURL string, content models.ArticleContent) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    id, ok := m.articleByKey[canonicalURL]
+    if !ok {
+        return nil
+    }
+
+    a := m.articles[id]
+    a.Body = content.Body
+    a.ImageURL = content.ImageURL
+    a.Author = content.Author
+    a.PublishedAt = content.PublishedAt
+    a.fetchedAt = time.Now().UTC()
+    a.Summary, a.SummaryGenerated = pickSummary(a.Article, content)
+    if content.Language != "" {
+        a.Language = content.Language
+    }
+    scoreSentiment(a)
+    m.recordRevisionLocked(a)
+    return nil
+}
+
+// scoreSentiment scores the stored title and summary, like articleSentiment
+func scoreSentiment(a *memArticle) {
+    score := articleSentiment(a.Title, a.Summary, a.Language)
+    a.Sentiment = &score
+}
+
+// setListingSummary applies a scraped teaser; an empty one keeps the stored summary
+func setListingSummary(a *memArticle, summary string) {
+    if summary != "" {
+        a.Summary = summary
+        a.SummaryGenerated = false
+    }
+}
+
+// GetUnclusteredArticles returns recent articles without a story, oldest first
+func (m *MemoryRepository) GetUnclusteredArticles(ctx context.Context, since time.Time) ([]models.Article, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, a := range m.articles {
+        if a.StoryID == 0 && !a.ScrapedAt.Before(since) {
+            articles = append(articles, unclustered(a))
+        }
+    }
+    sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+    return articles, nil
+}
+
+// GetUnclusteredArticlesByURL returns the given articles that have no story yet, oldest first
+func (m *MemoryRepository) GetUnclusteredArticlesByURL(ctx context.Context, canonicalURLs []string) ([]models.Article, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, url := range canonicalURLs {
+        if id, ok := m.articleByKey[url]; ok && m.articles[id].StoryID == 0 {
+            articles = append(articles, unclustered(m.articles[id]))
+        }
+    }
+    sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+    return slices.CompactFunc(articles, func(a, b models.Article) bool { return a.ID == b.ID }), nil
+}
+
+// unclustered copies what GetUnclusteredArticles lists
+func unclustered(a *memArticle) models.Article {
+    return models.Article{
+        ID: a.ID, SourceID: a.SourceID, SourceName: a.SourceName,
+        Title: a.Title, URL: a.URL, Summary: a.Summary, Fingerprint: a.Fingerprint, ScrapedAt: a.ScrapedAt,
+    }
+}
+
+// GetStoryFingerprints returns the story and fingerprint of recent clustered articles
+func (m *MemoryRepository) GetStoryFingerprints(ctx context.Context, since time.Time) ([]models.Article, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, a := range m.articles {
+        if a.StoryID != 0 && !a.ScrapedAt.Before(since) {
+            articles = append(articles, models.Article{
+                ID: a.ID, SourceID: a.SourceID, StoryID: a.StoryID, Fingerprint: a.Fingerprint,
+            })
+        }
+    }
+    return articles, nil
+}
+
+// CreateStory starts a new story and returns its ID
+func (m *MemoryRepository) CreateStory(ctx context.Context, title string) (int, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.nextStoryID++
+    m.stories[m.nextStoryID] = title
+    return m.nextStoryID, nil
+}
+
+// SetArticleStory records an article's story
+func (m *MemoryRepository) SetArticleStory(ctx context.Context, articleID, storyID int) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if a, ok := m.articles[articleID]; ok {
+        a.StoryID = storyID
+    }
+    return nil
+}
+
+// GetArticlesCreatedSince returns articles first seen since the given time, oldest first
+func (m *MemoryRepository) GetArticlesCreatedSince(ctx context.Context, since time.Time) ([]models.Article, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, a := range m.articles {
+        if !a.CreatedAt.Before(since) {
+            articles = append(articles, models.Article{
+                ID: a.ID, SourceID: a.SourceID, SourceName: a.SourceName,
+                Title: a.Title, Summary: a.Summary, Category: a.Category, Sentiment: a.Sentiment, CreatedAt: a.CreatedAt,
+            })
+        }
+    }
+    sort.Slice(articles, func(i, j int) bool {
+        if articles[i].CreatedAt.Equal(articles[j].CreatedAt) {
+            return articles[i].ID < articles[j].ID
+        }
+        return articles[i].CreatedAt.Before(articles[j].CreatedAt)
+    })
+    return articles, nil
+}
+
+// GetArticleURLs returns the id, source, URL and canonical URL of every article, oldest first
+func (m *MemoryRepository) GetArticleURLs(ctx context.Context) ([]models.Article, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, a := range m.articles {
+        articles = append(articles, models.Article{ID: a.ID, SourceID: a.SourceID, URL: a.URL, CanonicalURL: a.CanonicalURL})
+    }
+    sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+    return articles, nil
+}
+
+// MergeArticleURL re-keys an article, or folds it into the article already keyed to
+func (m *MemoryRepository) MergeArticleURL(ctx context.Context, from, to string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    fromID, ok := m.articleByKey[from]
+    if !ok || from == to {
+        return nil
+    }
+
+    toID, exists := m.articleByKey[to]
+    if !exists {
+        a := m.articles[fromID]
+        delete(m.articleByKey, from)
+        a.CanonicalURL = to
+        m.articleByKey[to] = fromID
+        return nil
+    }
+
+    merged := append(m.revisions[toID], m.revisions[fromID]...)
+    for i := range merged {
+        merged[i].ArticleID = toID
+    }
+    sort.Slice(merged, func(i, j int) bool { return merged[i].ID < merged[j].ID })
+    m.revisions[toID] = merged
+    m.deleteArticleLocked(fromID)
+    return nil
+}
+
+// SetArticleEntities replaces the entities of the article with the given canonical URL
+func (m *MemoryRepository) SetArticleEntities(ctx context.Context, canonicalURL string, entities []models.Entity) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    id, ok := m.articleByKey[canonicalURL]
+    if !ok {
+        return nil
+    }
+    m.entities[id] = append([]models.Entity(nil), entities...)
+    return nil
+}
+
+// GetArticleEntities returns the entities of each article, most mentioned first
+func (m *MemoryRepository) GetArticleEntities(ctx context.Context, articleIDs []int) (map[int][]models.Entity, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    entities := make(map[int][]models.Entity)
+    for _, id := range articleIDs {
+        if list, ok := m.entities[id]; ok && len(list) > 0 {
+            list = append([]models.Entity(nil), list...)
+            sort.SliceStable(list, func(i, j int) bool {
+                if list[i].Mentions != list[j].Mentions {
+                    return list[i].Mentions > list[j].Mentions
+                }
+                return list[i].Name < list[j].Name
+            })
+            entities[id] = list
+        }
+    }
+    return entities, nil
+}
+
+// GetSentimentCounts groups the articles first seen since the given time by source, category and UTC day
+func (m *MemoryRepository) GetSentimentCounts(ctx context.Context, since time.Time, entity, query string) ([]models.SentimentCount, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    query = strings.ToLower(query)
+    groups := make(map[[3]string]*models.SentimentCount)
+    for _, a := range m.articles {
+        if a.CreatedAt.Before(since) {
+            continue
+        }
+        if entity != "" && !slices.ContainsFunc(m.entities[a.ID], func(e models.Entity) bool { return e.NormalizedName == entity }) {
+            continue
+        }
+        if query != "" && !strings.Contains(strings.ToLower(a.Title), query) && !strings.Contains(strings.ToLower(a.Summary), query) {
+            continue
+        }
+
+        key := [3]string{a.SourceName, a.Category, a.CreatedAt.UTC().Format("2006-01-02")}
+        c, ok := groups[key]
+        if !ok {
+            c = &models.SentimentCount{SourceName: key[0], Category: key[1], Day: key[2]}
+            groups[key] = c
+        }
+        c.Articles++
+        if a.Sentiment == nil {
+            continue
+        }
+        c.Scored++
+        c.Sum += *a.Sentiment
+        switch {
+        case *a.Sentiment >= nlp.PositiveThreshold:
+            c.Positive++
+        case *a.Sentiment <= nlp.NegativeThreshold:
+            c.Negative++
+        default:
+            c.Neutral++
+        }
+    }
+
+    counts := make([]models.SentimentCount, 0, len(groups))
+    for _, c := range groups {
+        counts = append(counts, *c)
+    }
+    return counts, nil
+}
+
+// GetArticlesByEntity retrieves the most recent articles mentioning an entity
+func (m *MemoryRepository) GetArticlesByEntity(ctx context.Context, normalizedName string, limit int) ([]models.Article, error) {
+    return m.listArticles(func(a *memArticle) bool {
+        for _, e := range m.entities[a.ID] {
+            if e.NormalizedName == normalizedName {
+                return true
+            }
+        }
+        return false
+    }, limit, false), nil
+}
+
+// GetArticleRevisions returns an article's revisions, oldest first
+func (m *MemoryRepository) GetArticleRevisions(ctx context.Context, articleID int) ([]models.ArticleRevision, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    return append([]models.ArticleRevision(nil), m.revisions[articleID]...), nil
+}
+
+// recordRevisionLocked adds a revision and refreshes the fingerprint if the article's content changed
+func (m *MemoryRepository) recordRevisionLocked(a *memArticle) {
+    hash := contentHash(a.Title, a.Summary, a.Body)
+    revisions := m.revisions[a.ID]
+    if len(revisions) > 0 && revisions[len(revisions)-1].ContentHash == hash {
+        return
+    }
+    a.Fingerprint = articleFingerprint(a.Title, a.Summary, a.Body)
+
+    m.nextRevisionID++
+    m.revisions[a.ID] = append(revisions, models.ArticleRevision{
+        ID:          m.nextRevisionID,
+        ArticleID:   a.ID,
+        Title:       a.Title,
+        Summary:     a.Summary,
+        Body:        a.Body,
+        ContentHash: hash,
+        CreatedAt:   time.Now().UTC(),
+    })
+}
+
+// GetRecentArticles retrieves the most recent articles
+func (m *MemoryRepository) GetRecentArticles(ctx context.Context, limit int) ([]models.Article, error) {
+    return m.listArticles(func(a *memArticle) bool { return true }, limit, false), nil
+}
+
+// GetArticlesByCategory retrieves the most recent articles in a category
+func (m *MemoryRepository) GetArticlesByCategory(ctx context.Context, category string, limit int) ([]models.Article, error) {
+    return m.listArticles(func(a *memArticle) bool { return a.Category == category }, limit, false), nil
+}
+
+// GetArticlesByLanguage retrieves the most recent articles in a language
+func (m *MemoryRepository) GetArticlesByLanguage(ctx context.Context, language string, limit int) ([]models.Article, error) {
+    return m.listArticles(func(a *memArticle) bool { return a.Language == language }, limit, false), nil
+}
+
+// GetArticlesBySource retrieves the 50 most recent articles of a source
+func (m *MemoryRepository) GetArticlesBySource(ctx context.Context, sourceID int) ([]models.Article, error) {
+    return m.listArticles(func(a *memArticle) bool { return a.SourceID == sourceID }, 50, false), nil
+}
+
+// GetArticleURLsBySource returns the URL and canonical URL of every stored article of a source
+func (m *MemoryRepository) GetArticleURLsBySource(ctx context.Context, sourceID int) ([]models.Article, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, a := range m.articles {
+        if a.SourceID == sourceID {
+            articles = append(articles, models.Article{URL: a.URL, CanonicalURL: a.CanonicalURL})
+        }
+    }
+    return articles, nil
+}
+
+// GetCategories returns the distinct categories in alphabetical order
+func (m *MemoryRepository) GetCategories(ctx context.Context) ([]string, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    seen := make(map[string]bool)
+    var categories []string
+    for _, a := range m.articles {
+        if a.Category != "" && !seen[a.Category] {
+            seen[a.Category] = true
+            categories = append(categories, a.Category)
+        }
+    }
+    sort.Strings(categories)
+    return categories, nil
+}
+
+// GetLanguages returns the distinct detected languages in alphabetical order
+func (m *MemoryRepository) GetLanguages(ctx context.Context) ([]string, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    seen := make(map[string]bool)
+    var languages []string
+    for _, a := range m.articles {
+        if a.Language != "" && !seen[a.Language] {
+            seen[a.Language] = true
+            languages = append(languages, a.Language)
+        }
+    }
+    sort.Strings(languages)
+    return languages, nil
+}
+
+// listArticles returns matching articles newest first
+// Listing queries leave out the body, like the SQL backends do.
+func (m *MemoryRepository) listArticles(match func(*memArticle) bool, limit int, withBody bool) []models.Article {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, a := range m.articles {
+        if !match(a) {
+            continue
+        }
+        article := a.Article
+        if !withBody {
+            article.Body = ""
+            article.ImageURL = ""
+            article.Author = ""
+            article.PublishedAt = time.Time{}
+        }
+        articles = append(articles, article)
+    }
+    sortNewestFirst(articles)
+
+    if limit >= 0 && len(articles) > limit {
+        articles = articles[:limit]
+    }
+    return articles
+}
+
+// EnqueueJob adds a job unless one with the same kind and dedupe key exists
+func (m *MemoryRepository) EnqueueJob(ctx context.Context, kind, dedupeKey, payload string, maxAttempts int) (bool, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key := kind + "\x00" + hashKey(dedupeKey)
+    if _, ok := m.jobByKey[key]; ok {
+        return false, nil
+    }
+
+    m.nextJobID++
+    now := time.Now().UTC()
+    m.jobs[m.nextJobID] = &memJob{Job: models.Job{
+        ID:          m.nextJobID,
+        Kind:        kind,
+        DedupeKey:   hashKey(dedupeKey),
+        Payload:     payload,
+        Status:      models.JobPending,
+        MaxAttempts: maxAttempts,
+        RunAfter:    now,
+        CreatedAt:   now,
+        UpdatedAt:   now,
+    }}
+    m.jobByKey[key] = m.nextJobID
+    return true, nil
+}
+
+// LeaseJobs claims up to limit due jobs, including ones whose lease expired
+func (m *MemoryRepository) LeaseJobs(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    now := time.Now().UTC()
+    var runnable []*memJob
+    for _, j := range m.jobs {
+        due := j.Status == models.JobPending && !j.RunAfter.After(now)
+        expired := j.Status == models.JobLeased && j.leasedUntil.Before(now)
+        if due || expired {
+            runnable = append(runnable, j)
+        }
+    }
+    sort.Slice(runnable, func(i, k int) bool {
+        if runnable[i].RunAfter.Equal(runnable[k].RunAfter) {
+            return runnable[i].ID < runnable[k].ID
+        }
+        return runnable[i].RunAfter.Before(runnable[k].RunAfter)
+    })
+
+    var jobs []models.Job
+    for _, j := range runnable {
+        if len(jobs) >= limit {
+            break
+        }
+
+        if j.Status == models.JobLeased && j.Attempts >= j.MaxAttempts {
+            j.Status = models.JobDead
+            j.LastError = "lease expired on final attempt"
+            j.LeaseToken = ""
+            j.UpdatedAt = now
+            continue
+        }
+
+        token, err := newLeaseToken()
+        if err != nil {
+            return nil, err
+        }
+
+        j.Status = models.JobLeased
+        j.Attempts++
+        j.LeaseToken = token
+        j.leasedUntil = now.Add(lease)
+        j.UpdatedAt = now
+        jobs = append(jobs, j.Job)
+    }
+    return jobs, nil
+}
+
+// AckJob marks a leased job as done
+func (m *MemoryRepository) AckJob(ctx context.Context, job models.Job) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    j, ok := m.jobs[job.ID]
+    if !ok || j.LeaseToken == "" || j.LeaseToken != job.LeaseToken {
+        return fmt.Errorf("job %d: lease no longer held", job.ID)
+    }
+
+    j.Status = models.JobDone
+    j.LastError = ""
+    j.LeaseToken = ""
+    j.UpdatedAt = time.Now().UTC()
+    return nil
+}
+
+// FailJob records a failed attempt and schedules a retry or dead-letters the job
+func (m *MemoryRepository) FailJob(ctx context.Context, job models.Job, jobErr error, retryAt time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    j, ok := m.jobs[job.ID]
+    if !ok || j.LeaseToken == "" || j.LeaseToken != job.LeaseToken {
+        return fmt.Errorf("job %d: lease no longer held", job.ID)
+    }
+
+    j.Status = models.JobPending
+    if j.Attempts >= j.MaxAttempts {
+        j.Status = models.JobDead
+    }
+    j.LastError = truncate(jobErr.Error(), 2000)
+    j.RunAfter = retryAt.UTC()
+    j.LeaseToken = ""
+    j.UpdatedAt = time.Now().UTC()
+    return nil
+}
+
+// RescheduleJob hands a leased job back until retryAt without counting the attempt
+func (m *MemoryRepository) RescheduleJob(ctx context.Context, job models.Job, retryAt time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    j, ok := m.jobs[job.ID]
+    if !ok || j.LeaseToken == "" || j.LeaseToken != job.LeaseToken {
+        return fmt.Errorf("job %d: lease no longer held", job.ID)
+    }
+
+    j.Status = models.JobPending
+    j.Attempts--
+    j.RunAfter = retryAt.UTC()
+    j.LeaseToken = ""
+    j.UpdatedAt = time.Now().UTC()
+    return nil
+}
+
+// GetDeadJobs returns dead-lettered jobs, newest first
+func (m *MemoryRepository) GetDeadJobs(ctx context.Context, limit int) ([]models.Job, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var jobs []models.Job
+    for _, j := range m.jobs {
+        if j.Status == models.JobDead {
+            job := j.Job
+            job.LeaseToken = ""
+            jobs = append(jobs, job)
+        }
+    }
+    sort.Slice(jobs, func(i, k int) bool { return jobs[i].UpdatedAt.After(jobs[k].UpdatedAt) })
+
+    if len(jobs) > limit {
+        jobs = jobs[:limit]
+    }
+    return jobs, nil
+}
+
+// PurgeDoneJobs deletes completed jobs older than the given age
+func (m *MemoryRepository) PurgeDoneJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    cutoff := time.Now().UTC().Add(-olderThan)
+    var purged int64
+    for id, j := range m.jobs {
+        if j.Status == models.JobDone && j.UpdatedAt.Before(cutoff) {
+            delete(m.jobs, id)
+            delete(m.jobByKey, j.Kind+"\x00"+j.DedupeKey)
+            purged++
+        }
+    }
+    return purged, nil
+}
+
+// GetRetentionCandidates returns every article newest first, without content
+func (m *MemoryRepository) GetRetentionCandidates(ctx context.Context) ([]models.Article, error) {
+    return m.listArticles(func(a *memArticle) bool { return true }, -1, false), nil
+}
+
+// GetArticlesByIDs returns full article rows ordered by ID
+func (m *MemoryRepository) GetArticlesByIDs(ctx context.Context, ids []int) ([]models.Article, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var articles []models.Article
+    for _, id := range ids {
+        if a, ok := m.articles[id]; ok {
+            articles = append(articles, a.Article)
+        }
+    }
+    sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+    return articles, nil
+}
+
+// ArchiveArticles moves articles into the in-memory archive
+func (m *MemoryRepository) ArchiveArticles(ctx context.Context, ids []int) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var archived int64
+    for _, id := range ids {
+        if a, ok := m.articles[id]; ok {
+            m.archive = append(m.archive, a.Article)
+            m.deleteArticleLocked(id)
+            archived++
+        }
+    }
+    return archived, nil
+}
+
+// DeleteArticles removes articles by ID
+func (m *MemoryRepository) DeleteArticles(ctx context.Context, ids []int) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var deleted int64
+    for _, id := range ids {
+        if _, ok := m.articles[id]; ok {
+            m.deleteArticleLocked(id)
+            deleted++
+        }
+    }
+    return deleted, nil
+}
+
+// RecordScrapeRun stores a finished run and sets its ID
+func (m *MemoryRepository) RecordScrapeRun(ctx context.Context, run *models.ScrapeRun) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.nextRunID++
+    run.ID = m.nextRunID
+    stored := *run
+    stored.BlockedURLs = slices.Clone(run.BlockedURLs)
+    m.runs = append(m.runs, stored)
+    return nil
+}
+
+// GetScrapeRuns returns the most recent runs, of one source or of all when sourceID is 0
+func (m *MemoryRepository) GetScrapeRuns(ctx context.Context, sourceID, limit int) ([]models.ScrapeRun, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var runs []models.ScrapeRun
+    for _, r := range m.runs {
+        if sourceID == 0 || r.SourceID == sourceID {
+            r.BlockedURLs = slices.Clone(r.BlockedURLs)
+            runs = append(runs, r)
+        }
+    }
+    sort.Slice(runs, func(i, j int) bool {
+        if runs[i].StartedAt.Equal(runs[j].StartedAt) {
+            return runs[i].ID > runs[j].ID
+        }
+        return runs[i].StartedAt.After(runs[j].StartedAt)
+    })
+    if len(runs) > limit {
+        runs = runs[:limit]
+    }
+    return runs, nil
+}
+
+// PurgeScrapeRuns deletes runs started before the given age
+func (m *MemoryRepository) PurgeScrapeRuns(ctx context.Context, olderThan time.Duration) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    cutoff := time.Now().UTC().Add(-olderThan)
+    before := len(m.runs)
+    m.runs = slices.DeleteFunc(m.runs, func(r models.ScrapeRun) bool { return r.StartedAt.Before(cutoff) })
+    return int64(before - len(m.runs)), nil
+}
+
+// GetPageCache returns the stored validators of a page, or nil if it was never fetched
+func (m *MemoryRepository) GetPageCache(ctx context.Context, url string) (*models.PageCache, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    page, ok := m.pages[url]
+    if !ok {
+        return nil, nil
+    }
+    return &page, nil
+}
+
+// SavePageCache stores the validators of a page, replacing the previous ones
+func (m *MemoryRepository) SavePageCache(ctx context.Context, page *models.PageCache) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.pages[page.URL] = *page
+    return nil
+}
+
+func (m *MemoryRepository) deleteArticleLocked(id int) {
+    if a, ok := m.articles[id]; ok {
+        delete(m.articleByKey, a.CanonicalURL)
+        delete(m.articleByURL, a.URL)
+        delete(m.articles, id)
+        delete(m.revisions, id)
+        delete(m.entities, id)
+    }
+}
+
+// sortNewestFirst orders by scraped_at, then ID, descending
+func sortNewestFirst(articles []models.Article) {
+    sort.Slice(articles, func(i, j int) bool {
+        if articles[i].ScrapedAt.Equal(articles[j].ScrapedAt) {
+            return articles[i].ID > articles[j].ID
+        }
+        return articles[i].ScrapedAt.After(articles[j].ScrapedAt)
+    })
+}
@@ -0,0 +1,358 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrSchemaOutOfDate is returned by CheckCurrent when migrations are pending
+var ErrSchemaOutOfDate = errors.New("database schema is out of date")
+
+// ErrDirty is returned when a migration was interrupted partway on a
+// database without transactional DDL; finish or undo it by hand, then
+// record the resulting version with Force
+var ErrDirty = errors.New("migration was interrupted partway")
+
+// migrationFile matches NNN_description.up.sql / NNN_description.down.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change
+type Migration struct {
+    Version int
+    Name    string
+    Up      string
+    Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+    Version   int
+    Name      string
+    Applied   bool
+    AppliedAt time.Time
+    Dirty     bool // started but not finished, see ErrDirty
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+    at    time.Time
+    dirty bool
+}
+
+// Migrator applies versioned migrations and records them in schema_migrations
+type Migrator struct {
+    db         *sql.DB
+    d          dialect
+    migrations []Migration
+}
+
+// NewMigrator loads the driver's migrations from fsys (normally migrations.Files)
+// Each driver has its own directory of migrations with the same versions.
+func NewMigrator(db *sql.DB, fsys fs.FS, driver string) (*Migrator, error) {
+    d, err := dialectFor(driver)
+    if err != nil {
+        return nil, err
+    }
+
+    fsys, err = fs.Sub(fsys, d.name)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read migrations: %w", err)
+    }
+
+    entries, err := fs.ReadDir(fsys, ".")
+    if err != nil {
+        return nil, fmt.Errorf("failed to read migrations: %w", err)
+    }
+
+    byVersion := make(map[int]*Migration)
+    for _, entry := range entries {
+        match := migrationFile.FindStringSubmatch(entry.Name())
+        if match == nil {
+            continue
+        }
+
+        version, _ := strconv.Atoi(match[1])
+        data, err := fs.ReadFile(fsys, entry.Name())
+        if err != nil {
+            return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+        }
+
+        m, ok := byVersion[version]
+        if !ok {
+            m = &Migration{Version: version, Name: match[2]}
+            byVersion[version] = m
+        } else if m.Name != match[2] {
+            return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, match[2])
+        }
+
+        if match[3] == "up" {
+            m.Up = string(data)
+        } else {
+            m.Down = string(data)
+        }
+    }
+
+    var migrations []Migration
+    for _, m := range byVersion {
+        if m.Up == "" {
+            return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+        }
+        migrations = append(migrations, *m)
+    }
+    sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+    return &Migrator{db: db, d: d, migrations: migrations}, nil
+}
+
+// Up applies all pending migrations in order and returns the ones applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+    applied, err := m.appliedVersions(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := checkDirty(applied); err != nil {
+        return nil, err
+    }
+
+    var done []Migration
+    for _, mig := range m.migrations {
+        if _, ok := applied[mig.Version]; ok {
+            continue
+        }
+
+        err := m.apply(ctx, mig.Up,
+            func(q querier) error {
+                _, err := m.d.exec(ctx, q, `INSERT INTO schema_migrations (version, name, applied_at, dirty) VALUES (?, ?, ?, ?)`,
+                    mig.Version, mig.Name, time.Now().UTC(), !m.d.transactionalDDL)
+                return err
+            },
+            func(q querier) error {
+                _, err := m.d.exec(ctx, q, `UPDATE schema_migrations SET dirty = ? WHERE version = ?`, false, mig.Version)
+                return err
+            })
+        if err != nil {
+            return done, fmt.Errorf("migration %d_%s failed: %w", mig.Version, mig.Name, err)
+        }
+        done = append(done, mig)
+    }
+
+    return done, nil
+}
+
+// Down reverts the latest steps applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+    applied, err := m.appliedVersions(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := checkDirty(applied); err != nil {
+        return nil, err
+    }
+
+    var done []Migration
+    for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+        mig := m.migrations[i]
+        if _, ok := applied[mig.Version]; !ok {
+            continue
+        }
+        if mig.Down == "" {
+            return done, fmt.Errorf("migration %d_%s has no down file", mig.Version, mig.Name)
+        }
+
+        err := m.apply(ctx, mig.Down,
+            func(q querier) error {
+                if m.d.transactionalDDL {
+                    return nil
+                }
+                _, err := m.d.exec(ctx, q, `UPDATE schema_migrations SET dirty = ? WHERE version = ?`, true, mig.Version)
+                return err
+            },
+            func(q querier) error {
+                _, err := m.d.exec(ctx, q, `DELETE FROM schema_migrations WHERE version = ?`, mig.Version)
+                return err
+            })
+        if err != nil {
+            return done, fmt.Errorf("reverting migration %d_%s failed: %w", mig.Version, mig.Name, err)
+        }
+        done = append(done, mig)
+    }
+
+    return done, nil
+}
+
+// Force marks every migration up to version as applied without running it
+// and every later one as not applied, and clears the dirty flag. It is the
+// escape hatch for a migration that failed halfway and was finished by hand.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+    if err := m.ensureTable(ctx); err != nil {
+        return err
+    }
+
+    tx, err := m.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := m.d.exec(ctx, tx, `DELETE FROM schema_migrations`); err != nil {
+        return err
+    }
+
+    now := time.Now().UTC()
+    for _, mig := range m.migrations {
+        if mig.Version > version {
+            break
+        }
+        _, err := m.d.exec(ctx, tx, `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+            mig.Version, mig.Name, now)
+        if err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+    applied, err := m.appliedVersions(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    statuses := make([]MigrationStatus, 0, len(m.migrations))
+    for _, mig := range m.migrations {
+        row, ok := applied[mig.Version]
+        statuses = append(statuses, MigrationStatus{
+            Version:   mig.Version,
+            Name:      mig.Name,
+            Applied:   ok && !row.dirty,
+            AppliedAt: row.at,
+            Dirty:     row.dirty,
+        })
+    }
+    return statuses, nil
+}
+
+// CheckCurrent returns ErrSchemaOutOfDate if any migration is pending
+// The server calls this at startup instead of creating tables itself.
+func (m *Migrator) CheckCurrent(ctx context.Context) error {
+    statuses, err := m.Status(ctx)
+    if err != nil {
+        return err
+    }
+
+    var pending []string
+    for _, s := range statuses {
+        if !s.Applied {
+            pending = append(pending, fmt.Sprintf("%03d_%s", s.Version, s.Name))
+        }
+    }
+
+    if len(pending) > 0 {
+        return fmt.Errorf("%w: %d pending migration(s) %v", ErrSchemaOutOfDate, len(pending), pending)
+    }
+    return nil
+}
+
+// apply runs a migration script between two changes to schema_migrations
+// With transactional DDL all three commit together. MySQL commits each DDL
+// statement on its own, so before runs first (marking the version dirty)
+// and after only once the script succeeded; a crash in between leaves the
+// dirty row for Force to clear.
+// Scripts may contain several statements and (on MySQL) session
+// variables, which relies on multiStatements=true in the DSN.
+func (m *Migrator) apply(ctx context.Context, script string, before, after func(querier) error) error {
+    if m.d.transactionalDDL {
+        tx, err := m.db.BeginTx(ctx, nil)
+        if err != nil {
+            return err
+        }
+        defer tx.Rollback()
+
+        if err := before(tx); err != nil {
+            return err
+        }
+        if _, err := tx.ExecContext(ctx, script); err != nil {
+            return err
+        }
+        if err := after(tx); err != nil {
+            return err
+        }
+        return tx.Commit()
+    }
+
+    conn, err := m.db.Conn(ctx)
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+
+    if err := before(conn); err != nil {
+        return err
+    }
+    if _, err := conn.ExecContext(ctx, script); err != nil {
+        return err
+    }
+    return after(conn)
+}
+
+// ensureTable creates schema_migrations, or adds the dirty column to one
+// created before it existed
+func (m *Migrator) ensureTable(ctx context.Context) error {
+    if _, err := m.d.exec(ctx, m.db, m.d.migrationsTable); err != nil {
+        return err
+    }
+
+    var n int
+    if err := m.d.queryRow(ctx, m.db, m.d.columnExists, "schema_migrations", "dirty").Scan(&n); err != nil {
+        return err
+    }
+    if n == 0 {
+        _, err := m.d.exec(ctx, m.db, `ALTER TABLE schema_migrations ADD COLUMN dirty BOOLEAN NOT NULL DEFAULT FALSE`)
+        return err
+    }
+    return nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]appliedMigration, error) {
+    if err := m.ensureTable(ctx); err != nil {
+        return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+    }
+
+    rows, err := m.d.query(ctx, m.db, `SELECT version, applied_at, dirty FROM schema_migrations`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    applied := make(map[int]appliedMigration)
+    for rows.Next() {
+        var version int
+        var row appliedMigration
+        if err := rows.Scan(&version, &row.at, &row.dirty); err != nil {
+            return nil, err
+        }
+        applied[version] = row
+    }
+    return applied, rows.Err()
+}
+
+// checkDirty returns ErrDirty if a migration was left partway
+func checkDirty(applied map[int]appliedMigration) error {
+    for version, row := range applied {
+        if row.dirty {
+            return fmt.Errorf("%w: version %d; fix the schema by hand, then run migrate force", ErrDirty, version)
+        }
+    }
+    return nil
+}
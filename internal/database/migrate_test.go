@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// brokenMigrations has a second migration whose second statement fails
+var brokenMigrations = fstest.MapFS{
+    "sqlite/001_items.up.sql":    {Data: []byte(`CREATE TABLE items (id INTEGER PRIMARY KEY);`)},
+    "sqlite/001_items.down.sql":  {Data: []byte(`DROP TABLE items;`)},
+    "sqlite/002_broken.up.sql":   {Data: []byte(`CREATE TABLE half (id INTEGER); INSERT INTO missing VALUES (1);`)},
+    "sqlite/002_broken.down.sql": {Data: []byte(`DROP TABLE half;`)},
+}
+
+func newTestMigrator(t *testing.T, transactional bool) *Migrator {
+    t.Helper()
+
+    db, err := Connect(Config{Driver: DriverSQLite, Path: filepath.Join(t.TempDir(), "migrate.db")})
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+
+    m, err := NewMigrator(db, brokenMigrations, DriverSQLite)
+    if err != nil {
+        t.Fatal(err)
+    }
+    m.d.transactionalDDL = transactional
+    return m
+}
+
+func tableExists(t *testing.T, m *Migrator, name string) bool {
+    t.Helper()
+
+    var n int
+    err := m.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return n > 0
+}
+
+func TestUpFailure(t *testing.T) {
+    tests := []struct {
+        name          string
+        transactional bool
+        wantHalf      bool
+        wantDirty     bool
+    }{
+        {"transactional DDL rolls back", true, false, false},
+        {"without transactional DDL marks dirty", false, true, true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            m := newTestMigrator(t, tt.transactional)
+            ctx := context.Background()
+
+            done, err := m.Up(ctx)
+            if err == nil {
+                t.Fatal("Up succeeded with a broken migration")
+            }
+            if len(done) != 1 {
+                t.Fatalf("applied %d migrations, want 1", len(done))
+            }
+            if got := tableExists(t, m, "half"); got != tt.wantHalf {
+                t.Errorf("table half exists = %v, want %v", got, tt.wantHalf)
+            }
+
+            statuses, err := m.Status(ctx)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !statuses[0].Applied || statuses[1].Applied || statuses[1].Dirty != tt.wantDirty {
+                t.Errorf("status %+v, want 001 applied and 002 pending with dirty = %v", statuses, tt.wantDirty)
+            }
+
+            _, err = m.Up(ctx)
+            if gotDirty := errors.Is(err, ErrDirty); gotDirty != tt.wantDirty {
+                t.Errorf("second Up returned %v, want ErrDirty = %v", err, tt.wantDirty)
+            }
+        })
+    }
+}
+
+func TestForceClearsDirty(t *testing.T) {
+    m := newTestMigrator(t, false)
+    ctx := context.Background()
+
+    if _, err := m.Up(ctx); err == nil {
+        t.Fatal("Up succeeded with a broken migration")
+    }
+    if _, err := m.Down(ctx, 1); !errors.Is(err, ErrDirty) {
+        t.Fatalf("Down returned %v, want ErrDirty", err)
+    }
+
+    if err := m.Force(ctx, 1); err != nil {
+        t.Fatal(err)
+    }
+    done, err := m.Down(ctx, 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(done) != 1 || tableExists(t, m, "items") {
+        t.Errorf("Down reverted %d migrations, want 001 reverted", len(done))
+    }
+}
+
+func TestEnsureTableAddsDirty(t *testing.T) {
+    m := newTestMigrator(t, true)
+    ctx := context.Background()
+
+    // schema_migrations as created before the dirty column existed
+    _, err := m.db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at DATETIME NOT NULL)`)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := m.Force(ctx, 1); err != nil {
+        t.Fatal(err)
+    }
+
+    statuses, err := m.Status(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !statuses[0].Applied || statuses[0].Dirty {
+        t.Errorf("status %+v, want 001 applied and clean", statuses[0])
+    }
+}
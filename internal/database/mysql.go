@@ -0,0 +1,64 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+var mysqlDialect = dialect{
+    name: DriverMySQL,
+    upsertArticle: `ON DUPLICATE KEY UPDATE title=VALUES(title), category = VALUES(category), scraped_at = VALUES(scraped_at),
+        summary_generated = CASE WHEN VALUES(summary) = '' THEN summary_generated ELSE FALSE END,
+        summary = CASE WHEN VALUES(summary) = '' THEN summary ELSE VALUES(summary) END,
+        language = COALESCE(NULLIF(language, ''), VALUES(language)), sentiment = VALUES(sentiment)`,
+    insertIgnoreJob: `ON DUPLICATE KEY UPDATE id = id`,
+    lockSkipLocked:  `FOR UPDATE SKIP LOCKED`,
+    createdDay:      `DATE_FORMAT(created_at, '%Y-%m-%d')`,
+    migrationsTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INT PRIMARY KEY,
+        name VARCHAR(255) NOT NULL,
+        applied_at DATETIME NOT NULL,
+        dirty BOOLEAN NOT NULL DEFAULT FALSE
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+    columnExists: `SELECT COUNT(*) FROM information_schema.COLUMNS
+                   WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`,
+}
+
+func connectMySQL(cfg Config) (*sql.DB, error) {
+    //allows for multi statement query //multiStatements=true
+    //connection to sql server
+    dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?multiStatements=true&parseTime=true&charset=utf8mb4",
+        cfg.User, cfg.Password, cfg.Host, cfg.Port)
+
+    db, err := sql.Open("mysql", dsn)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open database: %w", err)
+    }
+
+    //Create database
+
+    _, err = db.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", cfg.Name))
+    if err != nil{
+        log.Fatalf("Error in creating database: %v",err)
+    }
+    fmt.Printf("Database '%s' ensured to exist.\n", cfg.Name)
+    db.Close()
+
+
+    //re-connect to the newly created database
+
+    dbDsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?multiStatements=true&parseTime=true&charset=utf8mb4",
+        cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+    db, err = sql.Open("mysql", dbDsn)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open database: %w", err)
+    }
+
+    db.SetMaxOpenConns(25)
+    db.SetMaxIdleConns(5)
+
+    return db, nil
+}
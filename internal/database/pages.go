@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"news-scraper/internal/models"
+)
+
+// GetPageCache returns the stored validators of a page, or nil if it was never fetched
+func (r *SQLRepository) GetPageCache(ctx context.Context, url string) (*models.PageCache, error) {
+    var page models.PageCache
+    err := r.d.queryRow(ctx, r.db,
+        `SELECT source_id, url, COALESCE(etag, ''), COALESCE(last_modified, ''), COALESCE(content_hash, ''), checked_at
+         FROM page_cache WHERE url = ?`, url).
+        Scan(&page.SourceID, &page.URL, &page.ETag, &page.LastModified, &page.ContentHash, &page.CheckedAt)
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &page, nil
+}
+
+// SavePageCache stores the validators of a page, replacing the previous ones
+func (r *SQLRepository) SavePageCache(ctx context.Context, page *models.PageCache) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := r.d.exec(ctx, tx, `DELETE FROM page_cache WHERE url = ?`, page.URL); err != nil {
+        return err
+    }
+    if _, err := r.d.exec(ctx, tx,
+        `INSERT INTO page_cache (source_id, url, etag, last_modified, content_hash, checked_at)
+         VALUES (?, ?, ?, ?, ?, ?)`,
+        page.SourceID, page.URL, truncate(page.ETag, 255), truncate(page.LastModified, 64),
+        page.ContentHash, page.CheckedAt.UTC()); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
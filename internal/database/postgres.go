@@ -0,0 +1,52 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+var postgresDialect = dialect{
+    name:     DriverPostgres,
+    numbered: true,
+    upsertArticle: `ON CONFLICT (canonical_url) DO UPDATE SET title = excluded.title, category = excluded.category, scraped_at = excluded.scraped_at,
+        summary_generated = CASE WHEN excluded.summary = '' THEN articles.summary_generated ELSE FALSE END,
+        summary = CASE WHEN excluded.summary = '' THEN articles.summary ELSE excluded.summary END,
+        language = COALESCE(NULLIF(articles.language, ''), excluded.language), sentiment = excluded.sentiment`,
+    insertIgnoreJob: `ON CONFLICT (kind, dedupe_key) DO NOTHING`,
+    lockSkipLocked:  `FOR UPDATE SKIP LOCKED`,
+    returningID:     true,
+    createdDay:      `TO_CHAR(created_at, 'YYYY-MM-DD')`,
+    migrationsTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name VARCHAR(255) NOT NULL,
+        applied_at TIMESTAMP NOT NULL,
+        dirty BOOLEAN NOT NULL DEFAULT FALSE
+    )`,
+    columnExists: `SELECT COUNT(*) FROM information_schema.columns
+                   WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`,
+    transactionalDDL: true,
+}
+
+// connectPostgres connects to an existing PostgreSQL database
+// Unlike MySQL, the database itself is not created here.
+func connectPostgres(cfg Config) (*sql.DB, error) {
+    sslMode := cfg.SSLMode
+    if sslMode == "" {
+        sslMode = "disable"
+    }
+
+    dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+        cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, sslMode)
+
+    db, err := sql.Open("postgres", dsn)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open database: %w", err)
+    }
+
+    db.SetMaxOpenConns(25)
+    db.SetMaxIdleConns(5)
+
+    return db, nil
+}
@@ -3,30 +3,178 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"log"
+	"strings"
+	"time"
 
 	// "fmt"
 	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+	"news-scraper/internal/simhash"
 )
 
 // Repository provides database operations
-// It abstracts SQL queries and provides a clean interface
-type Repository struct {
+// It abstracts SQL queries and provides a clean interface; the scraper,
+// scheduler and handlers only depend on this interface, so any backend
+// that passes the conformance suite can be swapped in.
+type Repository interface {
+    SourceStore
+    ArticleStore
+    JobQueue
+    RetentionStore
+    StoryStore
+    EntityStore
+    RunStore
+    PageCacheStore
+}
+
+// SourceStore reads and writes news sources
+type SourceStore interface {
+    GetActiveSources(ctx context.Context) ([]models.Source, error)
+    GetSources(ctx context.Context) ([]models.Source, error)
+    GetSourceByID(ctx context.Context, id int) (*models.Source, error)
+    CreateSource(ctx context.Context, source *models.Source) error
+    DeleteSource(ctx context.Context, id int) error
+}
+
+// ArticleStore reads and writes scraped articles
+type ArticleStore interface {
+    SaveArticle(ctx context.Context, article *models.Article) error
+    SaveArticles(ctx context.Context, articles []models.Article) (models.SaveResult, error)
+    UpdateArticleContent(ctx context.Context, canonicalURL string, content models.ArticleContent) error
+    GetRecentArticles(ctx context.Context, limit int) ([]models.Article, error)
+    GetArticlesByCategory(ctx context.Context, category string, limit int) ([]models.Article, error)
+    GetCategories(ctx context.Context) ([]string, error)
+    GetArticlesByLanguage(ctx context.Context, language string, limit int) ([]models.Article, error)
+    GetLanguages(ctx context.Context) ([]string, error)
+    GetArticlesBySource(ctx context.Context, sourceID int) ([]models.Article, error)
+    GetArticleURLsBySource(ctx context.Context, sourceID int) ([]models.Article, error)
+    GetArticleRevisions(ctx context.Context, articleID int) ([]models.ArticleRevision, error)
+    GetArticleURLs(ctx context.Context) ([]models.Article, error)
+    GetArticlesCreatedSince(ctx context.Context, since time.Time) ([]models.Article, error)
+    GetSentimentCounts(ctx context.Context, since time.Time, entity, query string) ([]models.SentimentCount, error)
+    MergeArticleURL(ctx context.Context, from, to string) error
+}
+
+// JobQueue is the durable per-article work queue
+type JobQueue interface {
+    EnqueueJob(ctx context.Context, kind, dedupeKey, payload string, maxAttempts int) (bool, error)
+    LeaseJobs(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error)
+    AckJob(ctx context.Context, job models.Job) error
+    FailJob(ctx context.Context, job models.Job, jobErr error, retryAt time.Time) error
+    RescheduleJob(ctx context.Context, job models.Job, retryAt time.Time) error
+    GetDeadJobs(ctx context.Context, limit int) ([]models.Job, error)
+    PurgeDoneJobs(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// RetentionStore supports the retention and archiving job
+type RetentionStore interface {
+    GetRetentionCandidates(ctx context.Context) ([]models.Article, error)
+    GetArticlesByIDs(ctx context.Context, ids []int) ([]models.Article, error)
+    ArchiveArticles(ctx context.Context, ids []int) (int64, error)
+    DeleteArticles(ctx context.Context, ids []int) (int64, error)
+}
+
+// StoryStore groups near-duplicate articles into stories
+type StoryStore interface {
+    GetUnclusteredArticles(ctx context.Context, since time.Time) ([]models.Article, error)
+    GetUnclusteredArticlesByURL(ctx context.Context, canonicalURLs []string) ([]models.Article, error)
+    GetStoryFingerprints(ctx context.Context, since time.Time) ([]models.Article, error)
+    CreateStory(ctx context.Context, title string) (int, error)
+    SetArticleStory(ctx context.Context, articleID, storyID int) error
+}
+
+// EntityStore records the people, organizations and places articles mention
+type EntityStore interface {
+    SetArticleEntities(ctx context.Context, canonicalURL string, entities []models.Entity) error
+    GetArticleEntities(ctx context.Context, articleIDs []int) (map[int][]models.Entity, error)
+    GetArticlesByEntity(ctx context.Context, normalizedName string, limit int) ([]models.Article, error)
+}
+
+// RunStore keeps the history of scrape runs
+type RunStore interface {
+    RecordScrapeRun(ctx context.Context, run *models.ScrapeRun) error
+    GetScrapeRuns(ctx context.Context, sourceID, limit int) ([]models.ScrapeRun, error)
+    PurgeScrapeRuns(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+// PageCacheStore keeps the HTTP validators of fetched listing pages
+type PageCacheStore interface {
+    GetPageCache(ctx context.Context, url string) (*models.PageCache, error)
+    SavePageCache(ctx context.Context, page *models.PageCache) error
+}
+
+// SQLRepository implements Repository on MySQL, PostgreSQL or SQLite
+// Queries are shared; the dialect fills in the parts that differ.
+type SQLRepository struct {
     db *sql.DB
+    d  dialect
 }
 
-// NewRepository creates a new repository
-func NewRepository(db *sql.DB) *Repository {
-    return &Repository{db: db}
+// NewRepository creates a repository for a connection opened with Connect
+func NewRepository(db *sql.DB, driver string) (*SQLRepository, error) {
+    d, err := dialectFor(driver)
+    if err != nil {
+        return nil, err
+    }
+    return &SQLRepository{db: db, d: d}, nil
+}
+
+// CreateSource inserts a source and sets its ID
+func (r *SQLRepository) CreateSource(ctx context.Context, source *models.Source) error {
+    query := `INSERT INTO sources (name, url, selector_title, selector_link, selector_summary, selector_body, keep_query_params, ignore_robots, proxy_pool,
+                  user_agent, timeout_seconds, deadline_seconds, headers, cookies, selector_next, page_url_template, max_pages,
+                  stop_after_known, default_category, active, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+    if source.DefaultCategory == "" {
+        source.DefaultCategory = "general"
+    }
+
+    headers, err := encodeHeaders(source.Headers)
+    if err != nil {
+        return err
+    }
+
+    now := time.Now().UTC()
+    id, err := r.d.insertID(ctx, r.db, query,
+        source.Name, source.URL, source.SelectorTitle, source.SelectorLink, source.SelectorSummary,
+        source.SelectorBody, source.KeepQueryParams, source.IgnoreRobots, source.ProxyPool,
+        source.UserAgent, source.TimeoutSeconds, source.DeadlineSeconds, headers, source.Cookies,
+        source.SelectorNext, source.PageURLTemplate, source.MaxPages, source.StopAfterKnown,
+        source.DefaultCategory, source.Active, now, now)
+    if err != nil {
+        return err
+    }
+
+    source.ID = int(id)
+    source.CreatedAt = now
+    source.UpdatedAt = now
+    return nil
+}
+
+// DeleteSource removes a source; its articles go with it (ON DELETE CASCADE)
+func (r *SQLRepository) DeleteSource(ctx context.Context, id int) error {
+    _, err := r.d.exec(ctx, r.db, `DELETE FROM sources WHERE id = ?`, id)
+    return err
 }
 
 // GetActiveSources retrieves all active news sources
 // Used by scraper to know which sites to scrape
-func (r *Repository) GetActiveSources(ctx context.Context) ([]models.Source, error) {
-    query := `SELECT id, name, url, selector_title, selector_link, selector_summary, default_category, active, created_at, updated_at FROM sources WHERE active = TRUE ORDER BY name`
+func (r *SQLRepository) GetActiveSources(ctx context.Context) ([]models.Source, error) {
+    return r.getSources(ctx, `WHERE active = TRUE`)
+}
+
+// GetSources retrieves every source, active or not, by name
+func (r *SQLRepository) GetSources(ctx context.Context) ([]models.Source, error) {
+    return r.getSources(ctx, ``)
+}
+
+func (r *SQLRepository) getSources(ctx context.Context, where string) ([]models.Source, error) {
+    query := `SELECT ` + sourceColumns + ` FROM sources ` + where + ` ORDER BY name`
 
-    rows, err := r.db.QueryContext(ctx, query)
+    rows, err := r.d.query(ctx, r.db, query)
     if err != nil {
         return nil, err
     }
@@ -34,9 +182,7 @@ func (r *Repository) GetActiveSources(ctx context.Context) ([]models.Source, err
 
     var sources []models.Source
     for rows.Next() {
-        var s models.Source
-        err := rows.Scan(&s.ID, &s.Name, &s.URL, &s.SelectorTitle,
-            &s.SelectorLink, &s.SelectorSummary, &s.DefaultCategory, &s.Active, &s.CreatedAt, &s.UpdatedAt)
+        s, err := scanSource(rows)
         if err != nil {
             return nil, err
         }
@@ -47,73 +193,167 @@ func (r *Repository) GetActiveSources(ctx context.Context) ([]models.Source, err
 }
 
 // SaveArticle saves an article to the database
-// Uses ON DUPLICATE KEY UPDATE to avoid duplicate entries
-// If article URL already exists, it updates title and summary
-func (r *Repository) SaveArticle(ctx context.Context, article *models.Article) error {
-    query := `INSERT INTO articles (source_id, source_name, title, url, summary, category, scraped_at)
-              VALUES (?, ?, ?, ?, ?, ?, NOW())
-              ON DUPLICATE KEY UPDATE title=VALUES(title), summary=VALUES(summary), category = VALUES(category), scraped_at = NOW()`
+// Upserts on the canonical URL to avoid duplicate entries
+// If the article already exists, it updates title and summary
+// (an empty summary keeps the stored one) and keeps the previous version in article_revisions
+func (r *SQLRepository) SaveArticle(ctx context.Context, article *models.Article) error {
+    query := `INSERT INTO articles (source_id, source_name, title, url, canonical_url, summary, category, language, sentiment, scraped_at, created_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ` + r.d.upsertArticle
 
-    _, err := r.db.ExecContext(ctx, query,
-        article.SourceID, article.SourceName , article.Title, article.URL, article.Summary, article.Category)
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
 
-    return err
+    batch := dedupeByKey([]models.Article{*article})
+    stored, err := r.storedArticles(ctx, tx, batch)
+    if err != nil {
+        return err
+    }
+    article.CanonicalURL = batch[0].CanonicalURL
+    sentiment := savedSentiment(*article, stored)
+
+    now := time.Now().UTC()
+    _, err = r.d.exec(ctx, tx, query,
+        article.SourceID, article.SourceName , article.Title, article.URL, article.CanonicalURL, article.Summary, article.Category, article.Language, sentiment, now, now)
+    if err != nil {
+        return err
+    }
+
+    if err := r.recordRevisions(ctx, tx, []string{article.CanonicalURL}); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// UpdateArticleContent stores the body and metadata fetched by the worker pool
+// for the article with the given canonical URL
+// The listing summary wins over the page description, and both win over
+// a summary generated from the body
+func (r *SQLRepository) UpdateArticleContent(ctx context.Context, canonicalURL string, content models.ArticleContent) error {
+    query := `UPDATE articles SET body = ?, image_url = ?, author = ?, published_at = ?, fetched_at = ?,
+              summary = ?, summary_generated = ?, language = COALESCE(NULLIF(?, ''), language), sentiment = ? WHERE canonical_url = ?`
+
+    var publishedAt sql.NullTime
+    if !content.PublishedAt.IsZero() {
+        publishedAt = sql.NullTime{Time: content.PublishedAt, Valid: true}
+    }
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var stored models.Article
+    err = r.d.queryRow(ctx, tx, `SELECT title, COALESCE(summary, ''), summary_generated, COALESCE(language, '') FROM articles WHERE canonical_url = ?`, canonicalURL).
+        Scan(&stored.Title, &stored.Summary, &stored.SummaryGenerated, &stored.Language)
+    if err == sql.ErrNoRows {
+        return nil
+    }
+    if err != nil {
+        return err
+    }
+    summary, generated := pickSummary(stored, content)
+    language := firstNonEmpty(content.Language, stored.Language)
+
+    _, err = r.d.exec(ctx, tx, query,
+        content.Body, content.ImageURL, content.Author, publishedAt, time.Now().UTC(), summary, generated, content.Language,
+        articleSentiment(stored.Title, summary, language), canonicalURL)
+    if err != nil {
+        return err
+    }
+
+    if err := r.recordRevisions(ctx, tx, []string{canonicalURL}); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// pickSummary decides what an article's summary becomes after its page is fetched
+func pickSummary(stored models.Article, content models.ArticleContent) (string, bool) {
+    switch {
+    case stored.Summary != "" && !stored.SummaryGenerated:
+        return stored.Summary, false
+    case content.Description != "":
+        return content.Description, false
+    case content.Summary != "":
+        return content.Summary, true
+    default:
+        return stored.Summary, stored.SummaryGenerated
+    }
+}
+
+// articleSentiment scores an article's title and summary
+// It is derived from the stored text, like the revision hash, so every
+// write that changes the title or summary scores it again.
+func articleSentiment(title, summary, language string) float64 {
+    return nlp.Sentiment(title+"\n\n"+summary, language)
+}
+
+// articleFingerprint is the SimHash the stories package clusters on
+// Like the revision hash it covers the body once the worker has fetched it.
+func articleFingerprint(title, summary, body string) uint64 {
+    return simhash.Fingerprint(title + " " + summary + " " + body)
+}
+
+// savedSentiment scores an article as a save will store it: an empty
+// listing summary and a detected language keep the stored ones (see storedArticles)
+func savedSentiment(a models.Article, stored map[string]models.Article) float64 {
+    summary, language := a.Summary, a.Language
+    if s, ok := stored[a.CanonicalURL]; ok {
+        if summary == "" {
+            summary = s.Summary
+        }
+        language = firstNonEmpty(s.Language, language)
+    }
+    return articleSentiment(a.Title, summary, language)
+}
+
+func firstNonEmpty(values ...string) string {
+    for _, v := range values {
+        if v != "" {
+            return v
+        }
+    }
+    return ""
 }
 
 // GetRecentArticles retrieves the most recent articles
 // Ordered by scraped_at descending (newest first)
-func (r *Repository) GetRecentArticles(ctx context.Context, limit int) ([]models.Article, error) {
-    query := `SELECT id, source_id, source_name, title, url, summary, category, scraped_at, created_at
+func (r *SQLRepository) GetRecentArticles(ctx context.Context, limit int) ([]models.Article, error) {
+    query := `SELECT ` + articleColumns + `
               FROM articles ORDER BY scraped_at DESC LIMIT ?`
 
-    rows, err := r.db.QueryContext(ctx, query, limit)
+    rows, err := r.d.query(ctx, r.db, query, limit)
     if err != nil {
         return nil, err
     }
     defer rows.Close()
 
-    var articles []models.Article
-    for rows.Next() {
-        var a models.Article
-        err := rows.Scan(&a.ID, &a.SourceID, &a.SourceName, &a.Title, &a.URL, &a.Summary, &a.Category, &a.ScrapedAt, &a.CreatedAt)
-        if err != nil {
-            return nil, err
-        }
-        articles = append(articles, a)
-    }
-    return articles, rows.Err()
+    return scanArticles(rows)
 }
 
 //Get articles by category
-func(r *Repository) GetArticlesByCategory(ctx context.Context, category string, limit int ) ([]models.Article, error) {
-    query := `SELECT id, source_id, source_name, title, url, summary, category, scraped_at, created_at FROM articles where category = ? ORDER BY scraped_at DESC LIMIT ?`
+func (r *SQLRepository) GetArticlesByCategory(ctx context.Context, category string, limit int ) ([]models.Article, error) {
+    query := `SELECT ` + articleColumns + ` FROM articles where category = ? ORDER BY scraped_at DESC LIMIT ?`
 
-    rows, err := r.db.QueryContext(ctx, query, category, limit)
+    rows, err := r.d.query(ctx, r.db, query, category, limit)
     if err != nil {
         fmt.Println("Get articles by category query error :", err)
         return nil, err
     }
     defer rows.Close()
 
-    var articles []models.Article
-    for rows.Next() {
-        var a models.Article
-        err := rows.Scan(
-            &a.ID, &a.SourceID, &a.SourceName, &a.Title, &a.URL, &a.Summary, &a.Category, &a.ScrapedAt, &a.CreatedAt,
-        )
-        if err != nil {
-            return nil, err
-        }
-        articles = append(articles, a)
-    }
-    return articles, rows.Err()
+    return scanArticles(rows)
 }
 
 //Get all available categories
-func (r *Repository) GetCategories(ctx context.Context) ([]string, error) {
+func (r *SQLRepository) GetCategories(ctx context.Context) ([]string, error) {
     query := `SELECT DISTINCT category FROM articles WHERE category IS NOT NULL ORDER BY category`
 
-    rows, err := r.db.QueryContext(ctx, query)
+    rows, err := r.d.query(ctx, r.db, query)
     if err != nil {
         return nil, err
     }
@@ -131,12 +371,82 @@ func (r *Repository) GetCategories(ctx context.Context) ([]string, error) {
     return categories, rows.Err()
 }
 
+// GetArticlesByLanguage retrieves the most recent articles in a language
+func (r *SQLRepository) GetArticlesByLanguage(ctx context.Context, language string, limit int) ([]models.Article, error) {
+    query := `SELECT ` + articleColumns + ` FROM articles WHERE language = ? ORDER BY scraped_at DESC LIMIT ?`
+
+    rows, err := r.d.query(ctx, r.db, query, language, limit)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    return scanArticles(rows)
+}
+
+// GetLanguages returns the distinct detected languages
+func (r *SQLRepository) GetLanguages(ctx context.Context) ([]string, error) {
+    query := `SELECT DISTINCT language FROM articles WHERE language IS NOT NULL AND language <> '' ORDER BY language`
+
+    rows, err := r.d.query(ctx, r.db, query)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var languages []string
+    for rows.Next() {
+        var language string
+        if err := rows.Scan(&language); err != nil {
+            return nil, err
+        }
+        languages = append(languages, language)
+    }
+    return languages, rows.Err()
+}
+
+// GetArticleURLsBySource returns the URL and canonical URL of every stored article of a source
+// Used by the scraper to tell new articles from ones it already has.
+func (r *SQLRepository) GetArticleURLsBySource(ctx context.Context, sourceID int) ([]models.Article, error) {
+    rows, err := r.d.query(ctx, r.db, `SELECT url, canonical_url FROM articles WHERE source_id = ?`, sourceID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var articles []models.Article
+    for rows.Next() {
+        var a models.Article
+        if err := rows.Scan(&a.URL, &a.CanonicalURL); err != nil {
+            return nil, err
+        }
+        articles = append(articles, a)
+    }
+    return articles, rows.Err()
+}
+
 // GetArticlesBySource retrieves articles from a specific source
-func (r *Repository) GetArticlesBySource(ctx context.Context, sourceID int) ([]models.Article, error) {
-    query := `SELECT id, source_id, source_name, title, url, summary, category, scraped_at, created_at
+func (r *SQLRepository) GetArticlesBySource(ctx context.Context, sourceID int) ([]models.Article, error) {
+    query := `SELECT ` + articleColumns + `
               FROM articles WHERE source_id = ? ORDER BY scraped_at DESC LIMIT 50`
 
-    rows, err := r.db.QueryContext(ctx, query, sourceID)
+    rows, err := r.d.query(ctx, r.db, query, sourceID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    return scanArticles(rows)
+}
+
+// GetArticlesCreatedSince returns the text fields and sentiment of articles first seen since the given time
+// Used by the trends analysis; created_at is used because scraped_at moves
+// forward every time an article is seen again.
+func (r *SQLRepository) GetArticlesCreatedSince(ctx context.Context, since time.Time) ([]models.Article, error) {
+    query := `SELECT id, source_id, source_name, title, COALESCE(summary, ''), COALESCE(category, ''), sentiment, created_at
+              FROM articles WHERE created_at >= ? ORDER BY created_at`
+
+    rows, err := r.d.query(ctx, r.db, query, since.UTC())
     if err != nil {
         return nil, err
     }
@@ -145,8 +455,7 @@ func (r *Repository) GetArticlesBySource(ctx context.Context, sourceID int) ([]m
     var articles []models.Article
     for rows.Next() {
         var a models.Article
-        err := rows.Scan(&a.ID, &a.SourceID, &a.SourceName, &a.Title, &a.URL, &a.Summary, &a.Category, &a.ScrapedAt, &a.CreatedAt)
-        if err != nil {
+        if err := rows.Scan(&a.ID, &a.SourceID, &a.SourceName, &a.Title, &a.Summary, &a.Category, &a.Sentiment, &a.CreatedAt); err != nil {
             return nil, err
         }
         articles = append(articles, a)
@@ -154,19 +463,59 @@ func (r *Repository) GetArticlesBySource(ctx context.Context, sourceID int) ([]m
     return articles, rows.Err()
 }
 
+// GetSentimentCounts groups the articles first seen since the given time by
+// source, category and UTC day and counts their tone, optionally only those
+// mentioning an entity (by normalized name) or containing query in their
+// title or summary. The grouping runs in the database so that the dashboard
+// reads one row per group rather than every article.
+func (r *SQLRepository) GetSentimentCounts(ctx context.Context, since time.Time, entity, query string) ([]models.SentimentCount, error) {
+    q := `SELECT source_name, COALESCE(category, ''), ` + r.d.createdDay + `, COUNT(*), COUNT(sentiment), COALESCE(SUM(sentiment), 0),
+                 SUM(CASE WHEN sentiment >= ? THEN 1 ELSE 0 END),
+                 SUM(CASE WHEN sentiment > ? AND sentiment < ? THEN 1 ELSE 0 END),
+                 SUM(CASE WHEN sentiment <= ? THEN 1 ELSE 0 END)
+          FROM articles WHERE created_at >= ?`
+    args := []any{nlp.PositiveThreshold, nlp.NegativeThreshold, nlp.PositiveThreshold, nlp.NegativeThreshold, since.UTC()}
+
+    if entity != "" {
+        q += ` AND id IN (SELECT article_id FROM article_entities WHERE normalized_name = ?)`
+        args = append(args, entity)
+    }
+    if query != "" {
+        pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+        q += ` AND (LOWER(title) LIKE ? ESCAPE '!' OR LOWER(COALESCE(summary, '')) LIKE ? ESCAPE '!')`
+        args = append(args, pattern, pattern)
+    }
+    q += ` GROUP BY source_name, COALESCE(category, ''), ` + r.d.createdDay
+
+    rows, err := r.d.query(ctx, r.db, q, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var counts []models.SentimentCount
+    for rows.Next() {
+        var c models.SentimentCount
+        if err := rows.Scan(&c.SourceName, &c.Category, &c.Day, &c.Articles, &c.Scored, &c.Sum, &c.Positive, &c.Neutral, &c.Negative); err != nil {
+            return nil, err
+        }
+        counts = append(counts, c)
+    }
+    return counts, rows.Err()
+}
+
+// likeEscaper escapes LIKE wildcards for ESCAPE '!'
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
 // GetSourceByID retrieves a single source by ID
-func (r *Repository) GetSourceByID(ctx context.Context, id int) (*models.Source, error) {
+func (r *SQLRepository) GetSourceByID(ctx context.Context, id int) (*models.Source, error) {
     query := `
-        SELECT id, name, url, selector_title, selector_link, selector_summary, dafault_category, active, created_at, updated_at
+        SELECT ` + sourceColumns + `
         FROM sources
         WHERE id = ?
     `
 
-    var s models.Source
-    err := r.db.QueryRowContext(ctx, query, id).Scan(
-        &s.ID, &s.Name, &s.URL,
-        &s.SelectorTitle, &s.SelectorLink, &s.SelectorSummary, &s.DefaultCategory, &s.Active, &s.CreatedAt, &s.UpdatedAt,
-    )
+    s, err := scanSource(r.d.queryRow(ctx, r.db, query, id))
 
     if err == sql.ErrNoRows {
         return nil, nil
@@ -178,19 +527,57 @@ func (r *Repository) GetSourceByID(ctx context.Context, id int) (*models.Source,
     return &s, nil
 }
 
-//Deletes all articles from the database
-func (r *Repository) ClearAllArticles( ctx context.Context) error {
-    query := `DELETE FROM articles WHERE scraped_at < NOW() - INTERVAL 24 HOUR`
+// sourceColumns is the column list scanSource expects
+const sourceColumns = `id, name, url, selector_title, selector_link, selector_summary, COALESCE(selector_body, ''),
+    COALESCE(keep_query_params, ''), ignore_robots, COALESCE(proxy_pool, ''), COALESCE(user_agent, ''),
+    COALESCE(timeout_seconds, 0), COALESCE(deadline_seconds, 0), COALESCE(headers, ''), COALESCE(cookies, ''), COALESCE(selector_next, ''),
+    COALESCE(page_url_template, ''), COALESCE(max_pages, 0),
+    COALESCE(stop_after_known, 0), default_category, active, created_at, updated_at`
 
-    result, err := r.db.ExecContext(ctx, query)
+// scanSource reads a row selected with sourceColumns
+func scanSource(row interface{ Scan(dest ...any) error }) (models.Source, error) {
+    var s models.Source
+    var headers string
+    err := row.Scan(&s.ID, &s.Name, &s.URL, &s.SelectorTitle, &s.SelectorLink, &s.SelectorSummary, &s.SelectorBody,
+        &s.KeepQueryParams, &s.IgnoreRobots, &s.ProxyPool, &s.UserAgent, &s.TimeoutSeconds, &s.DeadlineSeconds, &headers, &s.Cookies,
+        &s.SelectorNext, &s.PageURLTemplate, &s.MaxPages, &s.StopAfterKnown, &s.DefaultCategory, &s.Active, &s.CreatedAt, &s.UpdatedAt)
     if err != nil {
-        return err
+        return s, err
+    }
+    if headers != "" {
+        if err := json.Unmarshal([]byte(headers), &s.Headers); err != nil {
+            return s, fmt.Errorf("source %d: invalid headers: %w", s.ID, err)
+        }
     }
+    return s, nil
+}
 
-    rowsAffected, err := result.RowsAffected()
+// encodeHeaders stores request headers as a JSON object; none is NULL
+func encodeHeaders(headers map[string]string) (any, error) {
+    if len(headers) == 0 {
+        return nil, nil
+    }
+    data, err := json.Marshal(headers)
     if err != nil {
-        return err
+        return nil, err
     }
-    log.Printf("Deleted %d aricles from database", rowsAffected)
-    return nil
+    return string(data), nil
+}
+
+// articleColumns is the column list of article listings, read by scanArticles
+// Listings leave out the fetched body to stay light.
+const articleColumns = `id, source_id, source_name, title, url, summary, summary_generated, category, COALESCE(language, ''), sentiment, COALESCE(story_id, 0), scraped_at, created_at`
+
+// scanArticles reads rows selected with articleColumns
+func scanArticles(rows *sql.Rows) ([]models.Article, error) {
+    var articles []models.Article
+    for rows.Next() {
+        var a models.Article
+        err := rows.Scan(&a.ID, &a.SourceID, &a.SourceName, &a.Title, &a.URL, &a.Summary, &a.SummaryGenerated, &a.Category, &a.Language, &a.Sentiment, &a.StoryID, &a.ScrapedAt, &a.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        articles = append(articles, a)
+    }
+    return articles, rows.Err()
 }
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"news-scraper/internal/models"
+)
+
+// retentionBatchSize bounds the number of IDs per IN (...) clause
+const retentionBatchSize = 500
+
+// GetRetentionCandidates returns the fields retention rules are evaluated on
+// for every stored article, newest first. Body and summary are left out
+// to keep the scan cheap.
+func (r *SQLRepository) GetRetentionCandidates(ctx context.Context) ([]models.Article, error) {
+    query := `SELECT id, source_id, source_name, category, scraped_at FROM articles ORDER BY scraped_at DESC, id DESC`
+
+    rows, err := r.d.query(ctx, r.db, query)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var articles []models.Article
+    for rows.Next() {
+        var a models.Article
+        if err := rows.Scan(&a.ID, &a.SourceID, &a.SourceName, &a.Category, &a.ScrapedAt); err != nil {
+            return nil, err
+        }
+        articles = append(articles, a)
+    }
+    return articles, rows.Err()
+}
+
+// GetArticlesByIDs returns full article rows, including the fetched body
+func (r *SQLRepository) GetArticlesByIDs(ctx context.Context, ids []int) ([]models.Article, error) {
+    var articles []models.Article
+
+    for _, batch := range batchIDs(ids) {
+        query := `SELECT id, source_id, source_name, title, url, canonical_url, COALESCE(summary, ''), summary_generated, category, COALESCE(language, ''),
+                         sentiment, COALESCE(body, ''), COALESCE(image_url, ''), COALESCE(author, ''), published_at, scraped_at, created_at
+                  FROM articles WHERE id IN (` + placeholders(len(batch)) + `) ORDER BY id`
+
+        rows, err := r.d.query(ctx, r.db, query, intArgs(batch)...)
+        if err != nil {
+            return nil, err
+        }
+
+        for rows.Next() {
+            var a models.Article
+            var publishedAt sql.NullTime
+            err := rows.Scan(&a.ID, &a.SourceID, &a.SourceName, &a.Title, &a.URL, &a.CanonicalURL, &a.Summary, &a.SummaryGenerated, &a.Category, &a.Language,
+                &a.Sentiment, &a.Body, &a.ImageURL, &a.Author, &publishedAt, &a.ScrapedAt, &a.CreatedAt)
+            if err != nil {
+                rows.Close()
+                return nil, err
+            }
+            a.PublishedAt = publishedAt.Time
+            articles = append(articles, a)
+        }
+        rows.Close()
+        if err := rows.Err(); err != nil {
+            return nil, err
+        }
+    }
+
+    return articles, nil
+}
+
+// ArchiveArticles copies articles into articles_archive and deletes them
+// Each batch is copied and deleted in one transaction, so a row is never
+// lost between the two steps.
+func (r *SQLRepository) ArchiveArticles(ctx context.Context, ids []int) (int64, error) {
+    var archived int64
+
+    for _, batch := range batchIDs(ids) {
+        tx, err := r.db.BeginTx(ctx, nil)
+        if err != nil {
+            return archived, err
+        }
+
+        in := placeholders(len(batch))
+        insert := `INSERT INTO articles_archive (id, source_id, source_name, title, url, canonical_url, summary, summary_generated, category,
+                                                 language, sentiment, body, image_url, author, published_at, scraped_at, created_at, archived_at)
+                   SELECT id, source_id, source_name, title, url, canonical_url, summary, summary_generated, category,
+                          language, sentiment, body, image_url, author, published_at, scraped_at, created_at, CURRENT_TIMESTAMP
+                   FROM articles WHERE id IN (` + in + `)`
+
+        if _, err := r.d.exec(ctx, tx, insert, intArgs(batch)...); err != nil {
+            tx.Rollback()
+            return archived, err
+        }
+
+        result, err := r.d.exec(ctx, tx, `DELETE FROM articles WHERE id IN (`+in+`)`, intArgs(batch)...)
+        if err != nil {
+            tx.Rollback()
+            return archived, err
+        }
+
+        if err := tx.Commit(); err != nil {
+            return archived, err
+        }
+
+        n, _ := result.RowsAffected()
+        archived += n
+    }
+
+    return archived, nil
+}
+
+// DeleteArticles removes articles by ID
+func (r *SQLRepository) DeleteArticles(ctx context.Context, ids []int) (int64, error) {
+    var deleted int64
+
+    for _, batch := range batchIDs(ids) {
+        result, err := r.d.exec(ctx, r.db, `DELETE FROM articles WHERE id IN (`+placeholders(len(batch))+`)`, intArgs(batch)...)
+        if err != nil {
+            return deleted, err
+        }
+
+        n, _ := result.RowsAffected()
+        deleted += n
+    }
+
+    return deleted, nil
+}
+
+func batchIDs(ids []int) [][]int {
+    var batches [][]int
+    for len(ids) > 0 {
+        n := min(len(ids), retentionBatchSize)
+        batches = append(batches, ids[:n])
+        ids = ids[n:]
+    }
+    return batches
+}
+
+func placeholders(n int) string {
+    return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func intArgs(ids []int) []any {
+    args := make([]any, len(ids))
+    for i, id := range ids {
+        args[i] = id
+    }
+    return args
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"news-scraper/internal/models"
+)
+
+// Article revisions
+// Every save that changes an article's title, summary or body adds a row to
+// article_revisions, so headline rewrites are kept instead of overwritten.
+// Revisions are compared by content hash; rows backfilled by the migration
+// have an empty hash and are hashed here on first comparison. The story
+// fingerprint is derived from the same text and refreshed alongside.
+
+// GetArticleRevisions returns an article's revisions, oldest first
+func (r *SQLRepository) GetArticleRevisions(ctx context.Context, articleID int) ([]models.ArticleRevision, error) {
+    query := `SELECT id, article_id, title, COALESCE(summary, ''), COALESCE(body, ''), content_hash, created_at
+              FROM article_revisions WHERE article_id = ? ORDER BY id`
+
+    rows, err := r.d.query(ctx, r.db, query, articleID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var revisions []models.ArticleRevision
+    for rows.Next() {
+        var rev models.ArticleRevision
+        if err := rows.Scan(&rev.ID, &rev.ArticleID, &rev.Title, &rev.Summary, &rev.Body, &rev.ContentHash, &rev.CreatedAt); err != nil {
+            return nil, err
+        }
+        if rev.ContentHash == "" {
+            rev.ContentHash = contentHash(rev.Title, rev.Summary, rev.Body)
+        }
+        revisions = append(revisions, rev)
+    }
+    return revisions, rows.Err()
+}
+
+// recordRevisions snapshots the stored content of the articles with the given canonical URLs and
+// adds a revision for each one whose content differs from its latest revision
+// It runs inside the transaction of the write that may have changed them.
+func (r *SQLRepository) recordRevisions(ctx context.Context, q querier, urls []string) error {
+    for start := 0; start < len(urls); start += saveBatchSize {
+        batch := urls[start:min(start+saveBatchSize, len(urls))]
+
+        current, err := r.currentContent(ctx, q, batch)
+        if err != nil || len(current) == 0 {
+            return err
+        }
+
+        latest, err := r.latestRevisionHashes(ctx, q, current)
+        if err != nil {
+            return err
+        }
+
+        var changed []models.ArticleRevision
+        for _, rev := range current {
+            if latest[rev.ArticleID] != rev.ContentHash {
+                changed = append(changed, rev)
+            }
+        }
+        if len(changed) == 0 {
+            continue
+        }
+
+        query := `INSERT INTO article_revisions (article_id, title, summary, body, content_hash, created_at)
+                  VALUES ` + strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?), ", len(changed)), ", ")
+
+        now := time.Now().UTC()
+        args := make([]any, 0, len(changed)*6)
+        for _, rev := range changed {
+            args = append(args, rev.ArticleID, rev.Title, rev.Summary, rev.Body, rev.ContentHash, now)
+        }
+        if _, err := r.d.exec(ctx, q, query, args...); err != nil {
+            return err
+        }
+
+        // Fingerprints are stored as signed 64-bit values; the bits are what matter
+        for _, rev := range changed {
+            _, err := r.d.exec(ctx, q, `UPDATE articles SET fingerprint = ? WHERE id = ?`,
+                int64(articleFingerprint(rev.Title, rev.Summary, rev.Body)), rev.ArticleID)
+            if err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+// currentContent reads the stored title, summary and body as unsaved revisions
+func (r *SQLRepository) currentContent(ctx context.Context, q querier, urls []string) ([]models.ArticleRevision, error) {
+    args := make([]any, len(urls))
+    for i, url := range urls {
+        args[i] = url
+    }
+
+    query := `SELECT id, title, COALESCE(summary, ''), COALESCE(body, '') FROM articles WHERE canonical_url IN (` + placeholders(len(urls)) + `)`
+    rows, err := r.d.query(ctx, q, query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var current []models.ArticleRevision
+    for rows.Next() {
+        var rev models.ArticleRevision
+        if err := rows.Scan(&rev.ArticleID, &rev.Title, &rev.Summary, &rev.Body); err != nil {
+            return nil, err
+        }
+        rev.ContentHash = contentHash(rev.Title, rev.Summary, rev.Body)
+        current = append(current, rev)
+    }
+    return current, rows.Err()
+}
+
+// latestRevisionHashes returns the content hash of each article's newest revision
+func (r *SQLRepository) latestRevisionHashes(ctx context.Context, q querier, current []models.ArticleRevision) (map[int]string, error) {
+    ids := make([]int, len(current))
+    for i, rev := range current {
+        ids[i] = rev.ArticleID
+    }
+
+    // Only backfilled rows (empty hash) need their text to be hashed
+    query := `SELECT article_id, content_hash,
+                     CASE WHEN content_hash = '' THEN title ELSE '' END,
+                     CASE WHEN content_hash = '' THEN COALESCE(summary, '') ELSE '' END,
+                     CASE WHEN content_hash = '' THEN COALESCE(body, '') ELSE '' END
+              FROM article_revisions
+              WHERE id IN (SELECT MAX(id) FROM article_revisions WHERE article_id IN (` + placeholders(len(ids)) + `) GROUP BY article_id)`
+
+    rows, err := r.d.query(ctx, q, query, intArgs(ids)...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    latest := make(map[int]string)
+    for rows.Next() {
+        var articleID int
+        var hash, title, summary, body string
+        if err := rows.Scan(&articleID, &hash, &title, &summary, &body); err != nil {
+            return nil, err
+        }
+        if hash == "" {
+            hash = contentHash(title, summary, body)
+        }
+        latest[articleID] = hash
+    }
+    return latest, rows.Err()
+}
+
+// contentHash identifies a version of an article's text
+func contentHash(title, summary, body string) string {
+    sum := sha256.Sum256([]byte(title + "\x00" + summary + "\x00" + body))
+    return hex.EncodeToString(sum[:])
+}
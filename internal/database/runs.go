@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"news-scraper/internal/models"
+)
+
+// RecordScrapeRun stores a finished run and its blocked URLs, and sets its ID
+func (r *SQLRepository) RecordScrapeRun(ctx context.Context, run *models.ScrapeRun) error {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    id, err := r.d.insertID(ctx, tx,
+        `INSERT INTO scrape_runs (source_id, source_name, status, articles_found, articles_new, articles_known, articles_updated,
+             error, error_kind, started_at, finished_at)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        run.SourceID, truncate(run.SourceName, 255), run.Status, run.ArticlesFound, run.ArticlesNew, run.ArticlesKnown,
+        run.ArticlesUpdated, run.Error, run.ErrorKind,
+        run.StartedAt.UTC(), run.FinishedAt.UTC())
+    if err != nil {
+        return err
+    }
+
+    for _, u := range run.BlockedURLs {
+        if _, err := r.d.exec(ctx, tx, `INSERT INTO scrape_run_blocked (run_id, url) VALUES (?, ?)`, id, truncate(u, 1024)); err != nil {
+            return err
+        }
+    }
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+
+    run.ID = id
+    return nil
+}
+
+// GetScrapeRuns returns the most recent runs, of one source or of all when sourceID is 0
+func (r *SQLRepository) GetScrapeRuns(ctx context.Context, sourceID, limit int) ([]models.ScrapeRun, error) {
+    query := `SELECT id, source_id, source_name, status, articles_found, articles_new, articles_known, articles_updated,
+                     COALESCE(error, ''), COALESCE(error_kind, ''), started_at, finished_at
+              FROM scrape_runs`
+    var args []any
+    if sourceID != 0 {
+        query += ` WHERE source_id = ?`
+        args = append(args, sourceID)
+    }
+    query += ` ORDER BY started_at DESC, id DESC LIMIT ?`
+    args = append(args, limit)
+
+    rows, err := r.d.query(ctx, r.db, query, args...)
+    if err != nil {
+        return nil, err
+    }
+
+    var runs []models.ScrapeRun
+    index := make(map[int64]int)
+    for rows.Next() {
+        var run models.ScrapeRun
+        if err := rows.Scan(&run.ID, &run.SourceID, &run.SourceName, &run.Status, &run.ArticlesFound, &run.ArticlesNew, &run.ArticlesKnown,
+            &run.ArticlesUpdated, &run.Error, &run.ErrorKind,
+            &run.StartedAt, &run.FinishedAt); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        index[run.ID] = len(runs)
+        runs = append(runs, run)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil || len(runs) == 0 {
+        return runs, err
+    }
+
+    // Close the runs query first: SQLite has a single connection
+    args = make([]any, len(runs))
+    for i, run := range runs {
+        args[i] = run.ID
+    }
+    rows, err = r.d.query(ctx, r.db,
+        `SELECT run_id, url FROM scrape_run_blocked WHERE run_id IN (`+placeholders(len(args))+`) ORDER BY id`, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var id int64
+        var u string
+        if err := rows.Scan(&id, &u); err != nil {
+            return nil, err
+        }
+        runs[index[id]].BlockedURLs = append(runs[index[id]].BlockedURLs, u)
+    }
+    return runs, rows.Err()
+}
+
+// PurgeScrapeRuns deletes runs started before the given age, with their blocked URLs
+func (r *SQLRepository) PurgeScrapeRuns(ctx context.Context, olderThan time.Duration) (int64, error) {
+    result, err := r.d.exec(ctx, r.db, `DELETE FROM scrape_runs WHERE started_at < ?`, time.Now().UTC().Add(-olderThan))
+    if err != nil {
+        return 0, err
+    }
+    return result.RowsAffected()
+}
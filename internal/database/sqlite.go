@@ -0,0 +1,55 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "modernc.org/sqlite"
+)
+
+var sqliteDialect = dialect{
+    name: DriverSQLite,
+    upsertArticle: `ON CONFLICT (canonical_url) DO UPDATE SET title = excluded.title, category = excluded.category, scraped_at = excluded.scraped_at,
+        summary_generated = CASE WHEN excluded.summary = '' THEN articles.summary_generated ELSE FALSE END,
+        summary = CASE WHEN excluded.summary = '' THEN articles.summary ELSE excluded.summary END,
+        language = COALESCE(NULLIF(articles.language, ''), excluded.language), sentiment = excluded.sentiment`,
+    insertIgnoreJob: `ON CONFLICT (kind, dedupe_key) DO NOTHING`,
+    returningID:     true,
+    timeAsText:      true,
+    createdDay:      `substr(created_at, 1, 10)`,
+    migrationsTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+        version INTEGER PRIMARY KEY,
+        name TEXT NOT NULL,
+        applied_at DATETIME NOT NULL,
+        dirty BOOLEAN NOT NULL DEFAULT FALSE
+    )`,
+    columnExists:     `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`,
+    transactionalDDL: true,
+}
+
+// connectSQLite opens (and creates if needed) a SQLite database file
+// Transactions take the write lock up front (_txlock=immediate), which is
+// what serializes job leasing in place of SELECT ... FOR UPDATE.
+func connectSQLite(cfg Config) (*sql.DB, error) {
+    path := cfg.Path
+    if path == "" {
+        path = "news_scraper.db"
+    }
+
+    params := url.Values{}
+    params.Add("_pragma", "foreign_keys(1)")
+    params.Add("_pragma", "busy_timeout(5000)")
+    params.Add("_pragma", "journal_mode(WAL)")
+    params.Set("_txlock", "immediate")
+
+    db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+    if err != nil {
+        return nil, fmt.Errorf("failed to open database: %w", err)
+    }
+
+    // A single writer avoids SQLITE_BUSY between our own goroutines
+    db.SetMaxOpenConns(1)
+
+    return db, nil
+}
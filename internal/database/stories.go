@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"news-scraper/internal/models"
+)
+
+// Story clustering primitives
+// The stories package decides which articles belong together; these
+// methods only read candidates and record its decisions. Fingerprints are
+// written with each revision (see recordRevisions).
+
+// GetUnclusteredArticles returns articles scraped since the given time that
+// have no story yet, oldest first
+func (r *SQLRepository) GetUnclusteredArticles(ctx context.Context, since time.Time) ([]models.Article, error) {
+    return r.unclusteredArticles(ctx, `scraped_at >= ?`, since.UTC())
+}
+
+// GetUnclusteredArticlesByURL returns the articles with the given canonical
+// URLs that have no story yet, oldest first
+func (r *SQLRepository) GetUnclusteredArticlesByURL(ctx context.Context, canonicalURLs []string) ([]models.Article, error) {
+    var articles []models.Article
+    for start := 0; start < len(canonicalURLs); start += saveBatchSize {
+        batch := canonicalURLs[start:min(start+saveBatchSize, len(canonicalURLs))]
+
+        args := make([]any, len(batch))
+        for i, url := range batch {
+            args[i] = url
+        }
+        found, err := r.unclusteredArticles(ctx, `canonical_url IN (`+placeholders(len(batch))+`)`, args...)
+        if err != nil {
+            return nil, err
+        }
+        articles = append(articles, found...)
+    }
+    sort.Slice(articles, func(i, j int) bool { return articles[i].ID < articles[j].ID })
+    return articles, nil
+}
+
+// unclusteredArticles lists articles without a story matching the condition
+func (r *SQLRepository) unclusteredArticles(ctx context.Context, cond string, args ...any) ([]models.Article, error) {
+    query := `SELECT id, source_id, source_name, title, url, COALESCE(summary, ''), COALESCE(fingerprint, 0), scraped_at
+              FROM articles WHERE story_id IS NULL AND ` + cond + ` ORDER BY id`
+
+    rows, err := r.d.query(ctx, r.db, query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var articles []models.Article
+    for rows.Next() {
+        var a models.Article
+        var fingerprint int64
+        if err := rows.Scan(&a.ID, &a.SourceID, &a.SourceName, &a.Title, &a.URL, &a.Summary, &fingerprint, &a.ScrapedAt); err != nil {
+            return nil, err
+        }
+        a.Fingerprint = uint64(fingerprint)
+        articles = append(articles, a)
+    }
+    return articles, rows.Err()
+}
+
+// GetStoryFingerprints returns the story and fingerprint of clustered
+// articles scraped since the given time
+func (r *SQLRepository) GetStoryFingerprints(ctx context.Context, since time.Time) ([]models.Article, error) {
+    query := `SELECT id, source_id, story_id, fingerprint FROM articles
+              WHERE story_id IS NOT NULL AND fingerprint IS NOT NULL AND scraped_at >= ?`
+
+    rows, err := r.d.query(ctx, r.db, query, since.UTC())
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var articles []models.Article
+    for rows.Next() {
+        var a models.Article
+        var fingerprint int64
+        if err := rows.Scan(&a.ID, &a.SourceID, &a.StoryID, &fingerprint); err != nil {
+            return nil, err
+        }
+        a.Fingerprint = uint64(fingerprint)
+        articles = append(articles, a)
+    }
+    return articles, rows.Err()
+}
+
+// CreateStory starts a new story and returns its ID
+func (r *SQLRepository) CreateStory(ctx context.Context, title string) (int, error) {
+    id, err := r.d.insertID(ctx, r.db, `INSERT INTO stories (title, created_at) VALUES (?, ?)`,
+        truncate(title, 512), time.Now().UTC())
+    return int(id), err
+}
+
+// SetArticleStory records the story an article belongs to
+func (r *SQLRepository) SetArticleStory(ctx context.Context, articleID, storyID int) error {
+    _, err := r.d.exec(ctx, r.db, `UPDATE articles SET story_id = ? WHERE id = ?`, storyID, articleID)
+    return err
+}
@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"fmt"
+	"log"
+	"net/url"
 	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
 	"news-scraper/web/templates"
 	"strconv"
 
@@ -10,25 +14,32 @@ import (
 )
 
 type ArticlesHandler struct {
-    repo *database.Repository
+    repo database.Repository
 }
 
-func NewArticlesHandler(repo *database.Repository) *ArticlesHandler {
+func NewArticlesHandler(repo database.Repository) *ArticlesHandler {
     return &ArticlesHandler{repo: repo}
 }
 
 // GetRecent returns articles as JSON (for API)
+// ?language=hi limits the list to one language
 func (h *ArticlesHandler) GetRecent(c *fiber.Ctx) error {
     limit := 100
+    if language := c.Query("language"); language != "" {
+        return h.renderArticlesByLanguage(c, language, limit)
+    }
+
     articles, err := h.repo.GetRecentArticles(c.Context(), limit)
     if err != nil {
         // return c.Status(500).JSON(fiber.Map{
         //     "error": "Failed to fetch articles",
         // })
         c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Failed to fetch articles").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Failed to fetch articles").Render(c.Context(), c.Response().BodyWriter())
     }
 
+    articles = h.withEntities(c, articles)
+
     // return c.JSON(articles)
     c.Set("Content-Type", "text/html")
 
@@ -47,12 +58,13 @@ func (h *ArticlesHandler) GetRecentActivity(c *fiber.Ctx) error {
         //     "error": "Failed to fetch articles",
         // })
         c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Failed to fetch articles").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Failed to fetch articles").Render(c.Context(), c.Response().BodyWriter())
     }
 
     if len(articles) == 0 {
         return c.SendStatus(fiber.StatusNoContent)
     }
+    articles = h.withEntities(c, articles)
 
     // return c.JSON(articles)
     c.Set("Content-Type", "text/html")
@@ -67,7 +79,7 @@ func (h *ArticlesHandler) GetBySource(c *fiber.Ctx) error {
         //     "error": "Invalid source ID",
         // })
           c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Invalid source ID").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Invalid source ID").Render(c.Context(), c.Response().BodyWriter())
 
     }
 
@@ -77,9 +89,10 @@ func (h *ArticlesHandler) GetBySource(c *fiber.Ctx) error {
         //     "error": "Failed to fetch articles",
         // })
           c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Failed to fetch articles").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Failed to fetch articles").Render(c.Context(), c.Response().BodyWriter())
 
     }
+    articles = h.withEntities(c, articles)
 
     // return c.JSON(articles)
     c.Set("Content-Type", "text/html")
@@ -94,7 +107,7 @@ func (h *ArticlesHandler) RenderArticles(c *fiber.Ctx) error {
     if err != nil {
         // return c.Status(500).SendString("Failed to load articles")
         c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Failed to load articles from RenderArticles").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Failed to load articles from RenderArticles").Render(c.Context(), c.Response().BodyWriter())
 
     }
 
@@ -102,6 +115,7 @@ func (h *ArticlesHandler) RenderArticles(c *fiber.Ctx) error {
     //     "Articles": articles,
     // })
     // Render full page with layout
+    articles = h.withEntities(c, articles)
     c.Set("Content-Type", "text/html")
     return templates.Articles(articles).Render(c.Context(), c.Response().BodyWriter())
 }
@@ -113,11 +127,12 @@ func (h *ArticlesHandler) RenderArticlesList(c *fiber.Ctx) error {
     if err != nil {
         // return c.Status(500).SendString("Failed to load articles")
         c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Failed to load articles for RenderArticlesList").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Failed to load articles for RenderArticlesList").Render(c.Context(), c.Response().BodyWriter())
 
     }
 
     // Render only the article list component (no layout)
+    articles = h.withEntities(c, articles)
     c.Set("Content-Type", "text/html")
     return templates.ArticlesList(articles).Render(c.Context(), c.Response().BodyWriter())
 }
@@ -148,10 +163,11 @@ func (h *ArticlesHandler) RenderArticlesByCategory(c *fiber.Ctx) error {
         fmt.Println("Render articles by category error :", err)
         // return c.Status(500).SendString("Failed to load articles")
         c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Failed to load articles for RenderArticlesByCategory").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Failed to load articles for RenderArticlesByCategory").Render(c.Context(), c.Response().BodyWriter())
 
     }
 
+    articles = h.withEntities(c, articles)
     c.Set("Content-Type", "text/html")
     return templates.ArticlesContent(articles, category).Render(
         c.Context(),
@@ -159,6 +175,92 @@ func (h *ArticlesHandler) RenderArticlesByCategory(c *fiber.Ctx) error {
     )
 }
 
+// RenderArticlesByLanguage renders the most recent articles in one language
+func (h *ArticlesHandler) RenderArticlesByLanguage(c *fiber.Ctx) error {
+    return h.renderArticlesByLanguage(c, c.Params("language"), 50)
+}
+
+func (h *ArticlesHandler) renderArticlesByLanguage(c *fiber.Ctx, language string, limit int) error {
+    articles, err := h.repo.GetArticlesByLanguage(c.Context(), language, limit)
+    if err != nil {
+        fmt.Println("Render articles by language error :", err)
+        c.Set("Content-Type", "text/html")
+        return templates.ErrorMessage("Failed to load articles for RenderArticlesByLanguage").Render(c.Context(), c.Response().BodyWriter())
+    }
+
+    articles = h.withEntities(c, articles)
+    c.Set("Content-Type", "text/html")
+    return templates.ArticlesContent(articles, templates.LanguageName(language)).Render(c.Context(), c.Response().BodyWriter())
+}
+
+// RenderArticlesByEntity renders the most recent articles mentioning a person,
+// organization or place; the name may be given in any form, like "U.S." or "narendra modi"
+func (h *ArticlesHandler) RenderArticlesByEntity(c *fiber.Ctx) error {
+    name, err := url.PathUnescape(c.Params("name"))
+    if err != nil {
+        c.Set("Content-Type", "text/html")
+        return templates.ErrorMessage("Invalid entity name").Render(c.Context(), c.Response().BodyWriter())
+    }
+    entity := models.Entity{Name: name, NormalizedName: nlp.NormalizeEntityName(name)}
+
+    articles, err := h.repo.GetArticlesByEntity(c.Context(), entity.NormalizedName, 50)
+    if err != nil {
+        fmt.Println("Render articles by entity error :", err)
+        c.Set("Content-Type", "text/html")
+        return templates.ErrorMessage("Failed to load articles for RenderArticlesByEntity").Render(c.Context(), c.Response().BodyWriter())
+    }
+    articles = h.withEntities(c, articles)
+
+    // Show the name as the articles spell it
+    if len(articles) > 0 {
+        for _, e := range articles[0].Entities {
+            if e.NormalizedName == entity.NormalizedName {
+                entity = e
+                break
+            }
+        }
+    }
+
+    c.Set("Content-Type", "text/html")
+    if c.Get("HX-Request") != "" {
+        return templates.ArticlesContent(articles, entity.Name).Render(c.Context(), c.Response().BodyWriter())
+    }
+    return templates.EntityArticles(entity, articles).Render(c.Context(), c.Response().BodyWriter())
+}
+
+// withEntities fills in the entities shown as chips on each article card
+// A failed lookup only costs the chips, so it is logged and the articles are returned as they are.
+func (h *ArticlesHandler) withEntities(c *fiber.Ctx, articles []models.Article) []models.Article {
+    ids := make([]int, len(articles))
+    for i, a := range articles {
+        ids[i] = a.ID
+    }
+
+    entities, err := h.repo.GetArticleEntities(c.Context(), ids)
+    if err != nil {
+        log.Printf("Failed to load article entities: %v", err)
+        return articles
+    }
+    for i := range articles {
+        articles[i].Entities = entities[articles[i].ID]
+    }
+    return articles
+}
+
+// GetLanguages returns the detected languages as JSON
+func (h *ArticlesHandler) GetLanguages(c *fiber.Ctx) error {
+    languages, err := h.repo.GetLanguages(c.Context())
+    if err != nil {
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to fetch languages",
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "languages": languages,
+    })
+}
+
 // NEW: Get all categories
 func (h *ArticlesHandler) GetCategories(c *fiber.Ctx) error {
     categories, err := h.repo.GetCategories(c.Context())
@@ -167,7 +269,7 @@ func (h *ArticlesHandler) GetCategories(c *fiber.Ctx) error {
         //     "error": "Failed to fetch categories",
         // })
         c.Set("Content-Type", "text/html")
-        return templates.ErrorMessage("Failed to fetch categories for GetCategories").Render(c.Context(), c.Request().BodyWriter())
+        return templates.ErrorMessage("Failed to fetch categories for GetCategories").Render(c.Context(), c.Response().BodyWriter())
 
     }
 
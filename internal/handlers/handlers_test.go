@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/scraper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newTestApp wires the API routes like cmd/server does, on an in-memory repository
+func newTestApp(t *testing.T) (*fiber.App, *database.MemoryRepository, *scraper.Scraper) {
+    t.Helper()
+
+    repo := database.NewMemoryRepository()
+    s := scraper.NewScraper(repo, scraper.Config{
+        Workers:   1,
+        Timeout:   5 * time.Second,
+        RateLimit: 100,
+        UserAgent: "NewsBot/1.0",
+    })
+
+    articles := NewArticlesHandler(repo)
+    scrape := NewScrapeHandler(s)
+    sources := NewSourcesHandler(repo, s)
+
+    app := fiber.New()
+    api := app.Group("/api")
+    api.Get("/articles-list", articles.RenderArticlesList)
+    api.Get("/articles/source/:sourceId", articles.GetBySource)
+    api.Post("/scrape", scrape.TriggerScrape)
+    api.Get("/jobs/dead", NewJobsHandler(repo).GetDead)
+    api.Get("/runs", NewRunsHandler(repo).GetRuns)
+    api.Get("/sources", sources.GetSources)
+    return app, repo, s
+}
+
+// do sends a request to the app and returns the status and body
+func do(t *testing.T, app *fiber.App, method, target string) (int, string) {
+    t.Helper()
+
+    resp, err := app.Test(httptest.NewRequest(method, target, nil), -1)
+    if err != nil {
+        t.Fatalf("%s %s: %v", method, target, err)
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return resp.StatusCode, string(body)
+}
+
+func createSource(t *testing.T, repo database.Repository, source models.Source) models.Source {
+    t.Helper()
+
+    source.Active = true
+    if err := repo.CreateSource(context.Background(), &source); err != nil {
+        t.Fatal(err)
+    }
+    return source
+}
+
+func TestGetSourcesHidesCredentials(t *testing.T) {
+    app, repo, _ := newTestApp(t)
+    createSource(t, repo, models.Source{
+        Name: "Example", URL: "https://example.com/", SelectorTitle: "h2",
+        Headers: map[string]string{"Authorization": "Bearer secret"},
+        Cookies: "session=secret",
+    })
+
+    status, body := do(t, app, http.MethodGet, "/api/sources")
+    if status != http.StatusOK {
+        t.Fatalf("status %d: %s", status, body)
+    }
+    if strings.Contains(body, "secret") {
+        t.Errorf("response leaks headers or cookies: %s", body)
+    }
+
+    var response struct {
+        Sources []struct {
+            Name    string `json:"name"`
+            Circuit struct {
+                State string `json:"state"`
+            } `json:"circuit"`
+        } `json:"sources"`
+    }
+    if err := json.Unmarshal([]byte(body), &response); err != nil {
+        t.Fatal(err)
+    }
+    if len(response.Sources) != 1 || response.Sources[0].Name != "Example" || response.Sources[0].Circuit.State != "closed" {
+        t.Errorf("got %+v, want Example with a closed circuit", response.Sources)
+    }
+}
+
+func TestTriggerScrapeWait(t *testing.T) {
+    site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        fmt.Fprint(w, `<html><body>
+            <h2><a href="/news/1?utm_source=home">First headline</a></h2>
+            <h2><a href="/news/2">Second headline</a></h2>
+        </body></html>`)
+    }))
+    defer site.Close()
+
+    app, repo, _ := newTestApp(t)
+    createSource(t, repo, models.Source{Name: "Example", URL: site.URL + "/", SelectorTitle: "h2", IgnoreRobots: true})
+
+    status, body := do(t, app, http.MethodPost, "/api/scrape?wait=true")
+    if status != http.StatusOK {
+        t.Fatalf("status %d: %s", status, body)
+    }
+
+    var response struct {
+        Report scraper.Report `json:"report"`
+        Error  string         `json:"error"`
+    }
+    if err := json.Unmarshal([]byte(body), &response); err != nil {
+        t.Fatal(err)
+    }
+    if response.Error != "" || len(response.Report.Sources) != 1 {
+        t.Fatalf("got %s, want one source and no error", body)
+    }
+    if run := response.Report.Sources[0]; run.Status != models.RunOK || run.ArticlesNew != 2 {
+        t.Errorf("got run %+v, want ok with 2 new articles", run)
+    }
+
+    // The list links to the URL the source gave, not to its canonical form
+    status, body = do(t, app, http.MethodGet, "/api/articles-list")
+    if status != http.StatusOK || !strings.Contains(body, site.URL+"/news/1?utm_source=home") {
+        t.Errorf("status %d, article link missing from %s", status, body)
+    }
+
+    status, body = do(t, app, http.MethodGet, "/api/runs")
+    if status != http.StatusOK || !strings.Contains(body, `"status":"ok"`) {
+        t.Errorf("status %d, run missing from %s", status, body)
+    }
+}
+
+func TestTriggerScrapeAfterShutdown(t *testing.T) {
+    app, _, s := newTestApp(t)
+    if err := s.Shutdown(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    status, body := do(t, app, http.MethodPost, "/api/scrape?wait=true")
+    if status != http.StatusServiceUnavailable {
+        t.Errorf("status %d, want 503: %s", status, body)
+    }
+}
+
+func TestGetBySource(t *testing.T) {
+    app, repo, _ := newTestApp(t)
+    source := createSource(t, repo, models.Source{Name: "Example", URL: "https://example.com/", SelectorTitle: "h2"})
+    err := repo.SaveArticle(context.Background(), &models.Article{
+        SourceID: source.ID, SourceName: source.Name, Title: "Stored headline",
+        URL: "https://example.com/news/1", Category: "general",
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name   string
+        target string
+        want   string
+    }{
+        {"articles of the source", fmt.Sprintf("/api/articles/source/%d", source.ID), "Stored headline"},
+        {"invalid source ID", "/api/articles/source/abc", "Invalid source ID"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            status, body := do(t, app, http.MethodGet, tt.target)
+            if status != http.StatusOK || !strings.Contains(body, tt.want) {
+                t.Errorf("status %d, %q missing from %s", status, tt.want, body)
+            }
+        })
+    }
+}
+
+func TestGetDeadJobs(t *testing.T) {
+    app, repo, _ := newTestApp(t)
+    ctx := context.Background()
+    if _, err := repo.EnqueueJob(ctx, models.JobFetchArticle, "dead", `{"url":"https://example.com/"}`, 1); err != nil {
+        t.Fatal(err)
+    }
+    jobs, err := repo.LeaseJobs(ctx, 1, time.Minute)
+    if err != nil || len(jobs) != 1 {
+        t.Fatalf("LeaseJobs: %v, %d jobs", err, len(jobs))
+    }
+    if err := repo.FailJob(ctx, jobs[0], fmt.Errorf("HTTP 500"), time.Now()); err != nil {
+        t.Fatal(err)
+    }
+
+    status, body := do(t, app, http.MethodGet, "/api/jobs/dead")
+    if status != http.StatusOK || !strings.Contains(body, "HTTP 500") {
+        t.Errorf("status %d, dead job missing from %s", status, body)
+    }
+}
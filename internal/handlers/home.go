@@ -8,10 +8,10 @@ import (
 )
 
 type HomeHandler struct {
-	repo *database.Repository
+	repo database.Repository
 }
 
-func NewHomeHandler(repo *database.Repository) *HomeHandler {
+func NewHomeHandler(repo database.Repository) *HomeHandler {
 	return &HomeHandler{repo: repo}
 }
 
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"news-scraper/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JobsHandler struct {
+    repo database.Repository
+}
+
+func NewJobsHandler(repo database.Repository) *JobsHandler {
+    return &JobsHandler{repo: repo}
+}
+
+// GetDead returns dead-lettered jobs as JSON so failing articles can be inspected
+func (h *JobsHandler) GetDead(c *fiber.Ctx) error {
+    limit := c.QueryInt("limit", 100)
+
+    jobs, err := h.repo.GetDeadJobs(c.Context(), limit)
+    if err != nil {
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to fetch jobs",
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "jobs": jobs,
+    })
+}
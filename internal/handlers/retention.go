@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"news-scraper/internal/retention"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RetentionHandler struct {
+    retention *retention.Service
+}
+
+func NewRetentionHandler(retention *retention.Service) *RetentionHandler {
+    return &RetentionHandler{retention: retention}
+}
+
+// GetReport returns a dry-run report of what the retention policy would remove
+// Nothing is archived or deleted.
+func (h *RetentionHandler) GetReport(c *fiber.Ctx) error {
+    report, err := h.retention.Run(c.Context(), true)
+    if err != nil {
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to evaluate retention policy",
+        })
+    }
+
+    return c.JSON(report)
+}
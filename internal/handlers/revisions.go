@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"strconv"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/textdiff"
+	"news-scraper/web/templates"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RevisionsHandler struct {
+    repo database.Repository
+}
+
+func NewRevisionsHandler(repo database.Repository) *RevisionsHandler {
+    return &RevisionsHandler{repo: repo}
+}
+
+// GetRevisions returns every stored version of an article as JSON, oldest first
+func (h *RevisionsHandler) GetRevisions(c *fiber.Ctx) error {
+    id, err := strconv.Atoi(c.Params("id"))
+    if err != nil {
+        return c.Status(400).JSON(fiber.Map{
+            "error": "Invalid article ID",
+        })
+    }
+
+    revisions, err := h.repo.GetArticleRevisions(c.Context(), id)
+    if err != nil {
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to fetch revisions",
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "article_id": id,
+        "revisions":  revisions,
+    })
+}
+
+// RenderHistory renders the revision diffs of an article, newest first
+func (h *RevisionsHandler) RenderHistory(c *fiber.Ctx) error {
+    c.Set("Content-Type", "text/html")
+
+    id, err := strconv.Atoi(c.Params("id"))
+    if err != nil {
+        return templates.ErrorMessage("Invalid article ID").Render(c.Context(), c.Response().BodyWriter())
+    }
+
+    articles, err := h.repo.GetArticlesByIDs(c.Context(), []int{id})
+    if err != nil || len(articles) == 0 {
+        c.Status(404)
+        return templates.ErrorMessage("Article not found").Render(c.Context(), c.Response().BodyWriter())
+    }
+
+    revisions, err := h.repo.GetArticleRevisions(c.Context(), id)
+    if err != nil {
+        return templates.ErrorMessage("Failed to load revisions").Render(c.Context(), c.Response().BodyWriter())
+    }
+
+    return templates.ArticleHistory(articles[0], revisionChanges(revisions)).Render(c.Context(), c.Response().BodyWriter())
+}
+
+// revisionChanges diffs each revision against the previous one, newest first
+func revisionChanges(revisions []models.ArticleRevision) []templates.RevisionChange {
+    changes := make([]templates.RevisionChange, 0, len(revisions))
+    if len(revisions) == 0 {
+        return changes
+    }
+
+    // The first revision is diffed against itself so it shows as plain text
+    prev := revisions[0]
+    for i, rev := range revisions {
+        changes = append(changes, templates.RevisionChange{
+            Revision: rev,
+            First:    i == 0,
+            Title:    textdiff.Words(prev.Title, rev.Title),
+            Summary:  textdiff.Words(prev.Summary, rev.Summary),
+            Body:     textdiff.Paragraphs(prev.Body, rev.Body),
+        })
+        prev = rev
+    }
+
+    for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+        changes[i], changes[j] = changes[j], changes[i]
+    }
+    return changes
+}
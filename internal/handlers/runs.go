@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"news-scraper/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RunsHandler struct {
+    repo database.Repository
+}
+
+func NewRunsHandler(repo database.Repository) *RunsHandler {
+    return &RunsHandler{repo: repo}
+}
+
+// GetRuns returns the scrape history as JSON, newest first, with the URLs
+// robots.txt kept each run from fetching
+// Query parameters: source_id (default all sources), limit (default 50)
+func (h *RunsHandler) GetRuns(c *fiber.Ctx) error {
+    limit := min(c.QueryInt("limit", 50), 500)
+
+    runs, err := h.repo.GetScrapeRuns(c.Context(), c.QueryInt("source_id", 0), limit)
+    if err != nil {
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to fetch scrape runs",
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "runs": runs,
+    })
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log"
 	"news-scraper/internal/scraper"
 	"news-scraper/web/templates"
@@ -23,7 +24,29 @@ func NewScrapeHandler(scraper *scraper.Scraper) *ScrapeHandler {
 // 1. c.Context() is cancelled when HTTP response is sent
 // 2. Scraping continues in background goroutine after response
 // 3. If we used c.Context(), scraping would be cancelled immediately
+// The scraper's own Shutdown and per-source deadlines bound the run instead.
+// With ?wait=true the scrape runs in the request instead and its report
+// is returned as JSON, with the joined errors of the sources that failed.
 func (h *ScrapeHandler) TriggerScrape(c *fiber.Ctx) error {
+    if c.QueryBool("wait") {
+        report, err := h.scraper.ScrapeAll(c.UserContext())
+        if errors.Is(err, scraper.ErrShuttingDown) {
+            return c.Status(503).JSON(fiber.Map{
+                "error": err.Error(),
+            })
+        }
+        if report == nil {
+            return c.Status(500).JSON(fiber.Map{
+                "error": err.Error(),
+            })
+        }
+        response := fiber.Map{"report": report}
+        if err != nil {
+            response["error"] = err.Error()
+        }
+        return c.JSON(response)
+    }
+
     // Start scraping in background goroutine
     go func() {
         // if err := h.scraper.ScrapeAll(c.Context()); err != nil {
@@ -32,7 +55,7 @@ func (h *ScrapeHandler) TriggerScrape(c *fiber.Ctx) error {
 
         // Create new context that won't be cancelled when HTTP response completes
         ctx := context.Background()// Don't use c.Context() in goroutine
-        if err := h.scraper.ScrapeAll(ctx); err != nil {
+        if _, err := h.scraper.ScrapeAll(ctx); err != nil {
             log.Printf("Scraping failed: %v", err)
         } else {
             log.Println("Background scraping completed successfully")
@@ -47,3 +70,13 @@ func (h *ScrapeHandler) TriggerScrape(c *fiber.Ctx) error {
 
 
 }
+
+// GetRateLimits reports how long requests have waited on each host's rate limit
+func (h *ScrapeHandler) GetRateLimits(c *fiber.Ctx) error {
+    return c.JSON(fiber.Map{"hosts": h.scraper.RateLimitStats()})
+}
+
+// GetProxies reports the health of every configured proxy
+func (h *ScrapeHandler) GetProxies(c *fiber.Ctx) error {
+    return c.JSON(fiber.Map{"proxies": h.scraper.ProxyStats()})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"log"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/sentiment"
+	"news-scraper/web/templates"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SentimentHandler struct {
+    repo database.Repository
+}
+
+func NewSentimentHandler(repo database.Repository) *SentimentHandler {
+    return &SentimentHandler{repo: repo}
+}
+
+// GetSentiment returns average sentiment per source, category and day as JSON
+// Query parameters: days (default 30), entity (person, organization or place), q (text in title or summary)
+func (h *SentimentHandler) GetSentiment(c *fiber.Ctx) error {
+    report, err := sentiment.Aggregate(c.Context(), h.repo, sentimentOptions(c))
+    if err != nil {
+        log.Printf("Error aggregating sentiment: %v", err)
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to aggregate sentiment",
+        })
+    }
+
+    return c.JSON(report)
+}
+
+// RenderDashboard renders the sentiment dashboard
+func (h *SentimentHandler) RenderDashboard(c *fiber.Ctx) error {
+    c.Set("Content-Type", "text/html")
+
+    report, err := sentiment.Aggregate(c.Context(), h.repo, sentimentOptions(c))
+    if err != nil {
+        log.Printf("Error aggregating sentiment: %v", err)
+        return templates.ErrorMessage("Failed to aggregate sentiment").Render(c.Context(), c.Response().BodyWriter())
+    }
+
+    return templates.SentimentDashboard(report).Render(c.Context(), c.Response().BodyWriter())
+}
+
+func sentimentOptions(c *fiber.Ctx) sentiment.Options {
+    return sentiment.Options{
+        Days:   min(c.QueryInt("days", 30), 365),
+        Entity: c.Query("entity"),
+        Query:  c.Query("q"),
+    }
+}
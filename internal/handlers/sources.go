@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"news-scraper/internal/circuit"
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/scraper"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SourcesHandler struct {
+    repo    database.Repository
+    scraper *scraper.Scraper
+}
+
+func NewSourcesHandler(repo database.Repository, scraper *scraper.Scraper) *SourcesHandler {
+    return &SourcesHandler{repo: repo, scraper: scraper}
+}
+
+// sourceStatus is a source with the state of its circuit breaker
+type sourceStatus struct {
+    models.Source
+    Circuit circuit.Status `json:"circuit"`
+}
+
+// GetSources returns every source as JSON with its circuit breaker state
+func (h *SourcesHandler) GetSources(c *fiber.Ctx) error {
+    sources, err := h.repo.GetSources(c.Context())
+    if err != nil {
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to fetch sources",
+        })
+    }
+
+    statuses := make([]sourceStatus, 0, len(sources))
+    for _, source := range sources {
+        statuses = append(statuses, sourceStatus{
+            Source:  source,
+            Circuit: h.scraper.CircuitStatus(source.ID),
+        })
+    }
+
+    return c.JSON(fiber.Map{
+        "sources": statuses,
+    })
+}
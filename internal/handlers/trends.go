@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/trends"
+	"news-scraper/web/templates"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TrendsHandler struct {
+    repo database.Repository
+}
+
+func NewTrendsHandler(repo database.Repository) *TrendsHandler {
+    return &TrendsHandler{repo: repo}
+}
+
+// GetTrends returns top and rising terms as JSON
+// Query parameters: hours (window, default 24), baseline_days (default 7), limit (default 20)
+func (h *TrendsHandler) GetTrends(c *fiber.Ctx) error {
+    report, err := trends.Analyze(c.Context(), h.repo, trendOptions(c))
+    if err != nil {
+        log.Printf("Error analyzing trends: %v", err)
+        return c.Status(500).JSON(fiber.Map{
+            "error": "Failed to analyze trends",
+        })
+    }
+
+    return c.JSON(report)
+}
+
+// RenderDashboard renders the trends dashboard
+func (h *TrendsHandler) RenderDashboard(c *fiber.Ctx) error {
+    c.Set("Content-Type", "text/html")
+
+    report, err := trends.Analyze(c.Context(), h.repo, trendOptions(c))
+    if err != nil {
+        log.Printf("Error analyzing trends: %v", err)
+        return templates.ErrorMessage("Failed to analyze trends").Render(c.Context(), c.Response().BodyWriter())
+    }
+
+    return templates.TrendsDashboard(report).Render(c.Context(), c.Response().BodyWriter())
+}
+
+func trendOptions(c *fiber.Ctx) trends.Options {
+    return trends.Options{
+        Window:   time.Duration(min(c.QueryInt("hours", 24), 24*7)) * time.Hour,
+        Baseline: time.Duration(min(c.QueryInt("baseline_days", 7), 90)) * 24 * time.Hour,
+        Limit:    min(c.QueryInt("limit", 20), 100),
+    }
+}
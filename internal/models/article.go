@@ -3,15 +3,31 @@ package models
 import "time"
 
 type Article struct {
-    ID         int       `json:"id"`
-    SourceID   int       `json:"source_id"`
-    SourceName string    `json:"source_name"`
-    Title      string    `json:"title"`
-    URL        string    `json:"url"`
-    Summary    string    `json:"summary"`
-    Category   string    `json:"category"`
-    ScrapedAt  time.Time `json:"scraped_at"`
-    CreatedAt  time.Time `json:"created_at"`
+    ID          int       `json:"id"`
+    SourceID    int       `json:"source_id"`
+    SourceName  string    `json:"source_name"`
+    Title       string    `json:"title"`
+    URL         string    `json:"url"` // as linked by the source; what is fetched and shown
+    // CanonicalURL is the normalized form of URL that articles are
+    // deduplicated on; repositories fill it in from URL when empty
+    CanonicalURL string   `json:"-"`
+    Summary     string    `json:"summary"`
+    // SummaryGenerated is set when Summary was extracted from Body rather
+    // than published by the source
+    SummaryGenerated bool `json:"summary_generated"`
+    Category    string    `json:"category"`
+    Language    string    `json:"language,omitempty"` // ISO 639-1, "" when unknown
+    // Sentiment is the tone of Title and Summary from -1 to 1, nil until scored
+    Sentiment   *float64  `json:"sentiment,omitempty"`
+    Body        string    `json:"body,omitempty"`
+    ImageURL    string    `json:"image_url,omitempty"`
+    Author      string    `json:"author,omitempty"`
+    PublishedAt time.Time `json:"published_at,omitzero"` // from the article page, zero when unknown
+    StoryID     int       `json:"story_id,omitempty"`
+    Entities    []Entity  `json:"entities,omitempty"` // only filled in for display
+    Fingerprint uint64    `json:"-"`
+    ScrapedAt   time.Time `json:"scraped_at"`
+    CreatedAt   time.Time `json:"created_at"`
 }
 
 type Source struct {
@@ -21,8 +37,73 @@ type Source struct {
     SelectorTitle   string    `json:"selector_title"`
     SelectorLink    string    `json:"selector_link"`
     SelectorSummary string    `json:"selector_summary"`
+    SelectorBody    string    `json:"selector_body"`
+    KeepQueryParams string    `json:"keep_query_params"` // comma-separated; when set, all other query params are dropped
+    IgnoreRobots    bool      `json:"ignore_robots"`     // skip robots.txt and Crawl-delay
+    ProxyPool       string    `json:"proxy_pool"`        // name of a configured proxy pool; empty = direct
+    UserAgent       string    `json:"user_agent"`        // overrides scraper.user_agent
+    TimeoutSeconds  int       `json:"timeout_seconds"`   // overrides scraper.timeout; 0 = default
+    DeadlineSeconds int       `json:"deadline_seconds"`  // overrides scraper.source_timeout for a whole scrape; 0 = default
+    // Headers and Cookies may carry session tokens, so they are never serialized
+    Headers         map[string]string `json:"-"` // sent with every request, e.g. Accept-Language
+    Cookies         string    `json:"-"`                 // seeded into the cookie jar, "name=value; other=value"
+    SelectorNext    string    `json:"selector_next"`     // link to the next listing page
+    PageURLTemplate string    `json:"page_url_template"` // listing page n, e.g. "?page={n}"; wins over SelectorNext
+    MaxPages        int       `json:"max_pages"`         // listing pages per run; 0 = 5 when paginated, else 1
+    StopAfterKnown  int       `json:"stop_after_known"`  // stop after this many known articles in a row; 0 = off
     DefaultCategory string    `json:"dafault_category"`
     Active          bool      `json:"active"`
     CreatedAt       time.Time `json:"created_at"`
     UpdatedAt       time.Time `json:"updated_at"`
 }
+
+// SaveResult counts what a batch save did with each article
+// Unchanged articles matched the stored title, summary and category.
+type SaveResult struct {
+    Inserted  int `json:"inserted"`
+    Updated   int `json:"updated"`
+    Unchanged int `json:"unchanged"`
+}
+
+// SentimentCount is the tone of one source's articles in one category,
+// first seen on one UTC day (Day is YYYY-MM-DD). Sum adds up the Scored
+// articles' sentiment; articles without a score only count in Articles.
+type SentimentCount struct {
+    SourceName string
+    Category   string
+    Day        string
+    Articles   int
+    Scored     int
+    Sum        float64
+    Positive   int
+    Neutral    int
+    Negative   int
+}
+
+// ArticleRevision is one distinct version of an article's title, summary and body
+type ArticleRevision struct {
+    ID          int64     `json:"id"`
+    ArticleID   int       `json:"article_id"`
+    Title       string    `json:"title"`
+    Summary     string    `json:"summary"`
+    Body        string    `json:"body,omitempty"`
+    ContentHash string    `json:"content_hash"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+// Entity types
+const (
+    EntityPerson       = "person"
+    EntityOrganization = "organization"
+    EntityPlace        = "place"
+)
+
+// Entity is a person, organization or place mentioned in an article
+// NormalizedName is the lookup key: lowercase, with aliases resolved and
+// possessives dropped, so "U.S." and "United States'" are the same entity.
+type Entity struct {
+    Type           string `json:"type"`
+    Name           string `json:"name"`
+    NormalizedName string `json:"normalized_name"`
+    Mentions       int    `json:"mentions"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Job kinds handled by the article worker pool
+const (
+    JobFetchArticle = "fetch_article"
+)
+
+// Job statuses
+// pending -> leased -> done
+// pending -> leased -> pending (retry with backoff) -> ... -> dead
+const (
+    JobPending = "pending"
+    JobLeased  = "leased"
+    JobDone    = "done"
+    JobDead    = "dead"
+)
+
+type Job struct {
+    ID          int64     `json:"id"`
+    Kind        string    `json:"kind"`
+    DedupeKey   string    `json:"dedupe_key"`
+    Payload     string    `json:"payload"`
+    Status      string    `json:"status"`
+    Attempts    int       `json:"attempts"`
+    MaxAttempts int       `json:"max_attempts"`
+    LastError   string    `json:"last_error"`
+    LeaseToken  string    `json:"-"`
+    RunAfter    time.Time `json:"run_after"`
+    CreatedAt   time.Time `json:"created_at"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FetchArticlePayload is the JSON payload of a JobFetchArticle job
+type FetchArticlePayload struct {
+    SourceID     int    `json:"source_id"`
+    URL          string `json:"url"`                     // fetched
+    CanonicalURL string `json:"canonical_url,omitempty"` // the article's key; URL in older jobs
+}
+
+// ArticleContent holds what the worker pool extracts from an article page
+type ArticleContent struct {
+    Body        string
+    Description string
+    ImageURL    string
+    Author      string
+    PublishedAt time.Time
+
+    // Summary is extracted from Body, for pages without a description
+    Summary string
+
+    // Language is detected from Body and the page's lang attribute
+    Language string
+
+    // CanonicalURL is the page's rel=canonical URL after canonicalization, if any
+    CanonicalURL string
+
+    // Entities are found in the page's headline and Body
+    Entities []Entity
+}
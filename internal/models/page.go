@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PageCache is what the last successful fetch of a listing page returned
+// ETag and LastModified are sent back as If-None-Match and
+// If-Modified-Since; ContentHash (SHA-256 of the body) catches pages that
+// change their validators on every request but not their content.
+type PageCache struct {
+    SourceID     int       `json:"source_id"`
+    URL          string    `json:"url"`
+    ETag         string    `json:"etag,omitempty"`
+    LastModified string    `json:"last_modified,omitempty"`
+    ContentHash  string    `json:"content_hash,omitempty"`
+    CheckedAt    time.Time `json:"checked_at"`
+}
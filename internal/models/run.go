@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Scrape run statuses
+const (
+    RunOK     = "ok"
+    RunFailed = "failed"
+    RunTimeout = "timeout" // the source's deadline or a request timeout ran out
+    RunSkipped = "skipped" // the source's circuit breaker was open or its proxy pool exhausted
+    RunUnchanged = "unchanged" // the listing page was not modified since the last run
+    RunCancelled = "cancelled" // the scrape was stopped, e.g. by a shutdown
+)
+
+// Classes of a run's error
+const (
+    ErrorKindNetwork   = "network"     // no response: DNS, connection refused or reset
+    ErrorKindTimeout   = "timeout"     // the source's deadline or a request timeout ran out
+    ErrorKindHTTP      = "http_status" // the site answered with an error status
+    ErrorKindSelector  = "selector"    // the listing page matched no articles
+    ErrorKindDatabase  = "database"    // reading or saving articles failed
+    ErrorKindRobots    = "robots"      // robots.txt disallows the listing page
+    ErrorKindCircuit   = "circuit_open"
+    ErrorKindProxy     = "no_proxy"    // every proxy of the source's pool was ejected
+    ErrorKindCancelled = "cancelled"   // the scrape was stopped, e.g. by a shutdown
+    ErrorKindOther     = "other"
+)
+
+// ScrapeRun is the history record of one scrape of one source
+// BlockedURLs are the listing and article pages robots.txt kept the run from fetching.
+type ScrapeRun struct {
+    ID            int64     `json:"id"`
+    SourceID      int       `json:"source_id"`
+    SourceName    string    `json:"source_name"`
+    Status        string    `json:"status"`
+    ArticlesFound int       `json:"articles_found"`
+    ArticlesNew   int       `json:"articles_new"`   // found and not stored before
+    ArticlesKnown int       `json:"articles_known"` // found and already stored
+    ArticlesUpdated int     `json:"articles_updated"` // known articles whose title, summary or category changed
+    Error         string    `json:"error,omitempty"`
+    ErrorKind     string    `json:"error_kind,omitempty"`
+    BlockedURLs   []string  `json:"blocked_urls,omitempty"`
+    StartedAt     time.Time `json:"started_at"`
+    FinishedAt    time.Time `json:"finished_at"`
+}
@@ -0,0 +1,474 @@
+package nlp
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"news-scraper/internal/models"
+)
+
+// MaxEntities caps how many entities are kept per article, most mentioned first
+const MaxEntities = 20
+
+// maxNameTokens is the longest name considered; longer capitalized runs are
+// headlines or lists, not names
+const maxNameTokens = 6
+
+// ExtractEntities finds the people, organizations and places an article mentions
+// It is a rule and dictionary based recognizer: a gazetteer of well-known
+// names (with aliases, and Hindi spellings) is matched first; other runs of
+// capitalized words are classified by cues such as a preceding title
+// ("Prime Minister", "coach"), an organization or place suffix ("Bank",
+// "Pradesh"), a following "said", a common first name, or a preceding
+// "in". Surnames mentioned on their own are counted towards the full name.
+// A title-cased headline carries no case information, so only names known
+// from the gazetteer or the body are taken from it.
+func ExtractEntities(headline, body string) []models.Entity {
+    x := &extractor{found: make(map[string]*found)}
+    x.scan(tokenize(body))
+    if titleCased(headline) {
+        x.scanKnown(tokenize(headline))
+    } else {
+        x.scan(tokenize(headline))
+    }
+    x.resolve()
+    return x.entities()
+}
+
+// NormalizeEntityName returns the key an entity name is stored and looked up by
+func NormalizeEntityName(name string) string {
+    key := entityKey(strings.Fields(name))
+    if e, ok := gazetteer[key]; ok {
+        return entityKey(strings.Fields(e.name))
+    }
+    return key
+}
+
+// entityKey lowercases words and drops periods and possessives, so
+// "U.S." is "us" and "India's" is "india"
+func entityKey(words []string) string {
+    keys := make([]string, 0, len(words))
+    for _, w := range words {
+        w = strings.ToLower(trimPossessive(w))
+        w = strings.ReplaceAll(w, ".", "")
+        if w != "" {
+            keys = append(keys, w)
+        }
+    }
+    return strings.Join(keys, " ")
+}
+
+func trimPossessive(w string) string {
+    for _, suffix := range []string{"'s", "’s"} {
+        if strings.HasSuffix(w, suffix) {
+            return strings.TrimSuffix(w, suffix)
+        }
+    }
+    return strings.TrimRight(w, "'’")
+}
+
+type token struct {
+    word       string // without surrounding punctuation or possessive
+    key        string // entityKey of word
+    capital    bool   // starts with an uppercase letter
+    caseless   bool   // written in a script without case, like Devanagari
+    sentStart  bool   // first word of a sentence
+    breakAfter bool   // followed by punctuation, so a name can't continue
+}
+
+// trailing is punctuation that may follow a word
+const trailing = `.,;:!?)]}"'”’…।`
+
+// tokenize splits text into words, remembering sentence starts and punctuation
+func tokenize(text string) []token {
+    var tokens []token
+    for _, line := range strings.Split(text, "\n") {
+        start := true
+        for _, field := range strings.Fields(line) {
+            word := strings.TrimLeft(field, `"'“‘([{`)
+            core := strings.TrimRight(word, trailing)
+            trail := word[len(core):]
+            if strings.HasPrefix(trail, ".") && strings.Contains(core, ".") {
+                // Keep the final period of an acronym like "U.S."
+                core += "."
+                trail = trail[1:]
+            }
+            stripped := trimPossessive(core)
+
+            if stripped == "" || !unicode.IsLetter(firstRune(stripped)) && !unicode.IsDigit(firstRune(stripped)) {
+                // A dash or a stray symbol: no name runs across it
+                if len(tokens) > 0 {
+                    tokens[len(tokens)-1].breakAfter = true
+                }
+                continue
+            }
+
+            r := firstRune(stripped)
+            t := token{
+                word:       stripped,
+                key:        entityKey([]string{stripped}),
+                capital:    unicode.IsUpper(r),
+                caseless:   !unicode.IsUpper(r) && !unicode.IsLower(r),
+                sentStart:  start,
+                breakAfter: trail != "" || stripped != core,
+            }
+            tokens = append(tokens, t)
+            start = strings.ContainsAny(trail, ".!?।") && !abbreviated(core)
+        }
+        if len(tokens) > 0 {
+            tokens[len(tokens)-1].breakAfter = true
+        }
+    }
+    return tokens
+}
+
+// abbreviated reports whether a period after word belongs to the word
+func abbreviated(word string) bool {
+    if strings.Contains(word, ".") || len([]rune(word)) == 1 {
+        return true
+    }
+    return titleAbbreviations[strings.ToLower(word)]
+}
+
+func firstRune(s string) rune {
+    for _, r := range s {
+        return r
+    }
+    return 0
+}
+
+// titleCased reports whether most longer words of a headline are capitalized
+func titleCased(headline string) bool {
+    words, capitals := 0, 0
+    for _, w := range strings.Fields(headline) {
+        if len([]rune(w)) <= 3 {
+            continue
+        }
+        words++
+        if unicode.IsUpper(firstRune(w)) {
+            capitals++
+        }
+    }
+    return words >= 3 && capitals*3 >= words*2
+}
+
+// found is an entity with its mentions so far
+type found struct {
+    models.Entity
+    first int // order of first mention
+}
+
+// candidate is a capitalized name no rule could classify
+type candidate struct {
+    name string
+    key  string
+}
+
+type extractor struct {
+    found      map[string]*found
+    order      int
+    candidates []candidate
+}
+
+func (x *extractor) add(typ, name string) {
+    name = strings.Trim(name, " -")
+    key := NormalizeEntityName(name)
+    if key == "" {
+        return
+    }
+    if e, ok := gazetteer[entityKey(strings.Fields(name))]; ok {
+        typ, name = e.typ, e.name
+    }
+
+    id := typ + "\x00" + key
+    if f, ok := x.found[id]; ok {
+        f.Mentions++
+        return
+    }
+    x.order++
+    x.found[id] = &found{
+        Entity: models.Entity{Type: typ, Name: name, NormalizedName: key, Mentions: 1},
+        first:  x.order,
+    }
+}
+
+// scan finds entities in running text
+func (x *extractor) scan(tokens []token) {
+    for i := 0; i < len(tokens); {
+        t := tokens[i]
+        if t.caseless {
+            if n := gazetteerMatch(tokens[i:]); n > 0 {
+                x.add("", joinWords(tokens[i:i+n]))
+                i += n
+                continue
+            }
+            i++
+            continue
+        }
+        if !t.capital {
+            i++
+            continue
+        }
+        // Gazetteer names may have lowercase words: "Jammu and Kashmir"
+        if n := gazetteerMatch(tokens[i:]); n > 1 {
+            x.add("", joinWords(tokens[i:i+n]))
+            i += n
+            continue
+        }
+
+        end := runEnd(tokens, i)
+        x.scanRun(tokens, i, end)
+        i = end
+    }
+}
+
+// runEnd returns the end of the capitalized run starting at i
+// Connectors like "of" and "the" are included between capitalized words,
+// as in "Reserve Bank of India".
+func runEnd(tokens []token, i int) int {
+    j := i + 1
+    for j < len(tokens) && !tokens[j-1].breakAfter {
+        switch {
+        case tokens[j].capital:
+            j++
+        case connectors[tokens[j].word] && !tokens[j].breakAfter && j+1 < len(tokens) && tokens[j+1].capital:
+            j += 2
+        default:
+            return j
+        }
+    }
+    return j
+}
+
+// scanRun classifies the names in the capitalized run tokens[i:end]
+func (x *extractor) scanRun(tokens []token, i, end int) {
+    personCue := i > 0 && !tokens[i-1].breakAfter && personTitles[tokens[i-1].key]
+
+    // "General Motors" is a company, not a general
+    if organizationSuffixes[tokens[end-1].key] && gazetteerMatch(tokens[i:end]) < end-i {
+        x.classify(tokens, i, end, false)
+        return
+    }
+
+    for pos := i; pos < end; {
+        if n := gazetteerMatch(tokens[pos:end]); n > 0 {
+            x.add("", joinWords(tokens[pos:pos+n]))
+            pos += n
+            personCue = false
+            continue
+        }
+        if personTitles[tokens[pos].key] {
+            personCue = true
+            pos++
+            continue
+        }
+
+        // The name runs to the next title or known name
+        k := pos + 1
+        for k < end && !personTitles[tokens[k].key] && gazetteerMatch(tokens[k:end]) == 0 {
+            k++
+        }
+        x.classify(tokens, pos, k, personCue)
+        personCue = false
+        pos = k
+    }
+}
+
+// classify decides what the name tokens[i:end] is, if anything
+func (x *extractor) classify(tokens []token, i, end int, personCue bool) {
+    // Leading stopwords are sentence starts: "After Modi"
+    for i < end && (stopwords[tokens[i].key] || connectors[tokens[i].word]) {
+        i++
+    }
+    for end > i && connectors[tokens[end-1].word] {
+        end--
+    }
+    n := end - i
+    if n == 0 || n > maxNameTokens {
+        return
+    }
+
+    name := joinWords(tokens[i:end])
+    first, last := tokens[i], tokens[end-1]
+    var prev, next string
+    if i > 0 && !tokens[i-1].breakAfter {
+        prev = tokens[i-1].key
+    }
+    if end < len(tokens) && !last.breakAfter {
+        next = tokens[end].key
+    }
+
+    switch {
+    case n == 1 && nonNames[first.key]:
+        return
+    case organizationSuffixes[last.key] && n > 1, organizationHeads[first.key] && n > 2:
+        x.add(models.EntityOrganization, name)
+    case placeSuffixes[last.key] && n > 1:
+        x.add(models.EntityPlace, name)
+    case personCue:
+        x.add(models.EntityPerson, name)
+    case n == 1 && isAcronym(first.word):
+        x.add(models.EntityOrganization, name)
+    case n <= 3 && speechVerbs[next] && (n > 1 || !first.sentStart):
+        // A sentence may start with an ordinary word ("Later Pichai said"),
+        // and "Officials said" is not a name at all
+        if first.sentStart && !firstNames[first.key] {
+            name = joinWords(tokens[i+1 : end])
+        }
+        x.add(models.EntityPerson, name)
+    case n >= 2 && n <= 3 && firstNames[first.key]:
+        x.add(models.EntityPerson, name)
+    case n <= 3 && placePrepositions[prev] && !first.sentStart:
+        x.add(models.EntityPlace, name)
+    default:
+        // Only kept if it repeats a name found elsewhere
+        x.candidates = append(x.candidates, candidate{name: name, key: entityKey(strings.Fields(name))})
+    }
+}
+
+// scanKnown finds names already known from the body, or from the gazetteer,
+// in text without case information
+func (x *extractor) scanKnown(tokens []token) {
+    known := make(map[string]string)
+    for _, f := range x.found {
+        known[f.NormalizedName] = f.Type
+        if f.Type == models.EntityPerson {
+            // The surname alone
+            words := strings.Fields(f.NormalizedName)
+            known[words[len(words)-1]] = f.Type
+        }
+    }
+
+    for i := 0; i < len(tokens); {
+        if n := gazetteerMatch(tokens[i:]); n > 0 {
+            x.add("", joinWords(tokens[i:i+n]))
+            i += n
+            continue
+        }
+        matched := 0
+        for n := min(maxNameTokens, len(tokens)-i); n > 0 && matched == 0; n-- {
+            if typ, ok := known[entityKey(words(tokens[i:i+n]))]; ok && tokens[i].capital {
+                x.mention(typ, entityKey(words(tokens[i:i+n])))
+                matched = n
+            }
+        }
+        i += max(matched, 1)
+    }
+}
+
+// mention counts another mention of a found entity, by full name or surname
+func (x *extractor) mention(typ, key string) bool {
+    for _, f := range x.found {
+        if f.Type != typ {
+            continue
+        }
+        words := strings.Fields(f.NormalizedName)
+        if f.NormalizedName == key || typ == models.EntityPerson && len(words) > 1 && words[len(words)-1] == key {
+            f.Mentions++
+            return true
+        }
+    }
+    return false
+}
+
+// resolve counts unclassified names that repeat a found entity
+// A surname on its own ("Modi") is the person named in full elsewhere.
+func (x *extractor) resolve() {
+    for _, c := range x.candidates {
+        for _, typ := range []string{models.EntityPerson, models.EntityOrganization, models.EntityPlace} {
+            if x.mention(typ, c.key) {
+                break
+            }
+        }
+    }
+
+    // A surname that was classified on its own ("Pichai said") joins the full name too
+    for id, f := range x.found {
+        if f.Type != models.EntityPerson || strings.Contains(f.NormalizedName, " ") {
+            continue
+        }
+        for _, full := range x.found {
+            words := strings.Fields(full.NormalizedName)
+            if full.Type == models.EntityPerson && len(words) > 1 && words[len(words)-1] == f.NormalizedName {
+                full.Mentions += f.Mentions
+                full.first = min(full.first, f.first)
+                delete(x.found, id)
+                break
+            }
+        }
+    }
+}
+
+func (x *extractor) entities() []models.Entity {
+    list := make([]*found, 0, len(x.found))
+    for _, f := range x.found {
+        list = append(list, f)
+    }
+    sort.Slice(list, func(i, j int) bool {
+        if list[i].Mentions != list[j].Mentions {
+            return list[i].Mentions > list[j].Mentions
+        }
+        return list[i].first < list[j].first
+    })
+    if len(list) > MaxEntities {
+        list = list[:MaxEntities]
+    }
+
+    entities := make([]models.Entity, len(list))
+    for i, f := range list {
+        entities[i] = f.Entity
+    }
+    return entities
+}
+
+func words(tokens []token) []string {
+    w := make([]string, len(tokens))
+    for i, t := range tokens {
+        w[i] = t.word
+    }
+    return w
+}
+
+func joinWords(tokens []token) string {
+    return strings.Join(words(tokens), " ")
+}
+
+// isAcronym reports whether a word is two to six capital letters, like "ISRO"
+func isAcronym(word string) bool {
+    word = strings.ReplaceAll(word, ".", "")
+    n := 0
+    for _, r := range word {
+        if !unicode.IsUpper(r) {
+            return false
+        }
+        n++
+    }
+    return n >= 2 && n <= 6
+}
+
+// gazetteerMatch returns the length of the longest gazetteer name at the
+// start of tokens, or 0
+func gazetteerMatch(tokens []token) int {
+    for n := min(gazetteerMaxTokens, len(tokens)); n > 0; n-- {
+        // A name can't run across punctuation
+        broken := false
+        for _, t := range tokens[:n-1] {
+            broken = broken || t.breakAfter
+        }
+        if broken {
+            continue
+        }
+        if _, ok := gazetteer[entityKey(words(tokens[:n]))]; ok {
+            if !tokens[0].caseless && !tokens[0].capital {
+                continue
+            }
+            // Short aliases only count in capitals: "US" but not "Us", "WHO" but not "Who"
+            if t := tokens[0]; n == 1 && !t.caseless && !isAcronym(t.word) && (len(t.key) <= 2 || stopwords[t.key]) {
+                continue
+            }
+            return n
+        }
+    }
+    return 0
+}
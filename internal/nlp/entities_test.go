@@ -0,0 +1,143 @@
+package nlp
+
+import (
+	"testing"
+
+	"news-scraper/internal/models"
+)
+
+func TestExtractEntities(t *testing.T) {
+    const (
+        person = models.EntityPerson
+        org    = models.EntityOrganization
+        place  = models.EntityPlace
+    )
+
+    tests := []struct {
+        name     string
+        headline string
+        body     string
+        want     map[string]string // name -> type
+        not      []string
+    }{
+        {
+            name: "gazetteer aliases",
+            body: "The RBI kept rates unchanged. Officials in the U.S. and Bharat welcomed the move.",
+            want: map[string]string{"Reserve Bank of India": org, "United States": place, "India": place},
+            not:  []string{"Officials"},
+        },
+        {
+            name: "hindi spellings",
+            body: "नरेंद्र मोदी ने दिल्ली में भाजपा की बैठक की।",
+            want: map[string]string{"Narendra Modi": person, "Delhi": place, "Bharatiya Janata Party": org},
+        },
+        {
+            name: "gazetteer name with a lowercase word",
+            body: "Polling ended in Jammu and Kashmir on Monday.",
+            want: map[string]string{"Jammu and Kashmir": place},
+        },
+        {
+            name: "person title cue",
+            body: "The match was won after coach Rahul Dravid changed the batting order.",
+            want: map[string]string{"Rahul Dravid": person},
+        },
+        {
+            name: "organization and place suffixes",
+            body: "Profits rose at Hindustan Aeronautics Ltd this year. Farmers in Himachal Pradesh protested.",
+            want: map[string]string{"Hindustan Aeronautics Ltd": org, "Himachal Pradesh": place},
+        },
+        {
+            name: "organization suffix wins over a title",
+            body: "Workers at General Motors went on strike.",
+            want: map[string]string{"General Motors": org},
+        },
+        {
+            name: "speech verb and surname",
+            body: "Google chief Sundar Pichai said the launch was delayed. Later Pichai said more.",
+            want: map[string]string{"Sundar Pichai": person},
+            not:  []string{"Pichai", "Later Pichai"},
+        },
+        {
+            name: "acronym",
+            body: "The launch was approved by DRDO on Friday.",
+            want: map[string]string{"DRDO": org},
+        },
+        {
+            name: "place preposition",
+            body: "Heavy rain was reported in Kozhikode on Sunday.",
+            want: map[string]string{"Kozhikode": place},
+        },
+        {
+            name: "short aliases need capitals",
+            body: "Who said us? Nobody in the room.",
+            not:  []string{"World Health Organization", "United States"},
+        },
+        {
+            name: "sentence starts are not names",
+            body: "After the vote, Parliament adjourned. Meanwhile nothing changed.",
+            not:  []string{"After", "Meanwhile"},
+        },
+        {
+            name:     "title-cased headline only adds known names",
+            headline: "Pichai Unveils New Phones At Event In California",
+            body:     "Sundar Pichai said the phones ship next month.",
+            want:     map[string]string{"Sundar Pichai": person},
+            not:      []string{"Pichai Unveils New Phones", "New Phones", "California"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := make(map[string]string)
+            for _, e := range ExtractEntities(tt.headline, tt.body) {
+                got[e.Name] = e.Type
+            }
+            for name, typ := range tt.want {
+                if got[name] != typ {
+                    t.Errorf("%q is %q, want %q (got %v)", name, got[name], typ, got)
+                }
+            }
+            for _, name := range tt.not {
+                if _, ok := got[name]; ok {
+                    t.Errorf("%q extracted (got %v)", name, got)
+                }
+            }
+        })
+    }
+}
+
+func TestExtractEntitiesCountsMentions(t *testing.T) {
+    body := "Narendra Modi met Joe Biden. Modi said the talks went well. Biden agreed, and Modi left."
+
+    entities := ExtractEntities("", body)
+    if len(entities) != 2 {
+        t.Fatalf("got %+v, want Modi and Biden", entities)
+    }
+    if e := entities[0]; e.Name != "Narendra Modi" || e.Mentions != 3 || e.NormalizedName != "narendra modi" {
+        t.Errorf("first entity %+v, want Narendra Modi with 3 mentions", e)
+    }
+    if e := entities[1]; e.Name != "Joe Biden" || e.Mentions != 2 {
+        t.Errorf("second entity %+v, want Joe Biden with 2 mentions", e)
+    }
+}
+
+func TestNormalizeEntityName(t *testing.T) {
+    tests := []struct {
+        in   string
+        want string
+    }{
+        {"Sundar Pichai", "sundar pichai"},
+        {"India's", "india"},
+        {"U.S.", "united states"},
+        {"RBI", "reserve bank of india"},
+        {"मोदी", "मोदी"},
+        {"नरेंद्र मोदी", "narendra modi"},
+        {"  ", ""},
+    }
+
+    for _, tt := range tests {
+        if got := NormalizeEntityName(tt.in); got != tt.want {
+            t.Errorf("NormalizeEntityName(%q) = %q, want %q", tt.in, got, tt.want)
+        }
+    }
+}
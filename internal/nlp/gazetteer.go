@@ -0,0 +1,213 @@
+package nlp
+
+import (
+	"strings"
+
+	"news-scraper/internal/models"
+)
+
+type gazetteerEntry struct {
+    typ  string
+    name string
+}
+
+// gazetteer maps entity keys (see entityKey) of well-known names and their
+// aliases to the entity they mean
+var gazetteer = make(map[string]gazetteerEntry)
+
+// gazetteerMaxTokens is the length of the longest gazetteer key, in words
+var gazetteerMaxTokens int
+
+// Each line is a canonical name followed by its aliases, separated by "|"
+var (
+    knownPlaces = `
+India|Bharat|भारत|हिंदुस्तान
+United States|US|U.S.|USA|U.S.A.|America|अमेरिका
+United Kingdom|UK|U.K.|Britain|Great Britain|ब्रिटेन
+China|चीन
+Pakistan|पाकिस्तान
+Bangladesh|बांग्लादेश
+Sri Lanka|श्रीलंका
+Nepal|नेपाल
+Afghanistan|अफगानिस्तान
+Russia|रूस
+Ukraine|यूक्रेन
+Israel|इज़रायल|इजराइल
+Iran|ईरान
+Gaza
+Japan|जापान
+Australia|ऑस्ट्रेलिया
+New Zealand|न्यूजीलैंड
+South Africa|दक्षिण अफ्रीका
+Canada|कनाडा
+France|फ्रांस
+Germany|जर्मनी
+Saudi Arabia|सऊदी अरब
+United Arab Emirates|UAE|U.A.E.
+Europe|यूरोप
+New Delhi|नई दिल्ली
+Delhi|दिल्ली
+Mumbai|Bombay|मुंबई
+Kolkata|Calcutta|कोलकाता
+Chennai|Madras|चेन्नई
+Bengaluru|Bangalore|बेंगलुरु
+Hyderabad|हैदराबाद
+Ahmedabad|अहमदाबाद
+Pune|पुणे
+Lucknow|लखनऊ
+Jaipur|जयपुर
+Uttar Pradesh|UP|उत्तर प्रदेश
+Maharashtra|महाराष्ट्र
+Bihar|बिहार
+West Bengal|पश्चिम बंगाल
+Tamil Nadu|तमिलनाडु
+Karnataka|कर्नाटक
+Kerala|केरल
+Gujarat|गुजरात
+Punjab|पंजाब
+Rajasthan|राजस्थान
+Madhya Pradesh|मध्य प्रदेश
+Jammu and Kashmir|J&K|जम्मू-कश्मीर
+Kashmir|कश्मीर
+Washington
+New York|न्यूयॉर्क
+London|लंदन
+Beijing|बीजिंग
+Moscow|मॉस्को
+Kyiv|Kiev
+Islamabad
+Dhaka
+Tokyo
+Paris
+Dubai|दुबई
+`
+
+    knownOrganizations = `
+United Nations|UN|U.N.|संयुक्त राष्ट्र
+European Union|EU|E.U.
+NATO
+World Health Organization|WHO
+International Monetary Fund|IMF
+World Bank|विश्व बैंक
+Reserve Bank of India|RBI|आरबीआई
+Federal Reserve|Fed
+Supreme Court|सुप्रीम कोर्ट
+Bharatiya Janata Party|BJP|भाजपा
+Indian National Congress|Congress|कांग्रेस
+Aam Aadmi Party|AAP
+Indian Space Research Organisation|ISRO|इसरो
+NASA|नासा
+Board of Control for Cricket in India|BCCI|बीसीसीआई
+International Cricket Council|ICC
+FIFA
+Securities and Exchange Board of India|SEBI|सेबी
+Google|गूगल
+Apple
+Microsoft
+Amazon
+Meta
+OpenAI
+Tesla
+Reliance Industries|Reliance|रिलायंस
+Tata Group|Tata|टाटा
+Infosys
+Adani Group|Adani|अडानी
+`
+
+    knownPeople = `
+Narendra Modi|नरेंद्र मोदी
+Rahul Gandhi|राहुल गांधी
+Amit Shah|अमित शाह
+Droupadi Murmu|द्रौपदी मुर्मू
+Donald Trump|ट्रंप|डोनाल्ड ट्रंप
+Joe Biden|बाइडन
+Vladimir Putin|पुतिन
+Xi Jinping|शी जिनपिंग
+Volodymyr Zelensky|Zelenskyy|जेलेंस्की
+Elon Musk|एलन मस्क
+Virat Kohli|विराट कोहली
+Rohit Sharma|रोहित शर्मा
+`
+)
+
+func init() {
+    for typ, list := range map[string]string{
+        models.EntityPlace:        knownPlaces,
+        models.EntityOrganization: knownOrganizations,
+        models.EntityPerson:       knownPeople,
+    } {
+        for _, line := range strings.Split(strings.TrimSpace(list), "\n") {
+            names := strings.Split(line, "|")
+            for _, alias := range names {
+                key := entityKey(strings.Fields(alias))
+                gazetteer[key] = gazetteerEntry{typ: typ, name: names[0]}
+                gazetteerMaxTokens = max(gazetteerMaxTokens, len(strings.Fields(key)))
+            }
+        }
+    }
+}
+
+// personTitles precede a person's name: "Prime Minister Narendra Modi", "coach Gautam Gambhir"
+var personTitles = toSet(`
+mr mrs ms miss dr prof sir lord lady dame shri smt sri president vice prime minister
+chief deputy secretary senator sen gov governor mayor judge justice pope king queen prince
+princess sheikh emir general gen lt col maj capt captain skipper coach ceo cfo chairman
+chairwoman chairperson founder director spokesperson spokesman spokeswoman actor actress
+singer rapper star striker batter batsman bowler opener rep representative mp mla ambassador
+inspector commissioner officer
+`)
+
+// titleAbbreviations end in a period without ending the sentence
+var titleAbbreviations = toSet(`mr mrs ms dr prof st jr sr gen sen gov rep lt col maj capt sgt`)
+
+// connectors may appear inside a name: "Bank of India", "Ludwig van Beethoven"
+var connectors = toSet(`of the for de da del van von bin al &`)
+
+// organizationSuffixes end organization names: "Tata Motors", "Delhi Police"
+var organizationSuffixes = toSet(`
+inc corp corporation co ltd llc plc group holdings company bank bancorp university college
+institute school hospital party ministry department council commission committee agency
+authority board association federation union league club fc court police army navy
+airlines airways motors industries technologies systems labs foundation trust fund
+times post news journal network studios
+`)
+
+// organizationHeads start organization names: "Ministry of Defence"
+var organizationHeads = toSet(`ministry department university bank institute council board commission`)
+
+// placeSuffixes end place names: "Uttar Pradesh", "Hudson River"
+var placeSuffixes = toSet(`
+city county province district pradesh nagar island islands river valley lake sea
+ocean bay coast mountains hills desert
+`)
+
+// nonNames are capitalized words that aren't names on their own
+var nonNames = toSet(`
+i monday tuesday wednesday thursday friday saturday sunday january february march april
+may june july august september october november december ai gdp covid ipo tv
+`)
+
+// speechVerbs follow a person's name: "Sundar Pichai said"
+var speechVerbs = toSet(`said says told added wrote tweeted posted announced`)
+
+// placePrepositions precede place names: "in Jaipur"
+var placePrepositions = toSet(`in at near across outside around`)
+
+// firstNames are common given names, so "Sunita Williams" is a person
+var firstNames = toSet(`
+james john robert michael william david richard joseph thomas charles christopher daniel
+matthew anthony mark donald steven paul andrew joshua kevin brian george timothy ronald
+jason edward jeffrey ryan jacob gary nicholas eric jonathan stephen larry justin scott
+brandon benjamin samuel frank gregory alexander patrick jack dennis jerry peter kamala
+mary patricia jennifer linda elizabeth barbara susan jessica sarah karen lisa nancy betty
+margaret sandra ashley kimberly emily donna michelle carol amanda melissa deborah
+stephanie rebecca sharon laura cynthia kathleen amy angela anna emma olivia sophia
+emmanuel keir rishi boris olaf giorgia
+rahul amit rajesh suresh ramesh mahesh sunil anil vijay sanjay ajay manoj arvind ashok
+rajiv sachin virat rohit shubman hardik jasprit ravindra mohammed mohammad muhammad abdul
+ali imran salman shahrukh aamir akshay ranveer ranbir deepika priyanka alia katrina
+sonia smriti nirmala yogi mamata nitish nitin uddhav sharad akhilesh mayawati priya
+pooja neha sunita anita kavita sneha divya lakshmi meera shreya ananya aishwarya arjun
+krishna gautam sourav mithali jhulan harmanpreet sundar satya mukesh ratan narayana
+nandan kiran rakesh
+`)
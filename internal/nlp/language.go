@@ -0,0 +1,180 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Language codes are ISO 639-1, as used in <html lang>
+const (
+    English = "en"
+    Hindi   = "hi"
+)
+
+// scripts maps writing systems to the language they almost always mean here
+// Devanagari is also Marathi and Nepali; a declared lang in the same
+// script wins over this guess.
+var scripts = []struct {
+    table    *unicode.RangeTable
+    language string
+}{
+    {unicode.Devanagari, Hindi},
+    {unicode.Bengali, "bn"},
+    {unicode.Gurmukhi, "pa"},
+    {unicode.Gujarati, "gu"},
+    {unicode.Tamil, "ta"},
+    {unicode.Telugu, "te"},
+    {unicode.Kannada, "kn"},
+    {unicode.Malayalam, "ml"},
+    {unicode.Arabic, "ar"},
+    {unicode.Cyrillic, "ru"},
+    {unicode.Hangul, "ko"},
+    {unicode.Hiragana, "ja"},
+    {unicode.Katakana, "ja"},
+    {unicode.Han, "zh"},
+}
+
+// latinProfiles are the most frequent short words of languages written in Latin script
+var latinProfiles = map[string]map[string]bool{
+    English: toSet("the a an and of to in is that for on with was as by it at from are this be has have but not will after over his her its says said into"),
+    "fr":    toSet("le la les et des est une pour dans que qui sur pas du au avec ce il sont"),
+    "es":    toSet("el la los las y de que en es por con para una del se un al como"),
+    "de":    toSet("der die und das ist den von zu mit sich des auf für nicht ein eine im dem"),
+    "it":    toSet("il la di che e per una un del della sono con non le gli dei nel"),
+    "pt":    toSet("o a os as de que e do da em um uma para com não por dos das"),
+    "nl":    toSet("de het een en van is dat op te in met voor zijn niet aan"),
+}
+
+// DetectLanguage guesses the language of text
+// The script decides for non-Latin text. Latin text is matched against
+// common-word profiles, which needs a sentence or so to be reliable; for
+// shorter text the declared language (usually <html lang>) is used, and
+// short plain-ASCII text is taken to be English. It returns "" when
+// nothing gives an answer.
+func DetectLanguage(text, declared string) string {
+    declared = NormalizeLanguage(declared)
+
+    counts := make(map[string]int)
+    latin, letters := 0, 0
+    for _, r := range text {
+        if !unicode.IsLetter(r) {
+            continue
+        }
+        letters++
+        if unicode.Is(unicode.Latin, r) {
+            latin++
+            continue
+        }
+        for _, s := range scripts {
+            if unicode.Is(s.table, r) {
+                counts[s.language]++
+                break
+            }
+        }
+    }
+    if letters == 0 {
+        return declared
+    }
+
+    best, bestCount := "", 0
+    for language, n := range counts {
+        if n > bestCount {
+            best, bestCount = language, n
+        }
+    }
+    if bestCount*2 > letters {
+        if declared != "" && sameScript(declared, best) {
+            return declared
+        }
+        return best
+    }
+    if latin*2 <= letters {
+        return declared
+    }
+
+    scores := make(map[string]int)
+    for _, w := range Words(text) {
+        for language, profile := range latinProfiles {
+            if profile[w] {
+                scores[language]++
+            }
+        }
+    }
+    best, bestScore, second := "", 0, 0
+    for language, n := range scores {
+        switch {
+        case n > bestScore:
+            best, bestScore, second = language, n, bestScore
+        case n > second:
+            second = n
+        }
+    }
+    if bestScore >= 3 && bestScore > second {
+        return best
+    }
+    // A Hindi page may still carry an English headline
+    if declared != "" && !nonLatin(declared) {
+        return declared
+    }
+    if bestScore > second {
+        return best
+    }
+    // A short headline in plain ASCII with nothing else to go on is
+    // most likely English, at least for the sources we scrape
+    if isASCII(text) {
+        return English
+    }
+    return ""
+}
+
+func isASCII(s string) bool {
+    for i := 0; i < len(s); i++ {
+        if s[i] >= utf8.RuneSelf {
+            return false
+        }
+    }
+    return true
+}
+
+// NormalizeLanguage reduces a language tag like "en-IN" or "HI" to its base code
+func NormalizeLanguage(tag string) string {
+    tag = strings.ToLower(strings.TrimSpace(tag))
+    if i := strings.IndexAny(tag, "-_"); i >= 0 {
+        tag = tag[:i]
+    }
+    if len(tag) < 2 || len(tag) > 3 {
+        return ""
+    }
+    for _, r := range tag {
+        if r < 'a' || r > 'z' {
+            return ""
+        }
+    }
+    return tag
+}
+
+// nonLatin reports whether a language is written in a script other than Latin
+func nonLatin(language string) bool {
+    for _, s := range scripts {
+        if sameScript(language, s.language) {
+            return true
+        }
+    }
+    return false
+}
+
+// sameScript reports whether a declared language is written in the detected one's script
+func sameScript(declared, detected string) bool {
+    switch detected {
+    case Hindi:
+        return declared == "mr" || declared == "ne" || declared == "sa" || declared == Hindi
+    case "ar":
+        return declared == "ur" || declared == "fa" || declared == "ar"
+    case "ru":
+        return declared == "uk" || declared == "bg" || declared == "sr" || declared == "ru"
+    case "zh", "ja":
+        return declared == "zh" || declared == "ja"
+    }
+    return declared == detected
+}
@@ -0,0 +1,59 @@
+package nlp
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+    tests := []struct {
+        name     string
+        text     string
+        declared string
+        want     string
+    }{
+        {"english sentence", "The minister said the plan will be ready after the election", "", English},
+        {"french sentence", "Le président est arrivé dans la ville pour une visite avec les ministres", "", "fr"},
+        {"german sentence", "Der Minister ist mit dem Zug in die Stadt gefahren und hat sich nicht geäußert", "", "de"},
+        {"spanish sentence", "El presidente llegó a la ciudad para una visita con los ministros del gobierno", "", "es"},
+        {"hindi script", "प्रधानमंत्री ने नई योजना की घोषणा की", "", Hindi},
+        {"marathi declared in devanagari", "मुख्यमंत्र्यांनी नवीन योजना जाहीर केली", "mr-IN", "mr"},
+        {"english declared on devanagari text", "प्रधानमंत्री ने नई योजना की घोषणा की", "en", Hindi},
+        {"tamil script", "முதலமைச்சர் புதிய திட்டத்தை அறிவித்தார்", "", "ta"},
+        {"cyrillic declared ukrainian", "Президент оголосив новий план", "uk", "uk"},
+        {"english headline on a hindi page", "Sensex rallies 500 points", "hi", English},
+        {"short headline uses declared", "Sensex rallies", "fr-FR", "fr"},
+        {"short ascii headline", "Sensex rallies 500 points", "", English},
+        {"short accented headline", "Économie: hausse", "", ""},
+        {"no letters", "2025 — 10:30", "en-GB", English},
+        {"no letters, nothing declared", "2025", "", ""},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := DetectLanguage(tt.text, tt.declared); got != tt.want {
+                t.Errorf("DetectLanguage(%q, %q) = %q, want %q", tt.text, tt.declared, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+    tests := []struct {
+        in   string
+        want string
+    }{
+        {"en", "en"},
+        {"en-IN", "en"},
+        {" HI ", "hi"},
+        {"pt_BR", "pt"},
+        {"fil", "fil"},
+        {"", ""},
+        {"e", ""},
+        {"english", ""},
+        {"x1", ""},
+    }
+
+    for _, tt := range tests {
+        if got := NormalizeLanguage(tt.in); got != tt.want {
+            t.Errorf("NormalizeLanguage(%q) = %q, want %q", tt.in, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,126 @@
+package nlp
+
+import (
+	"bufio"
+	"embed"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//go:embed lexicon/*.txt
+var lexiconFiles embed.FS
+
+// Scores at or beyond these count as positive or negative, in between is neutral
+const (
+    PositiveThreshold = 0.05
+    NegativeThreshold = -0.05
+)
+
+// sentimentAlpha controls how fast the score approaches ±1 as word scores add up
+// With 15, one strongly negative word ("killed", -3) scores -0.61.
+const sentimentAlpha = 15
+
+// lexicon holds word scores for one language; stems catch inflections
+// the lexicon doesn't list
+type lexicon struct {
+    words map[string]float64
+    stems map[string]float64
+}
+
+// lexicons are loaded from lexicon/sentiment.<language>.txt
+var lexicons = map[string]*lexicon{
+    English: loadLexicon(English),
+    Hindi:   loadLexicon(Hindi),
+}
+
+// negators flip the score of the next few words; "t" is what's left of "n't"
+var negators = toSet(`not no never without nor neither nobody nothing none cannot t नहीं न मत बिना`)
+
+// boosters strengthen the word after them
+var boosters = toSet(`very extremely highly deeply really so too most hugely sharply severely badly greatly strongly बहुत बेहद काफी`)
+
+// Sentiment scores the tone of text from -1 (negative) to 1 (positive)
+// Words are looked up in the language's bundled lexicon (English when
+// there is none), scaled up by a preceding booster ("sharply") and flipped
+// by a negation in the three words before them ("not", "didn't"). The
+// sum is normalized like VADER does, so a headline with one strong word
+// and a long summary of them land on the same scale.
+func Sentiment(text, language string) float64 {
+    lex, ok := lexicons[language]
+    if !ok {
+        lex = lexicons[English]
+    }
+
+    words := Words(text)
+    sum := 0.0
+    for i, w := range words {
+        score, ok := lex.score(w, language)
+        if !ok {
+            continue
+        }
+        if i > 0 && boosters[words[i-1]] {
+            score *= 1.3
+        }
+        for j := max(0, i-3); j < i; j++ {
+            if negators[words[j]] {
+                score *= -0.75
+                break
+            }
+        }
+        sum += score
+    }
+
+    if sum == 0 {
+        return 0
+    }
+    score := sum / math.Sqrt(sum*sum+sentimentAlpha)
+    return math.Round(score*1000) / 1000
+}
+
+func (l *lexicon) score(word, language string) (float64, bool) {
+    if s, ok := l.words[word]; ok {
+        return s, true
+    }
+    s, ok := l.stems[Stem(word, language)]
+    return s, ok
+}
+
+// loadLexicon reads a bundled lexicon: one word and its score per line
+// A stem shared by words of opposite sign is left out of the stem table.
+func loadLexicon(language string) *lexicon {
+    data, err := lexiconFiles.ReadFile("lexicon/sentiment." + language + ".txt")
+    if err != nil {
+        panic(err)
+    }
+
+    l := &lexicon{words: make(map[string]float64), stems: make(map[string]float64)}
+    ambiguous := make(map[string]bool)
+    scanner := bufio.NewScanner(strings.NewReader(string(data)))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        fields := strings.Fields(line)
+        if len(fields) != 2 {
+            panic("bad lexicon line: " + line)
+        }
+        score, err := strconv.ParseFloat(fields[1], 64)
+        if err != nil {
+            panic("bad lexicon line: " + line)
+        }
+
+        word := fields[0]
+        l.words[word] = score
+        stem := Stem(word, language)
+        if prev, ok := l.stems[stem]; ok && prev*score < 0 {
+            ambiguous[stem] = true
+        }
+        l.stems[stem] = score
+    }
+    for stem := range ambiguous {
+        delete(l.stems, stem)
+    }
+    return l
+}
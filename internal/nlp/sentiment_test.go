@@ -0,0 +1,47 @@
+package nlp
+
+import "testing"
+
+func TestSentiment(t *testing.T) {
+    tests := []struct {
+        name     string
+        text     string
+        language string
+        want     float64
+    }{
+        // sum / sqrt(sum² + 15), rounded to three places
+        {"no lexicon words", "The committee met on Tuesday", English, 0},
+        {"empty", "", English, 0},
+        {"positive word", "A good day", English, 0.25},
+        {"negative word", "Three killed", English, -0.612},
+        {"scores add up", "Good news but a bad day", English, -0.25},
+        {"booster", "A very good day", English, 0.318},
+        {"negation", "Not good at all", English, -0.19},
+        {"contraction negates", "It didn't crash", English, 0.361},
+        {"negation only reaches three words back", "Not in any way good", English, 0.25},
+        {"stem of a listed word", "Markets crashes", English, -0.459},
+        {"hindi", "भारत की जीत", Hindi, 0.459},
+        {"unknown language uses english", "A good day", "fr", 0.25},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Sentiment(tt.text, tt.language); got != tt.want {
+                t.Errorf("Sentiment(%q, %q) = %v, want %v", tt.text, tt.language, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestSentimentRange(t *testing.T) {
+    tests := []string{
+        "killed killed killed killed killed killed killed killed",
+        "great win happy strong good great win happy strong good",
+    }
+
+    for _, text := range tests {
+        if got := Sentiment(text, English); got <= -1 || got >= 1 {
+            t.Errorf("Sentiment(%q) = %v, want within (-1, 1)", text, got)
+        }
+    }
+}
@@ -0,0 +1,85 @@
+package nlp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Stem reduces a lowercase word to its stem in the given language
+// English uses the plural and -ed/-ing steps of the Porter stemmer and
+// drops a final e, so "vote", "votes" and "voting" all become "vot".
+// Hindi strips the longest inflectional suffix (Ramanathan and Rao's light
+// stemmer), so "खिलाड़ियों" becomes "खिलाड़". Other languages are returned as is.
+func Stem(word, language string) string {
+    switch language {
+    case English:
+        return stemEnglish(word)
+    case Hindi:
+        return stemHindi(word)
+    default:
+        return word
+    }
+}
+
+func stemEnglish(w string) string {
+    if len(w) <= 3 {
+        return w
+    }
+
+    // Plurals
+    switch {
+    case strings.HasSuffix(w, "sses"):
+        w = w[:len(w)-2]
+    case strings.HasSuffix(w, "ies"):
+        w = w[:len(w)-2]
+    case strings.HasSuffix(w, "ss"), strings.HasSuffix(w, "us"), strings.HasSuffix(w, "is"):
+    case strings.HasSuffix(w, "s"):
+        w = w[:len(w)-1]
+    }
+
+    // -ed and -ing, when what's left still has a vowel
+    for _, suffix := range []string{"ing", "ed"} {
+        if stem := strings.TrimSuffix(w, suffix); stem != w && len(stem) >= 3 && hasVowel(stem) {
+            w = stem
+            // running -> run, but not falling -> fal
+            if n := len(w); w[n-1] == w[n-2] && !strings.ContainsRune("aeioulsz", rune(w[n-1])) {
+                w = w[:n-1]
+            }
+            break
+        }
+    }
+
+    if n := len(w); n > 3 && w[n-1] == 'y' && hasVowel(w[:n-1]) {
+        w = w[:n-1] + "i"
+    }
+    if n := len(w); n > 3 && w[n-1] == 'e' {
+        w = w[:n-1]
+    }
+    return w
+}
+
+func hasVowel(s string) bool {
+    return strings.ContainsAny(s, "aeiouy")
+}
+
+// hindiSuffixes are tried longest first
+var hindiSuffixes = [][]string{
+    {"ाएंगी", "ाएंगे", "ाऊंगी", "ाऊंगा", "ाइयाँ", "ाइयों", "ाइयां"},
+    {"ाएगी", "ाएगा", "ाओगी", "ाओगे", "एंगी", "ेंगी", "एंगे", "ेंगे", "ूंगी", "ूंगा", "ातीं", "नाओं", "नाएं", "ताओं", "ताएं", "ियाँ", "ियों", "ियां"},
+    {"ाकर", "ाइए", "ाईं", "ाया", "ेगी", "ेगा", "ोगी", "ोगे", "ाने", "ाना", "ाते", "ाती", "ाता", "तीं", "ाओं", "ाएं", "ुओं", "ुएं", "ुआं"},
+    {"कर", "ाओ", "िए", "ाई", "ाए", "ने", "नी", "ना", "ते", "ीं", "ती", "ता", "ाँ", "ां", "ों", "ें"},
+    {"ो", "े", "ू", "ु", "ी", "ि", "ा"},
+}
+
+func stemHindi(w string) string {
+    n := utf8.RuneCountInString(w)
+    for _, group := range hindiSuffixes {
+        for _, suffix := range group {
+            // Keep at least two characters of stem
+            if strings.HasSuffix(w, suffix) && n-utf8.RuneCountInString(suffix) >= 2 {
+                return strings.TrimSuffix(w, suffix)
+            }
+        }
+    }
+    return w
+}
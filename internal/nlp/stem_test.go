@@ -0,0 +1,40 @@
+package nlp
+
+import "testing"
+
+func TestStem(t *testing.T) {
+    tests := []struct {
+        word     string
+        language string
+        want     string
+    }{
+        {"vote", English, "vot"},
+        {"votes", English, "vot"},
+        {"voting", English, "vot"},
+        {"voted", English, "vot"},
+        {"running", English, "run"},
+        {"falling", English, "fall"},
+        {"classes", English, "class"},
+        {"parties", English, "parti"},
+        {"party", English, "parti"},
+        {"bus", English, "bus"},
+        {"crisis", English, "crisis"},
+        {"ring", English, "ring"},
+        {"red", English, "red"},
+        {"war", English, "war"},
+        {"खिलाड़ियों", Hindi, "खिलाड़"},
+        {"खिलाड़ी", Hindi, "खिलाड़"},
+        {"लड़कों", Hindi, "लड़क"},
+        {"बनाएगा", Hindi, "बन"},
+        {"जाएगा", Hindi, "जाएग"}, // keeps two characters of stem
+        {"में", Hindi, "में"},
+        {"running", "fr", "running"},
+        {"running", "", "running"},
+    }
+
+    for _, tt := range tests {
+        if got := Stem(tt.word, tt.language); got != tt.want {
+            t.Errorf("Stem(%q, %q) = %q, want %q", tt.word, tt.language, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,65 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Words splits text into lowercase words of letters and digits
+// Combining marks stay in the word: Devanagari vowel signs are marks.
+func Words(text string) []string {
+    return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+    })
+}
+
+// Keywords returns the words of text that can carry meaning on their own:
+// no stopwords, nothing shorter than three characters, no bare numbers
+func Keywords(text string) []string {
+    words := Words(text)
+    keywords := words[:0]
+    for _, w := range words {
+        if IsKeyword(w) {
+            keywords = append(keywords, w)
+        }
+    }
+    return keywords
+}
+
+// IsKeyword reports whether a lowercase word is worth indexing
+func IsKeyword(w string) bool {
+    if utf8.RuneCountInString(w) < 3 || stopwords[w] {
+        return false
+    }
+    for _, r := range w {
+        if !unicode.IsDigit(r) {
+            return true
+        }
+    }
+    return false
+}
+
+// stopwords are common English and Hindi words plus headline filler
+var stopwords = toSet(`
+a about above after again against all also am an and any are as at be because been before
+being below between both but by can could did do does doing down during each few for from
+further had has have having he her here hers herself him himself his how i if in into is it
+its itself just may me might more most must my myself no nor not now of off on once only or
+other our ours ourselves out over own same she should so some such than that the their theirs
+them themselves then there these they this those through to too under until up very was we
+were what when where which while who whom why will with would you your yours yourself
+yourselves new says said say get gets got make makes made one two three first last year years
+day days week weeks amid like back still many much today yesterday tomorrow
+news live latest update updates report reports watch video photos via
+का की के को में से पर ने है हैं था थी थे हो और या भी तो ही यह वह ये वे इस उस इन उन एक
+लिए साथ बाद कि जो तक कर किया गया गई गए रहा रही रहे कहा अपने अपनी नहीं अब जब बहुत
+`)
+
+func toSet(list string) map[string]bool {
+    set := make(map[string]bool)
+    for _, w := range strings.Fields(list) {
+        set[w] = true
+    }
+    return set
+}
@@ -0,0 +1,321 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Selection strategies of a pool
+const (
+    RoundRobin = "round_robin" // every request takes the next proxy
+    Sticky     = "sticky"      // each domain keeps its proxy while it stays healthy
+)
+
+// ErrNoHealthyProxy is returned when every proxy of a pool is ejected
+var ErrNoHealthyProxy = errors.New("no healthy proxy")
+
+// PoolConfig lists the proxies of one pool
+// URLs are http://, https:// or socks5:// with optional user:password.
+type PoolConfig struct {
+    Strategy string   `yaml:"strategy"`
+    URLs     []string `yaml:"urls"`
+}
+
+// Config holds proxy pool settings
+type Config struct {
+    Pools         map[string]PoolConfig
+    MaxFailures   int           // Consecutive failures that eject a proxy (default 3)
+    CheckURL      string        // Fetched through each proxy by the health check; empty = no active checks
+    CheckInterval time.Duration // How often proxies are checked, or ejected ones let back without CheckURL (default 5m)
+}
+
+// Manager holds the proxy pools sources can be assigned to
+// Proxies that fail MaxFailures times in a row, in scrapes or health
+// checks, are ejected from their pool until a health check passes.
+type Manager struct {
+    cfg   Config
+    pools map[string]*Pool
+
+    cancel context.CancelFunc
+    wg     sync.WaitGroup
+}
+
+// Pool is a named set of proxies with a selection strategy
+type Pool struct {
+    name        string
+    strategy    string
+    maxFailures int
+
+    mu      sync.Mutex
+    proxies []*member
+    next    int
+    sticky  map[string]*member // by host
+}
+
+type member struct {
+    url       *url.URL
+    failures  int
+    ejected   bool
+    ejectedAt time.Time
+    requests  int64
+    lastError string
+}
+
+// ProxyStats is the health of one proxy as shown by the API
+// URL has its password redacted.
+type ProxyStats struct {
+    Pool      string `json:"pool"`
+    URL       string `json:"url"`
+    Healthy   bool   `json:"healthy"`
+    Failures  int    `json:"failures"`
+    Requests  int64  `json:"requests"`
+    LastError string `json:"last_error,omitempty"`
+}
+
+// New parses the configured pools
+func New(cfg Config) (*Manager, error) {
+    if cfg.MaxFailures <= 0 {
+        cfg.MaxFailures = 3
+    }
+    if cfg.CheckInterval <= 0 {
+        cfg.CheckInterval = 5 * time.Minute
+    }
+
+    m := &Manager{cfg: cfg, pools: make(map[string]*Pool)}
+    for name, pc := range cfg.Pools {
+        strategy := pc.Strategy
+        if strategy == "" {
+            strategy = RoundRobin
+        }
+        if strategy != RoundRobin && strategy != Sticky {
+            return nil, fmt.Errorf("proxy pool %s: unknown strategy %q", name, strategy)
+        }
+        if len(pc.URLs) == 0 {
+            return nil, fmt.Errorf("proxy pool %s: no proxies", name)
+        }
+
+        pool := &Pool{name: name, strategy: strategy, maxFailures: cfg.MaxFailures, sticky: make(map[string]*member)}
+        for _, raw := range pc.URLs {
+            u, err := url.Parse(raw)
+            if err != nil {
+                return nil, fmt.Errorf("proxy pool %s: %w", name, err)
+            }
+            switch u.Scheme {
+            case "http", "https", "socks5":
+            default:
+                return nil, fmt.Errorf("proxy pool %s: unsupported proxy scheme %q", name, u.Scheme)
+            }
+            pool.proxies = append(pool.proxies, &member{url: u})
+        }
+        m.pools[name] = pool
+    }
+    return m, nil
+}
+
+// Pool returns the named pool
+func (m *Manager) Pool(name string) (*Pool, error) {
+    if m == nil {
+        return nil, fmt.Errorf("proxy pool %q: no proxy pools configured", name)
+    }
+    pool, ok := m.pools[name]
+    if !ok {
+        return nil, fmt.Errorf("unknown proxy pool %q", name)
+    }
+    return pool, nil
+}
+
+// Stats returns the health of every proxy, by pool
+func (m *Manager) Stats() []ProxyStats {
+    stats := []ProxyStats{}
+    if m == nil {
+        return stats
+    }
+
+    names := make([]string, 0, len(m.pools))
+    for name := range m.pools {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+
+    for _, name := range names {
+        pool := m.pools[name]
+        pool.mu.Lock()
+        for _, p := range pool.proxies {
+            stats = append(stats, ProxyStats{
+                Pool:      name,
+                URL:       p.url.Redacted(),
+                Healthy:   !p.ejected,
+                Failures:  p.failures,
+                Requests:  p.requests,
+                LastError: p.lastError,
+            })
+        }
+        pool.mu.Unlock()
+    }
+    return stats
+}
+
+// Start runs the health check every CheckInterval until Stop is called or ctx is cancelled
+func (m *Manager) Start(ctx context.Context) {
+    if m == nil || len(m.pools) == 0 {
+        return
+    }
+    ctx, m.cancel = context.WithCancel(ctx)
+
+    m.wg.Add(1)
+    go func() {
+        defer m.wg.Done()
+
+        ticker := time.NewTicker(m.cfg.CheckInterval)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-ticker.C:
+                m.check(ctx)
+            }
+        }
+    }()
+    log.Printf("Proxy health check started for %d pools", len(m.pools))
+}
+
+// Stop ends the health check
+func (m *Manager) Stop() {
+    if m != nil && m.cancel != nil {
+        m.cancel()
+        m.wg.Wait()
+    }
+}
+
+// check fetches CheckURL through every proxy, or without one lets
+// ejected proxies back in to be tried again
+func (m *Manager) check(ctx context.Context) {
+    for _, pool := range m.pools {
+        pool.mu.Lock()
+        proxies := make([]*member, len(pool.proxies))
+        copy(proxies, pool.proxies)
+        pool.mu.Unlock()
+
+        for _, p := range proxies {
+            if m.cfg.CheckURL == "" {
+                pool.mu.Lock()
+                if p.ejected && time.Since(p.ejectedAt) >= m.cfg.CheckInterval {
+                    p.ejected, p.failures = false, 0
+                    log.Printf("Proxy %s back in pool %s", p.url.Redacted(), pool.name)
+                }
+                pool.mu.Unlock()
+                continue
+            }
+
+            if err := probe(ctx, p.url, m.cfg.CheckURL); err != nil {
+                if ctx.Err() != nil {
+                    return
+                }
+                pool.Failure(p.url.String(), err)
+            } else {
+                pool.Success(p.url.String())
+            }
+        }
+    }
+}
+
+// probe fetches checkURL through the proxy
+func probe(ctx context.Context, proxyURL *url.URL, checkURL string) error {
+    transport := http.DefaultTransport.(*http.Transport).Clone()
+    transport.Proxy = http.ProxyURL(proxyURL)
+    transport.DisableKeepAlives = true
+    client := &http.Client{Transport: transport, Timeout: 15 * time.Second}
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+    if err != nil {
+        return err
+    }
+    resp, err := client.Do(req)
+    if err != nil {
+        return err
+    }
+    resp.Body.Close()
+    if resp.StatusCode >= 400 {
+        return fmt.Errorf("health check: HTTP %d", resp.StatusCode)
+    }
+    return nil
+}
+
+// Pick returns the proxy for a request to host
+func (p *Pool) Pick(host string) (*url.URL, error) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    host = strings.ToLower(host)
+    if p.strategy == Sticky {
+        if m, ok := p.sticky[host]; ok && !m.ejected {
+            m.requests++
+            return m.url, nil
+        }
+    }
+
+    for range p.proxies {
+        m := p.proxies[p.next%len(p.proxies)]
+        p.next++
+        if m.ejected {
+            continue
+        }
+        if p.strategy == Sticky {
+            p.sticky[host] = m
+        }
+        m.requests++
+        return m.url, nil
+    }
+    return nil, fmt.Errorf("proxy pool %s: %w", p.name, ErrNoHealthyProxy)
+}
+
+// Success resets the failure count of a proxy and lets it back into the pool
+func (p *Pool) Success(proxyURL string) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    if m := p.find(proxyURL); m != nil {
+        if m.ejected {
+            log.Printf("Proxy %s back in pool %s", m.url.Redacted(), p.name)
+        }
+        m.failures, m.ejected = 0, false
+    }
+}
+
+// Failure counts a failed request through a proxy and ejects it at MaxFailures
+func (p *Pool) Failure(proxyURL string, err error) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    m := p.find(proxyURL)
+    if m == nil {
+        return
+    }
+    m.failures++
+    if err != nil {
+        m.lastError = err.Error()
+    }
+    if !m.ejected && m.failures >= p.maxFailures {
+        m.ejected = true
+        m.ejectedAt = time.Now()
+        log.Printf("Proxy %s ejected from pool %s after %d failures: %v", m.url.Redacted(), p.name, m.failures, err)
+    }
+}
+
+func (p *Pool) find(proxyURL string) *member {
+    for _, m := range p.proxies {
+        if m.url.String() == proxyURL {
+            return m
+        }
+    }
+    return nil
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rule is the request budget of a host
+// Rate is in requests per second (0 = unlimited); Burst is how many
+// requests may go out back to back after an idle period (default 1).
+type Rule struct {
+    Rate  float64 `yaml:"rate" json:"rate"`
+    Burst int     `yaml:"burst" json:"burst"`
+}
+
+// Limiter is a process-wide token bucket per host
+// Every fetch waits on it, whichever source, page or article worker makes
+// it, so two sources on one site share that site's budget. Tokens refill
+// continuously at the rule's rate up to its burst; a request that finds
+// the bucket empty reserves the next token and sleeps until it is due.
+type Limiter struct {
+    def     Rule
+    domains map[string]Rule
+
+    mu    sync.Mutex
+    hosts map[string]*bucket
+}
+
+type bucket struct {
+    tokens float64
+    last   time.Time
+
+    requests  int64
+    delayed   int64
+    totalWait time.Duration
+    maxWait   time.Duration
+    rule      Rule // effective rule of the last request
+}
+
+// HostStats is how much a host's requests have waited on the limiter
+type HostStats struct {
+    Host           string  `json:"host"`
+    Rate           float64 `json:"rate"`
+    Burst          int     `json:"burst"`
+    Requests       int64   `json:"requests"`
+    Delayed        int64   `json:"delayed"` // requests that had to wait
+    WaitSeconds    float64 `json:"wait_seconds"`
+    AvgWaitSeconds float64 `json:"avg_wait_seconds"`
+    MaxWaitSeconds float64 `json:"max_wait_seconds"`
+}
+
+// New creates a limiter applying def to every host, except hosts under a
+// domain listed in domains ("bbc.co.uk" also covers "www.bbc.co.uk")
+func New(def Rule, domains map[string]Rule) *Limiter {
+    l := &Limiter{
+        def:     withDefaults(def),
+        domains: make(map[string]Rule, len(domains)),
+        hosts:   make(map[string]*bucket),
+    }
+    for domain, rule := range domains {
+        l.domains[strings.ToLower(strings.TrimPrefix(domain, "www."))] = withDefaults(rule)
+    }
+    return l
+}
+
+// Rule returns the configured rule of a host: its longest matching domain, else the default
+func (l *Limiter) Rule(host string) Rule {
+    host = strings.ToLower(host)
+    for {
+        if rule, ok := l.domains[host]; ok {
+            return rule
+        }
+        i := strings.IndexByte(host, '.')
+        if i < 0 {
+            return l.def
+        }
+        host = host[i+1:]
+    }
+}
+
+// Wait blocks until a request to host may go out
+// minInterval, when set, slows the host down further (robots.txt
+// Crawl-delay) and disables bursts. If ctx is done first the reserved
+// token is given back and the context's error returned.
+func (l *Limiter) Wait(ctx context.Context, host string, minInterval time.Duration) error {
+    host = strings.ToLower(host)
+    rule := l.Rule(host)
+    if minInterval > 0 {
+        rule.Burst = 1
+        if rule.Rate <= 0 || rule.Rate > 1/minInterval.Seconds() {
+            rule.Rate = 1 / minInterval.Seconds()
+        }
+    }
+
+    l.mu.Lock()
+    b, ok := l.hosts[host]
+    now := time.Now()
+    if !ok {
+        b = &bucket{tokens: float64(rule.Burst), last: now}
+        l.hosts[host] = b
+    }
+    b.rule = rule
+
+    var wait time.Duration
+    if rule.Rate > 0 {
+        b.tokens = math.Min(float64(rule.Burst), b.tokens+now.Sub(b.last).Seconds()*rule.Rate)
+        b.last = now
+        b.tokens--
+        if b.tokens < 0 {
+            wait = time.Duration(-b.tokens / rule.Rate * float64(time.Second))
+        }
+    }
+    b.requests++
+    if wait > 0 {
+        b.delayed++
+    }
+    l.mu.Unlock()
+
+    if wait <= 0 {
+        return nil
+    }
+    timer := time.NewTimer(wait)
+    defer timer.Stop()
+    var err error
+    select {
+    case <-timer.C:
+    case <-ctx.Done():
+        err = ctx.Err()
+    }
+
+    waited := time.Since(now)
+    l.mu.Lock()
+    if err != nil {
+        b.tokens++
+    }
+    b.totalWait += waited
+    b.maxWait = max(b.maxWait, waited)
+    l.mu.Unlock()
+    return err
+}
+
+// Stats returns the wait metrics of every host seen so far, most waited first
+func (l *Limiter) Stats() []HostStats {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    stats := make([]HostStats, 0, len(l.hosts))
+    for host, b := range l.hosts {
+        s := HostStats{
+            Host:           host,
+            Rate:           b.rule.Rate,
+            Burst:          b.rule.Burst,
+            Requests:       b.requests,
+            Delayed:        b.delayed,
+            WaitSeconds:    round(b.totalWait.Seconds()),
+            MaxWaitSeconds: round(b.maxWait.Seconds()),
+        }
+        if b.requests > 0 {
+            s.AvgWaitSeconds = round(b.totalWait.Seconds() / float64(b.requests))
+        }
+        stats = append(stats, s)
+    }
+    sort.Slice(stats, func(i, j int) bool {
+        if stats[i].WaitSeconds == stats[j].WaitSeconds {
+            return stats[i].Host < stats[j].Host
+        }
+        return stats[i].WaitSeconds > stats[j].WaitSeconds
+    })
+    return stats
+}
+
+func withDefaults(rule Rule) Rule {
+    if rule.Burst <= 0 {
+        rule.Burst = 1
+    }
+    return rule
+}
+
+func round(f float64) float64 {
+    return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRule(t *testing.T) {
+    l := New(Rule{Rate: 5}, map[string]Rule{
+        "bbc.co.uk":        {Rate: 1, Burst: 3},
+        "www.example.com":  {Rate: 2},
+        "news.example.com": {Rate: 4},
+    })
+
+    tests := []struct {
+        host string
+        want Rule
+    }{
+        {"bbc.co.uk", Rule{Rate: 1, Burst: 3}},
+        {"www.bbc.co.uk", Rule{Rate: 1, Burst: 3}},
+        {"Feeds.BBC.co.uk", Rule{Rate: 1, Burst: 3}},
+        {"notbbc.co.uk", Rule{Rate: 5, Burst: 1}},
+        {"example.com", Rule{Rate: 2, Burst: 1}}, // www. is dropped from domains
+        {"cdn.example.com", Rule{Rate: 2, Burst: 1}},
+        {"live.news.example.com", Rule{Rate: 4, Burst: 1}}, // longest domain wins
+        {"other.org", Rule{Rate: 5, Burst: 1}},
+    }
+
+    for _, tt := range tests {
+        if got := l.Rule(tt.host); got != tt.want {
+            t.Errorf("Rule(%q) = %+v, want %+v", tt.host, got, tt.want)
+        }
+    }
+}
+
+func TestWait(t *testing.T) {
+    tests := []struct {
+        name        string
+        rule        Rule
+        minInterval time.Duration // robots.txt Crawl-delay
+        requests    int
+        wantDelayed int64
+        wantRule    Rule // effective rule reported in Stats
+    }{
+        {"unlimited", Rule{}, 0, 5, 0, Rule{Burst: 1}},
+        {"one token per interval", Rule{Rate: 20}, 0, 3, 2, Rule{Rate: 20, Burst: 1}},
+        {"burst goes out back to back", Rule{Rate: 20, Burst: 3}, 0, 4, 1, Rule{Rate: 20, Burst: 3}},
+        {"crawl-delay limits an unlimited host", Rule{}, 50 * time.Millisecond, 3, 2, Rule{Rate: 20, Burst: 1}},
+        {"crawl-delay slows a faster rule and drops its burst", Rule{Rate: 100, Burst: 5}, 50 * time.Millisecond, 2, 1, Rule{Rate: 20, Burst: 1}},
+        {"slower rule wins over crawl-delay", Rule{Rate: 20}, 10 * time.Millisecond, 2, 1, Rule{Rate: 20, Burst: 1}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            l := New(Rule{}, map[string]Rule{"example.com": tt.rule})
+
+            start := time.Now()
+            for range tt.requests {
+                if err := l.Wait(context.Background(), "Example.com", tt.minInterval); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            elapsed := time.Since(start)
+
+            stats := l.Stats()
+            if len(stats) != 1 || stats[0].Host != "example.com" {
+                t.Fatalf("stats %+v, want one entry for example.com", stats)
+            }
+            s := stats[0]
+            if s.Requests != int64(tt.requests) || s.Delayed != tt.wantDelayed {
+                t.Errorf("%d requests, %d delayed, want %d and %d", s.Requests, s.Delayed, tt.requests, tt.wantDelayed)
+            }
+            if s.Rate != tt.wantRule.Rate || s.Burst != tt.wantRule.Burst {
+                t.Errorf("rule %v/%d, want %+v", s.Rate, s.Burst, tt.wantRule)
+            }
+            if tt.wantDelayed > 0 {
+                // Each delayed request waits for one more token at the effective rate
+                least := time.Duration(float64(tt.wantDelayed) / tt.wantRule.Rate * 0.9 * float64(time.Second))
+                if elapsed < least || s.MaxWaitSeconds <= 0 {
+                    t.Errorf("took %v (max wait %vs), want at least %v", elapsed, s.MaxWaitSeconds, least)
+                }
+            }
+        })
+    }
+}
+
+func TestWaitSharedPerHost(t *testing.T) {
+    l := New(Rule{Rate: 20}, nil)
+    ctx := context.Background()
+
+    for _, host := range []string{"a.example.com", "b.example.com", "a.example.com"} {
+        if err := l.Wait(ctx, host, 0); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    stats := l.Stats()
+    if len(stats) != 2 || stats[0].Host != "a.example.com" || stats[0].Delayed != 1 || stats[1].Delayed != 0 {
+        t.Errorf("stats %+v, want only the second request to a.example.com delayed, listed first", stats)
+    }
+}
+
+func TestWaitCancelled(t *testing.T) {
+    l := New(Rule{Rate: 1}, nil)
+
+    if err := l.Wait(context.Background(), "example.com", 0); err != nil {
+        t.Fatal(err)
+    }
+    ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+    defer cancel()
+    start := time.Now()
+    if err := l.Wait(ctx, "example.com", 0); !errors.Is(err, context.DeadlineExceeded) {
+        t.Fatalf("Wait() = %v, want deadline exceeded", err)
+    }
+    if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+        t.Errorf("cancelled Wait took %v", elapsed)
+    }
+
+    // The cancelled request gave its token back, so the next one waits
+    // for one token rather than two
+    l.mu.Lock()
+    tokens := l.hosts["example.com"].tokens
+    l.mu.Unlock()
+    if tokens < -0.1 {
+        t.Errorf("bucket holds %v tokens after a cancelled wait, want about 0", tokens)
+    }
+}
@@ -0,0 +1,258 @@
+package retention
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+)
+
+// Archive modes
+const (
+    ArchiveNone   = ""       // expired articles are deleted outright
+    ArchiveTable  = "table"  // moved into the articles_archive table
+    ArchiveNDJSON = "ndjson" // written to a gzip-compressed NDJSON file, then deleted
+)
+
+// Rule describes how long articles are kept
+// An article is kept if any of the set conditions keeps it, so
+// {KeepDays: 7, KeepLast: 100} keeps a week of articles and never fewer
+// than the latest 100. A zero Rule keeps everything, except as the
+// policy default, where DefaultRule takes its place.
+type Rule struct {
+    KeepDays    int  `yaml:"keep_days" json:"keep_days,omitempty"`
+    KeepLast    int  `yaml:"keep_last" json:"keep_last,omitempty"`
+    KeepForever bool `yaml:"keep_forever" json:"keep_forever,omitempty"`
+}
+
+// DefaultRule applies to articles no rule matches when the policy sets no
+// default. It covers the 7-day trends baseline and the 30-day sentiment
+// window; set keep_forever to keep everything instead.
+var DefaultRule = Rule{KeepDays: 30}
+
+// keepsAll reports whether the rule can never expire anything
+func (r Rule) keepsAll() bool {
+    return r.KeepForever || (r.KeepDays <= 0 && r.KeepLast <= 0)
+}
+
+// Policy maps articles to rules
+// A rule for the article's source (by name) wins over a rule for its
+// category, which wins over the default.
+type Policy struct {
+    Default    Rule            `yaml:"default"`
+    Sources    map[string]Rule `yaml:"sources"`
+    Categories map[string]Rule `yaml:"categories"`
+}
+
+// ArchiveConfig controls what happens to expired articles before deletion
+type ArchiveConfig struct {
+    Mode string `yaml:"mode"` // "", "table" or "ndjson"
+    Dir  string `yaml:"dir"`  // Output directory for ndjson archives
+}
+
+// GroupReport summarizes one retention scope
+// KeepLast is counted within the scope: per source for source and
+// default rules, per category for category rules.
+type GroupReport struct {
+    Scope   string `json:"scope"`
+    Rule    Rule   `json:"rule"`
+    Matched int    `json:"matched"`
+    Expired int    `json:"expired"`
+}
+
+// Report is the outcome of a retention run
+// In a dry run, Archived and Deleted stay zero and Expired is what
+// would have been removed.
+type Report struct {
+    DryRun      bool          `json:"dry_run"`
+    RanAt       time.Time     `json:"ran_at"`
+    ArchiveMode string        `json:"archive_mode"`
+    ArchiveFile string        `json:"archive_file,omitempty"`
+    Scanned     int           `json:"scanned"`
+    Expired     int           `json:"expired"`
+    Archived    int64         `json:"archived"`
+    Deleted     int64         `json:"deleted"`
+    Groups      []GroupReport `json:"groups"`
+}
+
+// Service applies a retention policy to the articles table
+type Service struct {
+    repo    database.Repository
+    policy  Policy
+    archive ArchiveConfig
+}
+
+// NewService creates a retention service
+func NewService(repo database.Repository, policy Policy, archive ArchiveConfig) (*Service, error) {
+    switch archive.Mode {
+    case ArchiveNone, ArchiveTable:
+    case ArchiveNDJSON:
+        if archive.Dir == "" {
+            archive.Dir = "archive"
+        }
+    default:
+        return nil, fmt.Errorf("unknown archive mode %q", archive.Mode)
+    }
+    if policy.Default == (Rule{}) {
+        policy.Default = DefaultRule
+    }
+
+    return &Service{repo: repo, policy: policy, archive: archive}, nil
+}
+
+// Run evaluates the policy and, unless dryRun is set, archives and deletes expired articles
+func (s *Service) Run(ctx context.Context, dryRun bool) (*Report, error) {
+    now := time.Now()
+    report := &Report{
+        DryRun:      dryRun,
+        RanAt:       now,
+        ArchiveMode: s.archive.Mode,
+    }
+
+    candidates, err := s.repo.GetRetentionCandidates(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load articles: %w", err)
+    }
+
+    expired, groups := s.policy.plan(candidates, now)
+    report.Scanned = len(candidates)
+    report.Expired = len(expired)
+    report.Groups = groups
+
+    if dryRun || len(expired) == 0 {
+        return report, nil
+    }
+
+    switch s.archive.Mode {
+    case ArchiveTable:
+        report.Archived, err = s.repo.ArchiveArticles(ctx, expired)
+        report.Deleted = report.Archived
+        if err != nil {
+            return report, fmt.Errorf("failed to archive articles: %w", err)
+        }
+    case ArchiveNDJSON:
+        report.ArchiveFile, report.Archived, err = s.writeNDJSON(ctx, expired, now)
+        if err != nil {
+            return report, fmt.Errorf("failed to archive articles: %w", err)
+        }
+        fallthrough
+    default:
+        report.Deleted, err = s.repo.DeleteArticles(ctx, expired)
+        if err != nil {
+            return report, fmt.Errorf("failed to delete articles: %w", err)
+        }
+    }
+
+    log.Printf("Retention: scanned %d, expired %d, archived %d, deleted %d",
+        report.Scanned, report.Expired, report.Archived, report.Deleted)
+    return report, nil
+}
+
+// plan returns the IDs of expired articles and a per-scope summary
+// candidates must be ordered newest first.
+func (p Policy) plan(candidates []models.Article, now time.Time) ([]int, []GroupReport) {
+    groups := make(map[string]*GroupReport)
+    var expired []int
+
+    for _, a := range candidates {
+        scope, rule := p.ruleFor(a)
+
+        g, ok := groups[scope]
+        if !ok {
+            g = &GroupReport{Scope: scope, Rule: rule}
+            groups[scope] = g
+        }
+        g.Matched++
+
+        if rule.keepsAll() {
+            continue
+        }
+        if rule.KeepLast > 0 && g.Matched <= rule.KeepLast {
+            continue
+        }
+        if rule.KeepDays > 0 && now.Sub(a.ScrapedAt) < time.Duration(rule.KeepDays)*24*time.Hour {
+            continue
+        }
+
+        g.Expired++
+        expired = append(expired, a.ID)
+    }
+
+    report := make([]GroupReport, 0, len(groups))
+    for _, g := range groups {
+        report = append(report, *g)
+    }
+    sort.Slice(report, func(i, j int) bool { return report[i].Scope < report[j].Scope })
+
+    return expired, report
+}
+
+// ruleFor picks the rule that applies to an article and the scope it is counted in
+func (p Policy) ruleFor(a models.Article) (string, Rule) {
+    if rule, ok := p.Sources[a.SourceName]; ok {
+        return "source:" + a.SourceName, rule
+    }
+    if rule, ok := p.Categories[a.Category]; ok {
+        return "category:" + a.Category, rule
+    }
+    return "default:" + a.SourceName, p.Default
+}
+
+// archivedArticle is one line of an NDJSON archive
+// It carries the canonical URL the API leaves out, so restored rows keep their dedupe key.
+type archivedArticle struct {
+    *models.Article
+    CanonicalURL string `json:"canonical_url"`
+}
+
+// writeNDJSON writes the expired articles to a new gzip-compressed NDJSON file
+func (s *Service) writeNDJSON(ctx context.Context, ids []int, now time.Time) (string, int64, error) {
+    articles, err := s.repo.GetArticlesByIDs(ctx, ids)
+    if err != nil {
+        return "", 0, err
+    }
+
+    if err := os.MkdirAll(s.archive.Dir, 0o755); err != nil {
+        return "", 0, err
+    }
+
+    path := filepath.Join(s.archive.Dir, "articles-"+now.UTC().Format("20060102T150405Z")+".ndjson.gz")
+    f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+    if err != nil {
+        return "", 0, err
+    }
+
+    gz := gzip.NewWriter(f)
+    enc := json.NewEncoder(gz)
+    for i := range articles {
+        if err := enc.Encode(archivedArticle{&articles[i], articles[i].CanonicalURL}); err != nil {
+            gz.Close()
+            f.Close()
+            return "", 0, err
+        }
+    }
+
+    // Only report success once everything is flushed to disk;
+    // the caller deletes the rows right after
+    if err := gz.Close(); err != nil {
+        f.Close()
+        return "", 0, err
+    }
+    if err := f.Sync(); err != nil {
+        f.Close()
+        return "", 0, err
+    }
+    if err := f.Close(); err != nil {
+        return "", 0, err
+    }
+
+    return path, int64(len(articles)), nil
+}
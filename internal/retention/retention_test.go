@@ -0,0 +1,171 @@
+package retention
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/migrations"
+)
+
+// newTestRepo returns a repository on a migrated SQLite file
+func newTestRepo(t *testing.T) (*sql.DB, database.Repository) {
+    t.Helper()
+
+    db, err := database.Connect(database.Config{
+        Driver: database.DriverSQLite,
+        Path:   filepath.Join(t.TempDir(), "retention.db"),
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+
+    migrator, err := database.NewMigrator(db, migrations.Files, database.DriverSQLite)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := migrator.Up(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    repo, err := database.NewRepository(db, database.DriverSQLite)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return db, repo
+}
+
+// saveArticles stores a fully populated article and a newer, bare one
+// and returns the first as it should come out of the archive
+func saveArticles(t *testing.T, repo database.Repository) models.Article {
+    t.Helper()
+    ctx := context.Background()
+
+    source := models.Source{Name: "Example", URL: "https://example.com/", SelectorTitle: "h2", Active: true}
+    if err := repo.CreateSource(ctx, &source); err != nil {
+        t.Fatal(err)
+    }
+
+    want := models.Article{
+        SourceID: source.ID, SourceName: source.Name,
+        Title:        "A great win for the home team",
+        URL:          "https://www.example.com/news/1?utm_source=home",
+        CanonicalURL: "https://example.com/news/1",
+        Category:     "sports",
+    }
+    if err := repo.SaveArticle(ctx, &want); err != nil {
+        t.Fatal(err)
+    }
+    content := models.ArticleContent{
+        Body:        "The home team won the final.\n\nFans celebrated all night.",
+        Summary:     "The home team won the final.",
+        ImageURL:    "https://example.com/img/1.jpg",
+        Author:      "Reporter",
+        PublishedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+        Language:    "en",
+    }
+    if err := repo.UpdateArticleContent(ctx, want.CanonicalURL, content); err != nil {
+        t.Fatal(err)
+    }
+
+    newer := models.Article{SourceID: source.ID, SourceName: source.Name, Title: "Newer headline", URL: "https://example.com/news/2", Category: "sports"}
+    if err := repo.SaveArticle(ctx, &newer); err != nil {
+        t.Fatal(err)
+    }
+
+    want.Summary = content.Summary
+    want.SummaryGenerated = true
+    want.Language = content.Language
+    want.Body = content.Body
+    want.ImageURL = content.ImageURL
+    want.Author = content.Author
+    want.PublishedAt = content.PublishedAt
+    return want
+}
+
+func TestArchiveKeepsEveryColumn(t *testing.T) {
+    tests := []struct {
+        mode     string
+        readBack func(t *testing.T, db *sql.DB, report *Report) models.Article
+    }{
+        {ArchiveTable, readArchiveTable},
+        {ArchiveNDJSON, readArchiveFile},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.mode, func(t *testing.T) {
+            db, repo := newTestRepo(t)
+            want := saveArticles(t, repo)
+
+            service, err := NewService(repo, Policy{Default: Rule{KeepLast: 1}}, ArchiveConfig{Mode: tt.mode, Dir: t.TempDir()})
+            if err != nil {
+                t.Fatal(err)
+            }
+            report, err := service.Run(context.Background(), false)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if report.Archived != 1 || report.Deleted != 1 {
+                t.Fatalf("archived %d and deleted %d, want 1 and 1", report.Archived, report.Deleted)
+            }
+
+            got := tt.readBack(t, db, report)
+            if got.Title != want.Title || got.URL != want.URL || got.CanonicalURL != want.CanonicalURL ||
+                got.Summary != want.Summary || got.SummaryGenerated != want.SummaryGenerated || got.Category != want.Category ||
+                got.Language != want.Language || got.Body != want.Body || got.ImageURL != want.ImageURL ||
+                got.Author != want.Author || !got.PublishedAt.Equal(want.PublishedAt) {
+                t.Errorf("archived %+v, want %+v", got, want)
+            }
+            if got.Sentiment == nil || *got.Sentiment <= 0 {
+                t.Errorf("archived sentiment %v, want a positive score", got.Sentiment)
+            }
+        })
+    }
+}
+
+func readArchiveTable(t *testing.T, db *sql.DB, report *Report) models.Article {
+    t.Helper()
+
+    var a models.Article
+    err := db.QueryRow(`SELECT title, url, canonical_url, summary, summary_generated, category, language, sentiment,
+                               body, image_url, author, published_at
+                        FROM articles_archive`).
+        Scan(&a.Title, &a.URL, &a.CanonicalURL, &a.Summary, &a.SummaryGenerated, &a.Category, &a.Language, &a.Sentiment,
+            &a.Body, &a.ImageURL, &a.Author, &a.PublishedAt)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return a
+}
+
+func readArchiveFile(t *testing.T, db *sql.DB, report *Report) models.Article {
+    t.Helper()
+
+    f, err := os.Open(report.ArchiveFile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+    gz, err := gzip.NewReader(f)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var line struct {
+        models.Article
+        CanonicalURL string `json:"canonical_url"`
+    }
+    if err := json.NewDecoder(gz).Decode(&line); err != nil {
+        t.Fatal(err)
+    }
+    line.Article.CanonicalURL = line.CanonicalURL
+    return line.Article
+}
@@ -0,0 +1,214 @@
+package robots
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+// maxRobotsSize is how much of a robots.txt is read; Google stops at 500 KiB too
+const maxRobotsSize = 500 << 10
+
+// retryAfter is how long an unreachable robots.txt counts as allowing
+// everything, and one failing with 5xx as disallowing everything, before
+// it is fetched again
+const retryAfter = 10 * time.Minute
+
+// Checker fetches robots.txt once per host, caches it and answers whether a
+// URL may be fetched and how long to wait between requests to its host.
+// One Checker is shared by every collector, so a site's robots.txt is
+// fetched once for all its sources, pages and article fetches.
+type Checker struct {
+    client    *http.Client // used when Check is given none
+    userAgent string       // sent when fetching robots.txt for the scraper's own agent
+    agent     string       // matched against User-agent lines
+    ttl       time.Duration
+
+    mu    sync.Mutex
+    hosts map[string]*entry
+}
+
+type entry struct {
+    ready   chan struct{} // closed once data is set
+    data    *robotstxt.RobotsData
+    expires time.Time
+}
+
+// Config holds Checker configuration
+type Config struct {
+    UserAgent string        // User-Agent header of the scraper
+    Agent     string        // robots.txt user-agent token (default: product token of UserAgent)
+    Timeout   time.Duration // robots.txt request timeout
+    TTL       time.Duration // how long a robots.txt is cached (default 24h)
+}
+
+// NewChecker creates a Checker with an empty cache
+func NewChecker(cfg Config) *Checker {
+    if cfg.Agent == "" {
+        cfg.Agent = AgentToken(cfg.UserAgent)
+    }
+    if cfg.Timeout <= 0 {
+        cfg.Timeout = 30 * time.Second
+    }
+    if cfg.TTL <= 0 {
+        cfg.TTL = 24 * time.Hour
+    }
+
+    return &Checker{
+        client:    &http.Client{Timeout: cfg.Timeout},
+        userAgent: cfg.UserAgent,
+        agent:     strings.ToLower(cfg.Agent),
+        ttl:       cfg.TTL,
+        hosts:     make(map[string]*entry),
+    }
+}
+
+// AgentToken returns the product token robots.txt groups are matched on
+// "NewsBot/1.0 (+https://example.com/bot)" gives "NewsBot"; for a
+// browser-style "Mozilla/5.0 (compatible; NewsBot/1.0)" the compatible
+// product is used.
+func AgentToken(userAgent string) string {
+    if i := strings.Index(userAgent, "compatible;"); i >= 0 {
+        userAgent = userAgent[i+len("compatible;"):]
+    }
+    token := strings.TrimSpace(userAgent)
+    if i := strings.IndexAny(token, "/ ;)"); i >= 0 {
+        token = token[:i]
+    }
+    if token == "" {
+        return "*"
+    }
+    return token
+}
+
+// Check reports whether u may be fetched by userAgent and the Crawl-delay of its host
+// Rules are those of the group for the agent's product token, else the
+// "*" group; an empty userAgent is the scraper's own agent. A robots.txt
+// that is missing (4xx) allows everything; one that fails with 5xx
+// disallows everything until it is fetched again.
+// When robots.txt has to be fetched, it is requested as userAgent and,
+// unless client is nil, with client, so that it goes out the way the
+// pages will (through a source's proxy, say). No response through such a
+// client may be the proxy's fault, so it allows the request without
+// being cached.
+func (c *Checker) Check(ctx context.Context, u *url.URL, userAgent string, client *http.Client) (bool, time.Duration) {
+    if u.Path == "/robots.txt" {
+        return true, 0
+    }
+
+    agent := c.agent
+    if userAgent != "" {
+        agent = strings.ToLower(AgentToken(userAgent))
+    }
+    data := c.robotsFor(ctx, u, userAgent, client)
+    path := u.EscapedPath()
+    if path == "" {
+        path = "/"
+    }
+    if u.RawQuery != "" {
+        path += "?" + u.RawQuery
+    }
+    // TestAgent, unlike the group, knows a 5xx robots.txt disallows everything
+    return data.TestAgent(path, agent), data.FindGroup(agent).CrawlDelay
+}
+
+// robotsFor returns the cached robots.txt of u's host, fetching it when
+// missing or expired. Concurrent callers for the same host share one
+// fetch, made the first caller's way.
+func (c *Checker) robotsFor(ctx context.Context, u *url.URL, userAgent string, client *http.Client) *robotstxt.RobotsData {
+    key := u.Scheme + "://" + u.Host
+
+    c.mu.Lock()
+    e, ok := c.hosts[key]
+    if ok {
+        select {
+        case <-e.ready:
+            if time.Now().After(e.expires) {
+                ok = false
+            }
+        default:
+        }
+    }
+    if !ok {
+        e = &entry{ready: make(chan struct{})}
+        c.hosts[key] = e
+        c.mu.Unlock()
+
+        data, ttl := c.fetch(ctx, key, userAgent, client)
+        if ctx.Err() != nil {
+            // Our own cancellation says nothing about the site: fetch again next time
+            ttl = 0
+        }
+        e.data, e.expires = data, time.Now().Add(ttl)
+        close(e.ready)
+        return data
+    }
+    c.mu.Unlock()
+
+    select {
+    case <-e.ready:
+        return e.data
+    case <-ctx.Done():
+        return allowAll
+    }
+}
+
+// fetch downloads and parses robots.txt and says how long to cache it
+func (c *Checker) fetch(ctx context.Context, site, userAgent string, client *http.Client) (*robotstxt.RobotsData, time.Duration) {
+    data, status, err := c.download(ctx, site+"/robots.txt", userAgent, client)
+    if err != nil {
+        if client != nil {
+            // Maybe only the proxy is down: don't cache the site as unreachable
+            log.Printf("robots.txt of %s unavailable, allowing this request: %v", site, err)
+            return allowAll, 0
+        }
+        log.Printf("robots.txt of %s unavailable, allowing all for %s: %v", site, retryAfter, err)
+        return allowAll, retryAfter
+    }
+    if status >= http.StatusInternalServerError {
+        // A server error is usually short-lived; don't keep the site shut for a whole TTL
+        log.Printf("robots.txt of %s failed with status %d, disallowing all for %s", site, status, retryAfter)
+        return data, retryAfter
+    }
+    return data, c.ttl
+}
+
+func (c *Checker) download(ctx context.Context, robotsURL, userAgent string, client *http.Client) (*robotstxt.RobotsData, int, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+    if err != nil {
+        return nil, 0, err
+    }
+    if userAgent == "" {
+        userAgent = c.userAgent
+    }
+    req.Header.Set("User-Agent", userAgent)
+
+    if client == nil {
+        client = c.client
+    }
+    resp, err := client.Do(req)
+    if err != nil {
+        return nil, 0, err
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsSize))
+    if err != nil {
+        return nil, 0, err
+    }
+    data, err := robotstxt.FromStatusAndBytes(resp.StatusCode, body)
+    if err != nil {
+        return nil, 0, fmt.Errorf("status %d: %w", resp.StatusCode, err)
+    }
+    return data, resp.StatusCode, nil
+}
+
+var allowAll, _ = robotstxt.FromStatusAndBytes(http.StatusNotFound, nil)
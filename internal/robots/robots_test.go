@@ -0,0 +1,144 @@
+package robots
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `User-agent: *
+Disallow: /private/
+Crawl-delay: 2
+
+User-agent: NewsBot
+Disallow: /no-bots/
+Allow: /private/press/
+Crawl-delay: 5
+`
+
+// newSite serves robots.txt with the given status and body
+func newSite(t *testing.T, status int, body string) *httptest.Server {
+    t.Helper()
+
+    site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/robots.txt" {
+            http.NotFound(w, r)
+            return
+        }
+        w.WriteHeader(status)
+        w.Write([]byte(body))
+    }))
+    t.Cleanup(site.Close)
+    return site
+}
+
+func TestCheck(t *testing.T) {
+    tests := []struct {
+        name      string
+        status    int
+        path      string
+        userAgent string // "" is the checker's own NewsBot
+        want      bool
+        wantDelay time.Duration
+    }{
+        {"own group allows", http.StatusOK, "/news/1", "", true, 5 * time.Second},
+        {"own group disallows", http.StatusOK, "/no-bots/1", "", false, 5 * time.Second},
+        {"own group allow wins", http.StatusOK, "/private/press/1", "", true, 5 * time.Second},
+        {"own group has no wildcard rules", http.StatusOK, "/private/1", "", true, 5 * time.Second},
+        {"other agent gets the wildcard group", http.StatusOK, "/private/1", "OtherBot/2.0", false, 2 * time.Second},
+        {"compatible token", http.StatusOK, "/no-bots/1", "Mozilla/5.0 (compatible; NewsBot/1.0)", false, 5 * time.Second},
+        {"robots.txt itself", http.StatusOK, "/robots.txt", "", true, 0},
+        {"missing allows all", http.StatusNotFound, "/no-bots/1", "", true, 0},
+        {"server error disallows all", http.StatusServiceUnavailable, "/news/1", "", false, 0},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            site := newSite(t, tt.status, robotsTxt)
+            c := NewChecker(Config{UserAgent: "NewsBot/1.0 (+https://example.com/bot)"})
+
+            u, _ := url.Parse(site.URL + tt.path)
+            allowed, delay := c.Check(context.Background(), u, tt.userAgent, nil)
+            if allowed != tt.want || delay != tt.wantDelay {
+                t.Errorf("Check(%s, %q) = %v, %s, want %v, %s", tt.path, tt.userAgent, allowed, delay, tt.want, tt.wantDelay)
+            }
+        })
+    }
+}
+
+func TestAgentToken(t *testing.T) {
+    tests := []struct {
+        userAgent string
+        want      string
+    }{
+        {"NewsBot/1.0 (+https://example.com/bot)", "NewsBot"},
+        {"Mozilla/5.0 (compatible; NewsBot/1.0; +https://example.com/bot)", "NewsBot"},
+        {"Mozilla/5.0 (X11; Linux x86_64)", "Mozilla"},
+        {"NewsBot", "NewsBot"},
+        {"", "*"},
+    }
+
+    for _, tt := range tests {
+        if got := AgentToken(tt.userAgent); got != tt.want {
+            t.Errorf("AgentToken(%q) = %q, want %q", tt.userAgent, got, tt.want)
+        }
+    }
+}
+
+// recordingTransport counts the requests it sends and the last User-Agent
+type recordingTransport struct {
+    requests  atomic.Int32
+    userAgent atomic.Value
+    err       error
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+    rt.requests.Add(1)
+    rt.userAgent.Store(r.Header.Get("User-Agent"))
+    if rt.err != nil {
+        return nil, rt.err
+    }
+    return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestCheckWithClient(t *testing.T) {
+    site := newSite(t, http.StatusOK, robotsTxt)
+    u, _ := url.Parse(site.URL + "/no-bots/1")
+    ctx := context.Background()
+
+    tests := []struct {
+        name         string
+        err          error // of the client's transport
+        want         bool
+        wantRequests int32 // after two checks
+    }{
+        {"fetched through the client once", nil, true, 1},
+        {"no response is not cached", errors.New("proxyconnect tcp: connection refused"), true, 2},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            c := NewChecker(Config{UserAgent: "NewsBot/1.0"})
+            transport := &recordingTransport{err: tt.err}
+            client := &http.Client{Transport: transport}
+
+            for range 2 {
+                // The source's own agent is sent and matched: OtherBot may visit /no-bots/
+                if allowed, _ := c.Check(ctx, u, "OtherBot/2.0", client); allowed != tt.want {
+                    t.Errorf("Check() = %v, want %v", allowed, tt.want)
+                }
+            }
+            if got := transport.requests.Load(); got != tt.wantRequests {
+                t.Errorf("client sent %d requests, want %d", got, tt.wantRequests)
+            }
+            if got, _ := transport.userAgent.Load().(string); got != "OtherBot/2.0" {
+                t.Errorf("robots.txt requested as %q, want the source's user agent", got)
+            }
+        })
+    }
+}
@@ -3,33 +3,45 @@ package scheduler
 import (
 	"context"
 	"log"
+	"time"
 
 	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/retention"
 	"news-scraper/internal/scraper"
 
 	"github.com/robfig/cron/v3"
 )
 
 type Scheduler struct {
-    cron    *cron.Cron
-    scraper *scraper.Scraper
-    repo    *database.Repository
+    cron      *cron.Cron
+    scraper   *scraper.Scraper
+    repo      database.Repository
+    retention *retention.Service
+    dryRun    bool // Only report what retention would remove
 }
 
-func NewScheduler(scraper *scraper.Scraper, repo *database.Repository) *Scheduler {
+func NewScheduler(scraper *scraper.Scraper, repo database.Repository, retention *retention.Service, dryRun bool) *Scheduler {
     return &Scheduler{
-        cron:    cron.New(),
-        scraper: scraper,
-        repo:    repo,
+        cron:      cron.New(),
+        scraper:   scraper,
+        repo:      repo,
+        retention: retention,
+        dryRun:    dryRun,
     }
 }
 
-func (s *Scheduler) Start(schedule string) error {
+// Start registers the scrape and cleanup jobs and starts the cron runner
+func (s *Scheduler) Start(schedule, cleanupSchedule string) error {
     _, err := s.cron.AddFunc(schedule, func() {
         log.Println("Starting scheduled scrape...")
-        ctx := context.Background()
-        if err := s.scraper.ScrapeAll(ctx); err != nil {
+        ctx := context.Background() // cancelled by the scraper's Shutdown, each source has a deadline
+        report, err := s.scraper.ScrapeAll(ctx)
+        if report == nil {
             log.Printf("Scheduled scrape failed: %v", err)
+        } else if err != nil {
+            failed := len(report.Sources) - report.Count(models.RunOK) - report.Count(models.RunUnchanged)
+            log.Printf("Scheduled scrape: %d of %d sources failed: %v", failed, len(report.Sources), err)
         }
     })
 
@@ -37,13 +49,22 @@ func (s *Scheduler) Start(schedule string) error {
         return err
     }
 
-    //runs every 2 hour
-    _, err =s.cron.AddFunc("0 */2 * * *", func ()  {
+    if cleanupSchedule == "" {
+        cleanupSchedule = "0 */2 * * *" // every 2 hours
+    }
+
+    _, err =s.cron.AddFunc(cleanupSchedule, func ()  {
         log.Println("Starting scheduled article cleanup...")
         ctx:= context.Background()
-        if err := s.clearArticles(ctx); err != nil {
+        if err := s.applyRetention(ctx); err != nil {
             log.Printf("Scheduled cleanup failed : %v", err)
         }
+        if err := s.purgeJobs(ctx); err != nil {
+            log.Printf("Scheduled job purge failed : %v", err)
+        }
+        if err := s.purgeRuns(ctx); err != nil {
+            log.Printf("Scheduled run history purge failed : %v", err)
+        }
     })
 
     if err != nil {
@@ -52,19 +73,51 @@ func (s *Scheduler) Start(schedule string) error {
 
     s.cron.Start()
     log.Printf("Scheduler started with schedule: %s", schedule)
-    log.Println("Article cleanup shceduled to run every two hours")
+    log.Printf("Article cleanup scheduled with schedule: %s", cleanupSchedule)
+    return nil
+}
+
+// applyRetention expires articles according to the retention policy
+func (s *Scheduler) applyRetention(ctx context.Context) error {
+    report, err := s.retention.Run(ctx, s.dryRun)
+    if err != nil {
+        return err
+    }
+
+    if report.DryRun {
+        log.Printf("Retention dry run: %d of %d articles would be removed", report.Expired, report.Scanned)
+        for _, g := range report.Groups {
+            if g.Expired > 0 {
+                log.Printf("  %s: %d of %d would be removed", g.Scope, g.Expired, g.Matched)
+            }
+        }
+    }
+    return nil
+}
+
+// purgeJobs drops finished queue entries after a week
+// Until then they keep the same article from being fetched again on every run
+func (s *Scheduler) purgeJobs(ctx context.Context) error {
+    purged, err := s.repo.PurgeDoneJobs(ctx, 7*24*time.Hour)
+    if err != nil {
+        return err
+    }
+    log.Printf("Purged %d completed jobs", purged)
     return nil
 }
 
-func (s *Scheduler) clearArticles (ctx context.Context) error {
-    log.Println("Clearing all articles from database...")
-    if err := s.repo.ClearAllArticles(ctx); err != nil {
+// purgeRuns drops scrape history older than 90 days
+// That is long enough to answer a publisher asking what we fetched and when.
+func (s *Scheduler) purgeRuns(ctx context.Context) error {
+    purged, err := s.repo.PurgeScrapeRuns(ctx, 90*24*time.Hour)
+    if err != nil {
         return err
     }
-    log.Println("Successfully cleared all articles")
+    log.Printf("Purged %d scrape runs", purged)
     return nil
 }
 
+// Stop stops the cron runner and waits for running jobs to return
 func (s *Scheduler) Stop() {
-    s.cron.Stop()
+    <-s.cron.Stop().Done()
 }
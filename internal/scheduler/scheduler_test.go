@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/retention"
+)
+
+// newTestScheduler returns a scheduler on an in-memory repository holding n articles of one source
+func newTestScheduler(t *testing.T, policy retention.Policy, dryRun bool, n int) (*Scheduler, *database.MemoryRepository, models.Source) {
+    t.Helper()
+    ctx := context.Background()
+
+    repo := database.NewMemoryRepository()
+    source := models.Source{Name: "Example", URL: "https://example.com/", SelectorTitle: "h2", Active: true}
+    if err := repo.CreateSource(ctx, &source); err != nil {
+        t.Fatal(err)
+    }
+    for i := 1; i <= n; i++ {
+        err := repo.SaveArticle(ctx, &models.Article{
+            SourceID: source.ID, SourceName: source.Name, Title: fmt.Sprintf("Headline %d", i),
+            URL: fmt.Sprintf("https://example.com/news/%d", i), Category: "general",
+        })
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    service, err := retention.NewService(repo, policy, retention.ArchiveConfig{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    return NewScheduler(nil, repo, service, dryRun), repo, source
+}
+
+func TestStartRejectsInvalidSchedule(t *testing.T) {
+    s, _, _ := newTestScheduler(t, retention.Policy{}, false, 0)
+
+    tests := []struct {
+        name     string
+        schedule string
+        cleanup  string
+    }{
+        {"scrape schedule", "every now and then", ""},
+        {"cleanup schedule", "*/30 * * * *", "61 * * * *"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if err := s.Start(tt.schedule, tt.cleanup); err == nil {
+                s.Stop()
+                t.Errorf("Start(%q, %q) accepted an invalid schedule", tt.schedule, tt.cleanup)
+            }
+        })
+    }
+}
+
+func TestApplyRetention(t *testing.T) {
+    tests := []struct {
+        name   string
+        policy retention.Policy
+        dryRun bool
+        want   int
+    }{
+        {"keep last", retention.Policy{Default: retention.Rule{KeepLast: 1}}, false, 1},
+        {"dry run", retention.Policy{Default: retention.Rule{KeepLast: 1}}, true, 3},
+        {"keep days", retention.Policy{Default: retention.Rule{KeepDays: 1}}, false, 3},
+        {"30 days without a default", retention.Policy{}, false, 3},
+        {"keep forever", retention.Policy{Default: retention.Rule{KeepForever: true}}, false, 3},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            s, repo, source := newTestScheduler(t, tt.policy, tt.dryRun, 3)
+            ctx := context.Background()
+
+            if err := s.applyRetention(ctx); err != nil {
+                t.Fatal(err)
+            }
+            articles, err := repo.GetArticlesBySource(ctx, source.ID)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(articles) != tt.want {
+                t.Errorf("got %d articles, want %d", len(articles), tt.want)
+            }
+        })
+    }
+}
+
+func TestPurgeJobsKeepsRecentJobs(t *testing.T) {
+    s, repo, _ := newTestScheduler(t, retention.Policy{}, false, 0)
+    ctx := context.Background()
+
+    if _, err := repo.EnqueueJob(ctx, models.JobFetchArticle, "https://example.com/news/1", "{}", 1); err != nil {
+        t.Fatal(err)
+    }
+    jobs, err := repo.LeaseJobs(ctx, 1, time.Minute)
+    if err != nil || len(jobs) != 1 {
+        t.Fatalf("LeaseJobs: %v, %d jobs", err, len(jobs))
+    }
+    if err := repo.AckJob(ctx, jobs[0]); err != nil {
+        t.Fatal(err)
+    }
+
+    if err := s.purgeJobs(ctx); err != nil {
+        t.Fatal(err)
+    }
+
+    // A job finished within the week still keeps its article from being queued again
+    queued, err := repo.EnqueueJob(ctx, models.JobFetchArticle, "https://example.com/news/1", "{}", 1)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if queued {
+        t.Error("recently finished job was purged")
+    }
+}
@@ -2,99 +2,128 @@ package scraper
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
 	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+	"news-scraper/internal/stories"
 
 	"github.com/gocolly/colly/v2"
 )
 
-// ScrapeWithPagination scrapes multiple pages (for sites with pagination)
+// ScrapeWithPagination scrapes up to maxPages listing pages of a source
+// Sources without their own next-page selector or URL template follow
+// the common next-link patterns.
 func (s *Scraper) ScrapeWithPagination(ctx context.Context, source models.Source, maxPages int) error {
-    c := colly.NewCollector(
-         colly.UserAgent(s.userAgent),
-         colly.AllowedDomains(extractDomain(source.URL)),
-         colly.MaxDepth(maxPages),
-    )
-
-    // Configure rate limiting
-    c.Limit(&colly.LimitRule{
-        DomainGlob:  "*",
-        Parallelism: 1,
-        Delay:       s.timeout / time.Duration(s.rateLimit),
-    })
-
-    pageCount := 0
-
-    // Find articles on each page
-    c.OnHTML(source.SelectorTitle, func(e *colly.HTMLElement) {
-        article := models.Article{
-            SourceID: source.ID,
-            Title:    e.Text,
-        }
-
-        if href, exists := e.DOM.Attr("href"); exists {
-            article.URL = e.Request.AbsoluteURL(href)
-        } else if href, exists := e.DOM.Find("a").Attr("href"); exists {
-            article.URL = e.Request.AbsoluteURL(href)
-        }
-
-        if article.Title != "" && article.URL != "" {
-            if err := s.repo.SaveArticle(ctx, &article); err != nil {
-                log.Printf("Failed to save article: %v", err)
-            }
-        }
-    })
-
-    // Find next page link (common patterns)
-    c.OnHTML("a[rel='next'], a.next, a.pagination-next, .next-page a", func(e *colly.HTMLElement) {
-        if pageCount < maxPages {
-            nextPage := e.Attr("href")
-            if nextPage != "" {
-                pageCount++
-                log.Printf("Following to page %d: %s", pageCount+1, nextPage)
-                e.Request.Visit(nextPage)
-            }
-        }
-    })
-
-    c.OnError(func(r *colly.Response, err error) {
-        log.Printf("Error on %s: %v", r.Request.URL, err)
-    })
-
-    return c.Visit(source.URL)
+    source.MaxPages = maxPages
+    if source.SelectorNext == "" && source.PageURLTemplate == "" {
+        source.SelectorNext = defaultNextSelector
+    }
+
+    ctx, release, err := s.track(ctx)
+    if err != nil {
+        return err
+    }
+    defer release()
+
+    run := &models.ScrapeRun{SourceID: source.ID, SourceName: source.Name, StartedAt: time.Now().UTC()}
+    sourceCtx, cancel := context.WithTimeout(ctx, s.sourceDeadline(source))
+    defer cancel()
+    err = s.scrapeSourceWithColly(sourceCtx, source, run)
+    s.recordRun(ctx, run, err)
+    return err
 }
 
 // ScrapeWithJavaScript scrapes sites that require JavaScript
 // NOTE: Requires chromedp or similar for full JS support
+// Like the other scrapes it runs within the source's deadline and is
+// waited for by Shutdown.
 func (s *Scraper) ScrapeWithCache(ctx context.Context, source models.Source, cacheDir string) error {
+    ctx, release, err := s.track(ctx)
+    if err != nil {
+        return err
+    }
+    defer release()
+
+    sourceCtx, cancel := context.WithTimeout(ctx, s.sourceDeadline(source))
+    defer cancel()
+    return s.scrapeWithCache(sourceCtx, source, cacheDir)
+}
+
+func (s *Scraper) scrapeWithCache(ctx context.Context, source models.Source, cacheDir string) error {
     c := colly.NewCollector(
         colly.UserAgent(s.userAgent),
         colly.AllowedDomains(extractDomain(source.URL)),
         colly.CacheDir(cacheDir), // Enable caching
+        colly.StdlibContext(ctx),
     )
 
-    c.Limit(&colly.LimitRule{
-        DomainGlob:  "*",
-        Parallelism: 1,
-        Delay:       time.Second / time.Duration(s.rateLimit),
-    })
+    if err := s.customize(c, source); err != nil {
+        return err
+    }
+    if err := s.useProxy(c, source); err != nil {
+        return err
+    }
 
+    c.OnResponse(fixCharset)
+    s.fetchPolitely(ctx, c, source, nil)
+    s.retryTransient(ctx, c)
+
+    var articles []models.Article
     c.OnHTML(source.SelectorTitle, func(e *colly.HTMLElement) {
         article := models.Article{
-            SourceID: source.ID,
-            Title:    e.Text,
+            SourceID:   source.ID,
+            SourceName: source.Name,
+            Title:      e.Text,
+            Category:   source.DefaultCategory,
         }
 
         if href, exists := e.DOM.Find("a").Attr("href"); exists {
             article.URL = e.Request.AbsoluteURL(href)
         }
 
+        normalizeArticle(&article)
         if article.Title != "" && article.URL != "" {
-            s.repo.SaveArticle(ctx, &article)
+            articles = append(articles, article)
         }
     })
 
-    return c.Visit(source.URL)
+    if err := s.visit(c, source.URL); err != nil {
+        return err
+    }
+    // What was fetched is saved even when the deadline passed meanwhile
+    _, err := s.saveScraped(context.WithoutCancel(ctx), source, articles)
+    return err
+}
+
+// saveScraped writes the articles collected by a run in one batch
+// URLs are canonicalized first so tracking and AMP variants upsert onto one row
+func (s *Scraper) saveScraped(ctx context.Context, source models.Source, articles []models.Article) (models.SaveResult, error) {
+    for i := range articles {
+        articles[i].CanonicalURL = canonicalURL(source, articles[i].URL)
+        if articles[i].Language == "" {
+            articles[i].Language = nlp.DetectLanguage(articles[i].Title+" "+articles[i].Summary, "")
+        }
+    }
+
+    saved, err := s.repo.SaveArticles(ctx, articles)
+    if err != nil {
+        return saved, fmt.Errorf("failed to save articles from %s: %w", source.Name, &storeError{err})
+    }
+    log.Printf("Saved %s: %d inserted, %d updated, %d unchanged",
+        source.Name, saved.Inserted, saved.Updated, saved.Unchanged)
+
+    // Group new articles with the same story from other sources
+    if saved.Inserted > 0 {
+        urls := make([]string, len(articles))
+        for i, a := range articles {
+            urls[i] = a.CanonicalURL
+        }
+        if _, err := stories.AssignArticles(ctx, s.repo, urls); err != nil {
+            log.Printf("Failed to assign stories for %s: %v", source.Name, err)
+        }
+    }
+    return saved, nil
 }
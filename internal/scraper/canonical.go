@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/urlnorm"
+)
+
+// canonicalURL applies the generic and per-source URL rules
+// A URL that can't be parsed is kept as it is.
+func canonicalURL(source models.Source, raw string) string {
+    canonical, err := urlnorm.Canonicalize(raw, urlnorm.SplitParams(source.KeepQueryParams))
+    if err != nil {
+        return raw
+    }
+    return canonical
+}
+
+// pageCanonicalURL returns the rel=canonical URL of an article page, or ""
+// if it shouldn't be trusted. Canonicals pointing at another host or at a
+// site's front page are usually template mistakes, and following them
+// would merge unrelated articles into one.
+func pageCanonicalURL(source models.Source, articleURL, canonical string) string {
+    canonical = canonicalURL(source, canonical)
+
+    page, err := url.Parse(canonicalURL(source, articleURL))
+    if err != nil {
+        return ""
+    }
+    target, err := url.Parse(canonical)
+    if err != nil || target.Host != page.Host || target.Path == "/" {
+        return ""
+    }
+    return canonical
+}
+
+// DedupeReport summarizes a MergeDuplicateURLs run
+type DedupeReport struct {
+    Scanned int
+    Renamed int // articles re-keyed to their canonical URL
+    Merged  int // duplicates folded into another article
+}
+
+// MergeDuplicateURLs canonicalizes the keys of every stored article and
+// merges articles that turn out to be the same one. An article already
+// stored under the canonical URL is kept; otherwise the oldest one of the
+// group is re-keyed and the others merged into it. Article URLs are left
+// as the source gave them. It's meant to be run once after canonicalization was
+// introduced, or after a source's keep_query_params changed.
+func MergeDuplicateURLs(ctx context.Context, repo database.Repository, dryRun bool) (DedupeReport, error) {
+    var report DedupeReport
+
+    articles, err := repo.GetArticleURLs(ctx)
+    if err != nil {
+        return report, err
+    }
+    report.Scanned = len(articles)
+
+    // Keys already in the table, plus the ones this run re-keys to
+    seen := make(map[string]bool, len(articles))
+    for _, a := range articles {
+        seen[a.CanonicalURL] = true
+    }
+
+    sources := make(map[int]models.Source)
+    for _, a := range articles {
+        source, ok := sources[a.SourceID]
+        if !ok {
+            s, err := repo.GetSourceByID(ctx, a.SourceID)
+            if err != nil {
+                return report, err
+            }
+            if s != nil {
+                source = *s
+            }
+            sources[a.SourceID] = source
+        }
+
+        canonical := canonicalURL(source, a.CanonicalURL)
+        if canonical == a.CanonicalURL {
+            continue
+        }
+
+        if seen[canonical] {
+            report.Merged++
+        } else {
+            report.Renamed++
+        }
+        seen[canonical] = true
+
+        if dryRun {
+            continue
+        }
+        if err := repo.MergeArticleURL(ctx, a.CanonicalURL, canonical); err != nil {
+            return report, fmt.Errorf("failed to merge %s into %s: %w", a.CanonicalURL, canonical, err)
+        }
+    }
+
+    return report, nil
+}
@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// conditionalPages sends the validators stored for each page a run
+// fetches and notices pages that have not changed since the last run
+// New validators are held back until commit, after the run's articles
+// are saved: a run that fails must not make the next one skip the page.
+type conditionalPages struct {
+    repo database.PageCacheStore
+
+    mu        sync.Mutex
+    pending   []models.PageCache
+    unchanged map[string]bool
+}
+
+// Keys of the colly context that carry a page's stored validators
+// Pages are stored under the URL requested, not the one redirected to.
+const (
+    pageURLKey   = "page_url"
+    pageCacheKey = "page_cache"
+)
+
+// fetchConditionally makes c send If-None-Match and If-Modified-Since for
+// pages fetched before, and skip parsing a page whose body hashes the same
+// as last time. A page answered with 304 comes back from s.visit as a
+// FetchError; check it with notModified.
+func (s *Scraper) fetchConditionally(ctx context.Context, c *colly.Collector, source models.Source) *conditionalPages {
+    p := &conditionalPages{repo: s.repo, unchanged: make(map[string]bool)}
+
+    c.OnRequest(func(r *colly.Request) {
+        if r.Ctx.Get(pageURLKey) != "" {
+            return // a retry; the validators are already set
+        }
+        r.Ctx.Put(pageURLKey, r.URL.String())
+
+        page, err := p.repo.GetPageCache(ctx, r.URL.String())
+        if err != nil {
+            log.Printf("Failed to load cached validators of %s: %v", r.URL, err)
+            return
+        }
+        if page == nil {
+            return
+        }
+        r.Ctx.Put(pageCacheKey, page)
+        if page.ETag != "" {
+            r.Headers.Set("If-None-Match", page.ETag)
+        }
+        if page.LastModified != "" {
+            r.Headers.Set("If-Modified-Since", page.LastModified)
+        }
+    })
+
+    c.OnResponse(func(r *colly.Response) {
+        pageURL := r.Ctx.Get(pageURLKey)
+        sum := sha256.Sum256(r.Body)
+        fresh := models.PageCache{
+            SourceID:     source.ID,
+            URL:          pageURL,
+            ETag:         r.Headers.Get("ETag"),
+            LastModified: r.Headers.Get("Last-Modified"),
+            ContentHash:  hex.EncodeToString(sum[:]),
+            CheckedAt:    time.Now().UTC(),
+        }
+
+        p.mu.Lock()
+        p.pending = append(p.pending, fresh)
+        if old, ok := r.Ctx.GetAny(pageCacheKey).(*models.PageCache); ok && old.ContentHash == fresh.ContentHash {
+            // Without a body colly has no HTML to parse
+            p.unchanged[pageURL] = true
+            r.Body = nil
+        }
+        p.mu.Unlock()
+    })
+
+    return p
+}
+
+// isUnchanged reports whether the page's body was the same as last run
+func (p *conditionalPages) isUnchanged(pageURL string) bool {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    return p.unchanged[pageURL]
+}
+
+// commit stores the validators of the pages fetched this run
+func (p *conditionalPages) commit(ctx context.Context) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    for i := range p.pending {
+        if err := p.repo.SavePageCache(ctx, &p.pending[i]); err != nil {
+            log.Printf("Failed to store validators of %s: %v", p.pending[i].URL, err)
+        }
+    }
+    p.pending = nil
+}
+
+// notModified reports whether a fetch was answered with 304 Not Modified
+func notModified(err error) bool {
+    var fetchErr *FetchError
+    return errors.As(err, &fetchErr) && fetchErr.StatusCode == http.StatusNotModified
+}
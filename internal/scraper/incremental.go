@@ -0,0 +1,25 @@
+package scraper
+
+// knownStreak counts the stored articles found in a row on a listing
+// sorted newest-first. Once limit of them follow each other the rest of
+// the listing is older and the run stops collecting. A zero limit never stops.
+type knownStreak struct {
+    limit int
+    known map[string]bool
+    count int
+}
+
+// add counts an article by its canonical URL and reports whether it is known
+func (k *knownStreak) add(url string) bool {
+    if k.known[url] {
+        k.count++
+        return true
+    }
+    k.count = 0
+    return false
+}
+
+// stopped reports whether the run has seen enough known articles in a row
+func (k *knownStreak) stopped() bool {
+    return k.limit > 0 && k.count >= k.limit
+}
@@ -0,0 +1,42 @@
+package scraper
+
+import (
+	"news-scraper/internal/models"
+	"news-scraper/internal/textnorm"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Column sizes in characters, from the migrations
+// URLs are never cut: a truncated link is a broken one, so an image URL
+// that doesn't fit is dropped instead.
+const (
+    maxTitleLength   = 512
+    maxSummaryLength = 16000   // TEXT is 64 KB, and a character may take 4 bytes
+    maxBodyLength    = 4000000 // MEDIUMTEXT is 16 MB
+    maxAuthorLength  = 255
+    maxURLLength     = 1024
+)
+
+// fixCharset is an OnResponse callback that converts pages declaring
+// their charset only in a <meta> tag (or not at all) to UTF-8
+// It must be registered before any OnHTML callback reads the page.
+func fixCharset(r *colly.Response) {
+    r.Body = textnorm.ToUTF8(r.Body, r.Headers.Get("Content-Type"))
+}
+
+// normalizeArticle cleans the text a listing scrape extracted
+func normalizeArticle(a *models.Article) {
+    a.Title = textnorm.Truncate(textnorm.Line(a.Title), maxTitleLength)
+    a.Summary = textnorm.Truncate(textnorm.Line(a.Summary), maxSummaryLength)
+}
+
+// normalizeContent cleans the text extracted from an article page
+func normalizeContent(content *models.ArticleContent) {
+    content.Body = textnorm.Truncate(textnorm.Paragraphs(content.Body), maxBodyLength)
+    content.Description = textnorm.Truncate(textnorm.Line(content.Description), maxSummaryLength)
+    content.Author = textnorm.Truncate(textnorm.Line(content.Author), maxAuthorLength)
+    if len(content.ImageURL) > maxURLLength {
+        content.ImageURL = ""
+    }
+}
@@ -0,0 +1,77 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"news-scraper/internal/models"
+)
+
+// defaultMaxPages is how many listing pages a paginated source gets when max_pages is unset
+const defaultMaxPages = 5
+
+// defaultNextSelector finds the next-page link on sites that use one of the common patterns
+const defaultNextSelector = "a[rel='next'], a.next, a.pagination-next, .next-page a"
+
+// maxPages returns how many listing pages of the source one run may visit
+func maxPages(source models.Source) int {
+    if source.SelectorNext == "" && source.PageURLTemplate == "" {
+        return 1
+    }
+    if source.MaxPages <= 0 {
+        return defaultMaxPages
+    }
+    return source.MaxPages
+}
+
+// nextPageURL returns the URL of listing page n: the source's template
+// with {n} filled in, resolved against its URL, or else the next-page
+// link found on the previous page (which may be empty)
+func nextPageURL(source models.Source, n int, found string) string {
+    if source.PageURLTemplate == "" {
+        return found
+    }
+
+    ref, err := url.Parse(strings.ReplaceAll(source.PageURLTemplate, "{n}", strconv.Itoa(n)))
+    if err != nil {
+        return ""
+    }
+    base, err := url.Parse(source.URL)
+    if err != nil {
+        return ""
+    }
+    return base.ResolveReference(ref).String()
+}
+
+// knownURLs returns the canonical URLs of the source's stored articles
+// Both the stored key and the canonical form of the stored URL count, as
+// the key may have been moved to the page's rel=canonical since.
+func (s *Scraper) knownURLs(ctx context.Context, source models.Source) (map[string]bool, error) {
+    articles, err := s.repo.GetArticleURLsBySource(ctx, source.ID)
+    if err != nil {
+        return nil, err
+    }
+
+    known := make(map[string]bool, 2*len(articles))
+    for _, a := range articles {
+        known[a.CanonicalURL] = true
+        known[canonicalURL(source, a.URL)] = true
+    }
+    return known, nil
+}
+
+// hasNewArticles reports whether any of a page's articles is neither
+// stored nor seen on an earlier page, and adds them all to seen
+func hasNewArticles(source models.Source, articles []models.Article, known, seen map[string]bool) bool {
+    found := false
+    for _, a := range articles {
+        u := canonicalURL(source, a.URL)
+        if !known[u] && !seen[u] {
+            found = true
+        }
+        seen[u] = true
+    }
+    return found
+}
@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"news-scraper/internal/models"
+	"news-scraper/internal/proxy"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// useProxy routes c through the source's proxy pool, if it has one, and
+// reports each request's outcome to the pool so failing proxies are
+// ejected. Requests that get no response or a 407 count as failures of
+// the proxy; any other answer means it works. When every proxy is
+// ejected, requests fail with proxy.ErrNoHealthyProxy without going out.
+// Collectors are synchronous, so the proxy picked last is the one the
+// request being reported went through. Register it before retryTransient,
+// whose retries run inside the failed attempt's OnError.
+func (s *Scraper) useProxy(c *colly.Collector, source models.Source) error {
+    if source.ProxyPool == "" {
+        return nil
+    }
+    pool, err := s.proxies.Pool(source.ProxyPool)
+    if err != nil {
+        return err
+    }
+
+    var mu sync.Mutex
+    var last string
+    c.WithTransport(http.DefaultTransport.(*http.Transport).Clone())
+    c.SetProxyFunc(func(r *http.Request) (*url.URL, error) {
+        u, err := pool.Pick(r.URL.Hostname())
+        if err != nil {
+            return nil, err
+        }
+        mu.Lock()
+        last = u.String()
+        mu.Unlock()
+        return u, nil
+    })
+    picked := func() string {
+        mu.Lock()
+        defer mu.Unlock()
+        return last
+    }
+
+    c.OnResponse(func(r *colly.Response) {
+        if u := picked(); u != "" {
+            pool.Success(u)
+        }
+    })
+    c.OnError(func(r *colly.Response, err error) {
+        u := picked()
+        if u == "" || errors.Is(err, proxy.ErrNoHealthyProxy) {
+            return
+        }
+        if r.StatusCode == 0 || r.StatusCode == http.StatusProxyAuthRequired {
+            pool.Failure(u, err)
+        } else {
+            pool.Success(u)
+        }
+    })
+    return nil
+}
+
+type robotsClientKey struct {
+    pool    string
+    timeout time.Duration
+}
+
+// robotsClient returns the client robots.txt is fetched with for source,
+// so that it goes out through the source's proxy pool like its pages do.
+// It is nil, the robots checker's own direct client, for sources without
+// a pool. Clients are kept per pool and timeout, to reuse connections.
+func (s *Scraper) robotsClient(source models.Source) (*http.Client, error) {
+    if source.ProxyPool == "" {
+        return nil, nil
+    }
+    key := robotsClientKey{pool: source.ProxyPool, timeout: s.requestTimeout(source)}
+
+    s.robotsMu.Lock()
+    defer s.robotsMu.Unlock()
+
+    if client, ok := s.robotsClients[key]; ok {
+        return client, nil
+    }
+    pool, err := s.proxies.Pool(source.ProxyPool)
+    if err != nil {
+        return nil, err
+    }
+
+    transport := http.DefaultTransport.(*http.Transport).Clone()
+    transport.Proxy = func(r *http.Request) (*url.URL, error) {
+        return pool.Pick(r.URL.Hostname())
+    }
+    client := &http.Client{Transport: transport, Timeout: key.timeout}
+    if s.robotsClients == nil {
+        s.robotsClients = make(map[robotsClientKey]*http.Client)
+    }
+    s.robotsClients[key] = client
+    return client, nil
+}
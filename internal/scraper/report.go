@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"news-scraper/internal/circuit"
+	"news-scraper/internal/models"
+	"news-scraper/internal/proxy"
+)
+
+// ErrNoArticles is returned when a listing page matches none of the source's articles
+var ErrNoArticles = errors.New("selector matched no articles")
+
+// Report is the outcome of one ScrapeAll, with an entry per active source
+// Sources cancelled before they started are listed as cancelled.
+type Report struct {
+    StartedAt       time.Time      `json:"started_at"`
+    FinishedAt      time.Time      `json:"finished_at"`
+    DurationSeconds float64        `json:"duration_seconds"`
+    Sources         []SourceReport `json:"sources"`
+}
+
+// SourceReport is the run of one source, as stored in the run history
+type SourceReport struct {
+    models.ScrapeRun
+    DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// Count returns how many sources ended with the given run status
+func (r *Report) Count(status string) int {
+    n := 0
+    for _, s := range r.Sources {
+        if s.Status == status {
+            n++
+        }
+    }
+    return n
+}
+
+// Log writes a summary line and one line per failed source
+func (r *Report) Log() {
+    log.Printf("Scraping completed in %.1fs: %d ok, %d unchanged, %d failed, %d timed out, %d skipped, %d cancelled",
+        r.DurationSeconds, r.Count(models.RunOK), r.Count(models.RunUnchanged), r.Count(models.RunFailed),
+        r.Count(models.RunTimeout), r.Count(models.RunSkipped), r.Count(models.RunCancelled))
+    for _, s := range r.Sources {
+        if s.Error != "" {
+            log.Printf("  %s: %s (%s)", s.SourceName, s.Status, s.ErrorKind)
+        }
+    }
+}
+
+// storeError marks a failure of the repository rather than of the site
+type storeError struct {
+    err error
+}
+
+func (e *storeError) Error() string { return e.err.Error() }
+func (e *storeError) Unwrap() error { return e.err }
+
+// classifyError returns the models.ErrorKind* class of a run's error
+func classifyError(err error) string {
+    var fetchErr *FetchError
+    var dbErr *storeError
+    switch {
+    case err == nil:
+        return ""
+    case errors.Is(err, context.Canceled):
+        return models.ErrorKindCancelled
+    case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+        return models.ErrorKindTimeout
+    case errors.Is(err, circuit.ErrOpen):
+        return models.ErrorKindCircuit
+    case errors.Is(err, proxy.ErrNoHealthyProxy):
+        return models.ErrorKindProxy
+    case errors.Is(err, ErrBlockedByRobots):
+        return models.ErrorKindRobots
+    case errors.Is(err, ErrNoArticles):
+        return models.ErrorKindSelector
+    case errors.As(err, &dbErr):
+        return models.ErrorKindDatabase
+    case errors.As(err, &fetchErr):
+        if fetchErr.StatusCode != 0 {
+            return models.ErrorKindHTTP
+        }
+        return models.ErrorKindNetwork
+    default:
+        return models.ErrorKindOther
+    }
+}
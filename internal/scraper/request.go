@@ -0,0 +1,50 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"news-scraper/internal/models"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// customize applies the source's request settings to c: its user agent
+// and timeout override the scraper's, its headers go out with every
+// request and its cookies are seeded into the collector's jar for the
+// source's site (a consent cookie, say, to get past a GDPR wall).
+func (s *Scraper) customize(c *colly.Collector, source models.Source) error {
+    if source.UserAgent != "" {
+        c.UserAgent = source.UserAgent
+    }
+
+    c.SetRequestTimeout(s.requestTimeout(source))
+
+    if len(source.Headers) > 0 {
+        c.OnRequest(func(r *colly.Request) {
+            for name, value := range source.Headers {
+                r.Headers.Set(name, value)
+            }
+        })
+    }
+
+    if source.Cookies != "" {
+        cookies, err := http.ParseCookie(source.Cookies)
+        if err != nil {
+            return fmt.Errorf("invalid cookies for %s: %w", source.Name, err)
+        }
+        if err := c.SetCookies(source.URL, cookies); err != nil {
+            return fmt.Errorf("failed to seed cookies for %s: %w", source.Name, err)
+        }
+    }
+    return nil
+}
+
+// requestTimeout is the timeout of one request to the source
+func (s *Scraper) requestTimeout(source models.Source) time.Duration {
+    if source.TimeoutSeconds > 0 {
+        return time.Duration(source.TimeoutSeconds) * time.Second
+    }
+    return s.timeout
+}
@@ -0,0 +1,179 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"news-scraper/internal/proxy"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// maxRetryAfter is the longest Retry-After a fetch waits out; a site
+// asking for more is left to the next run (or the job queue's backoff)
+const maxRetryAfter = 2 * time.Minute
+
+// Keys of the colly context that carry retry state between attempts
+const (
+    retriesKey = "fetch_retries"
+    outcomeKey = "fetch_outcome"
+)
+
+// FetchError is a page fetch that failed after its last attempt
+// StatusCode is 0 when no response was received (timeout, refused connection, DNS).
+// The message leaves out URL, which callers already name when wrapping it.
+type FetchError struct {
+    URL        string
+    StatusCode int
+    Err        error
+}
+
+func (e *FetchError) Error() string {
+    if e.StatusCode != 0 {
+        return fmt.Sprintf("HTTP %d: %v", e.StatusCode, e.Err)
+    }
+    return e.Err.Error()
+}
+
+func (e *FetchError) Unwrap() error {
+    return e.Err
+}
+
+// Transient reports whether the failure may go away on its own: no
+// response at all, 408, 429 or a 5xx. Only these are retried and only
+// these count against a source's circuit breaker.
+func (e *FetchError) Transient() bool {
+    switch {
+    case e.StatusCode == 0:
+        return true
+    case e.StatusCode == http.StatusRequestTimeout, e.StatusCode == http.StatusTooManyRequests:
+        return true
+    default:
+        return e.StatusCode >= 500
+    }
+}
+
+// isTransient reports whether err is a transient FetchError
+func isTransient(err error) bool {
+    var fetchErr *FetchError
+    return errors.As(err, &fetchErr) && fetchErr.Transient()
+}
+
+// fetchOutcome is the result of a request's last attempt
+type fetchOutcome struct {
+    err error
+}
+
+// retryTransient makes c retry idempotent requests that fail transiently,
+// up to s.maxRetries times with jittered exponential backoff, waiting
+// for the server's Retry-After instead when it sends one.
+// Register it before other OnError callbacks, and fetch with s.visit to
+// get the outcome of the last attempt rather than the first.
+func (s *Scraper) retryTransient(ctx context.Context, c *colly.Collector) {
+    c.OnError(func(r *colly.Response, err error) {
+        req := r.Request
+        if errors.Is(err, proxy.ErrNoHealthyProxy) {
+            // The request never left: the proxy pool, not the site, failed
+            var urlErr *url.Error
+            if errors.As(err, &urlErr) {
+                err = urlErr.Err
+            }
+            setOutcome(req.Ctx, err)
+            return
+        }
+        fetchErr := &FetchError{URL: req.URL.String(), StatusCode: r.StatusCode, Err: err}
+
+        attempt, _ := req.Ctx.GetAny(retriesKey).(int)
+        idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+        if !idempotent || !fetchErr.Transient() || attempt >= s.maxRetries || ctx.Err() != nil {
+            setOutcome(req.Ctx, fetchErr)
+            return
+        }
+
+        delay, ok := retryDelay(attempt+1, s.retryBackoff, r.Headers)
+        if !ok {
+            setOutcome(req.Ctx, fetchErr)
+            return
+        }
+        log.Printf("Retrying %s in %s (retry %d/%d): %v", req.URL, delay.Round(time.Millisecond), attempt+1, s.maxRetries, fetchErr)
+
+        select {
+        case <-ctx.Done():
+            setOutcome(req.Ctx, fetchErr)
+            return
+        case <-time.After(delay):
+        }
+
+        req.Ctx.Put(retriesKey, attempt+1)
+        if err := req.Retry(); err != nil {
+            setOutcome(req.Ctx, &FetchError{URL: fetchErr.URL, Err: err})
+            return
+        }
+        if ctx.Err() != nil {
+            // The retry was aborted before it went out
+            setOutcome(req.Ctx, fetchErr)
+            return
+        }
+        setOutcome(req.Ctx, nil)
+    })
+}
+
+// setOutcome records the result of a request's last attempt
+// Retries nest inside the failed attempt's OnError, so the innermost,
+// which is the last attempt, records first and wins.
+func setOutcome(ctx *colly.Context, err error) {
+    if _, done := ctx.GetAny(outcomeKey).(fetchOutcome); !done {
+        ctx.Put(outcomeKey, fetchOutcome{err: err})
+    }
+}
+
+// visit fetches rawURL with c and returns the error of its last attempt,
+// a *FetchError when the fetch itself failed, or proxy.ErrNoHealthyProxy
+// when the source's pool had no proxy to send it through
+func (s *Scraper) visit(c *colly.Collector, rawURL string) error {
+    ctx := colly.NewContext()
+    err := c.Request(http.MethodGet, rawURL, nil, ctx, nil)
+    if outcome, ok := ctx.GetAny(outcomeKey).(fetchOutcome); ok {
+        return outcome.err
+    }
+    return err
+}
+
+// retryDelay returns how long to wait before the given retry
+// A Retry-After header (seconds or HTTP date) wins; ok is false when it
+// asks for more than maxRetryAfter. Otherwise base doubles per retry, up
+// to 30s, with up to 20% jitter.
+func retryDelay(retry int, base time.Duration, headers *http.Header) (time.Duration, bool) {
+    if headers != nil {
+        if value := strings.TrimSpace(headers.Get("Retry-After")); value != "" {
+            var wait time.Duration
+            if secs, err := strconv.Atoi(value); err == nil {
+                wait = time.Duration(secs) * time.Second
+            } else if t, err := http.ParseTime(value); err == nil {
+                wait = time.Until(t)
+            }
+            if wait > maxRetryAfter {
+                return 0, false
+            }
+            if wait > 0 {
+                return wait, true
+            }
+        }
+    }
+
+    delay := base
+    for i := 1; i < retry && delay < 30*time.Second; i++ {
+        delay *= 2
+    }
+    delay = min(delay, 30*time.Second)
+    jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+    return delay + jitter, true
+}
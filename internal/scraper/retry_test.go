@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"news-scraper/internal/circuit"
+	"news-scraper/internal/models"
+)
+
+func TestRetryDelay(t *testing.T) {
+    tests := []struct {
+        name       string
+        retry      int
+        retryAfter string // "" sends no header
+        min, max   time.Duration
+        wantOK     bool
+    }{
+        {"first retry", 1, "", time.Second, 1200 * time.Millisecond, true},
+        {"doubles per retry", 3, "", 4 * time.Second, 4800 * time.Millisecond, true},
+        {"capped at 30s", 10, "", 30 * time.Second, 36 * time.Second, true},
+        {"retry-after seconds", 1, "5", 5 * time.Second, 5 * time.Second, true},
+        {"retry-after trimmed", 3, " 7 ", 7 * time.Second, 7 * time.Second, true},
+        {"retry-after date", 1, http.TimeFormat, 8 * time.Second, 10 * time.Second, true},
+        {"retry-after at the limit", 1, "120", maxRetryAfter, maxRetryAfter, true},
+        {"retry-after too long", 1, "121", 0, 0, false},
+        {"retry-after zero uses backoff", 2, "0", 2 * time.Second, 2400 * time.Millisecond, true},
+        {"retry-after in the past uses backoff", 1, "Sun, 06 Nov 1994 08:49:37 GMT", time.Second, 1200 * time.Millisecond, true},
+        {"unparsable retry-after uses backoff", 1, "soon", time.Second, 1200 * time.Millisecond, true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var headers *http.Header
+            if tt.retryAfter != "" {
+                value := tt.retryAfter
+                if value == http.TimeFormat {
+                    // Whole seconds, so the delay lands just under 10s
+                    value = time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+                }
+                headers = &http.Header{}
+                headers.Set("Retry-After", value)
+            }
+
+            got, ok := retryDelay(tt.retry, time.Second, headers)
+            if ok != tt.wantOK {
+                t.Fatalf("retryDelay() ok = %v, want %v", ok, tt.wantOK)
+            }
+            if ok && (got < tt.min || got > tt.max) {
+                t.Errorf("retryDelay() = %s, want between %s and %s", got, tt.min, tt.max)
+            }
+        })
+    }
+}
+
+func TestFetchErrorTransient(t *testing.T) {
+    tests := []struct {
+        status int
+        want   bool
+    }{
+        {0, true}, // no response
+        {http.StatusRequestTimeout, true},
+        {http.StatusTooManyRequests, true},
+        {http.StatusInternalServerError, true},
+        {http.StatusServiceUnavailable, true},
+        {http.StatusBadRequest, false},
+        {http.StatusForbidden, false},
+        {http.StatusNotFound, false},
+    }
+
+    for _, tt := range tests {
+        if got := (&FetchError{StatusCode: tt.status}).Transient(); got != tt.want {
+            t.Errorf("Transient() of status %d = %v, want %v", tt.status, got, tt.want)
+        }
+    }
+}
+
+func TestRetryTransient(t *testing.T) {
+    tests := []struct {
+        name         string
+        status       int // of the listing page's first failures
+        failures     int
+        retryAfter   string
+        wantRequests int32
+        wantStatus   string
+        wantKind     string
+    }{
+        {"recovers after two 503s", http.StatusServiceUnavailable, 2, "", 3, models.RunOK, ""},
+        {"429 waits out Retry-After", http.StatusTooManyRequests, 1, "1", 2, models.RunOK, ""},
+        {"gives up after the last retry", http.StatusServiceUnavailable, 10, "", 4, models.RunFailed, models.ErrorKindHTTP},
+        {"404 is not retried", http.StatusNotFound, 10, "", 1, models.RunFailed, models.ErrorKindHTTP},
+        {"Retry-After too long is not waited", http.StatusServiceUnavailable, 10, "3600", 1, models.RunFailed, models.ErrorKindHTTP},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var requests atomic.Int32
+            mux := http.NewServeMux()
+            mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+                if requests.Add(1) <= int32(tt.failures) {
+                    if tt.retryAfter != "" {
+                        w.Header().Set("Retry-After", tt.retryAfter)
+                    }
+                    w.WriteHeader(tt.status)
+                    return
+                }
+                fmt.Fprint(w, `<html><body><h2><a href="/news/1">A headline about the election</a></h2></body></html>`)
+            })
+            mux.HandleFunc("/news/", func(w http.ResponseWriter, r *http.Request) {
+                fmt.Fprint(w, `<html><body><article><p>The story.</p></article></body></html>`)
+            })
+            site := httptest.NewServer(mux)
+            t.Cleanup(site.Close)
+
+            s, _, _ := newTestScraper(t, site, circuit.Config{})
+            report, _ := s.ScrapeAll(context.Background())
+
+            if got := requests.Load(); got != tt.wantRequests {
+                t.Errorf("listing fetched %d times, want %d", got, tt.wantRequests)
+            }
+            if run := report.Sources[0]; run.Status != tt.wantStatus || run.ErrorKind != tt.wantKind {
+                t.Errorf("got run %s/%s (%s), want %s/%s", run.Status, run.ErrorKind, run.Error, tt.wantStatus, tt.wantKind)
+            }
+        })
+    }
+}
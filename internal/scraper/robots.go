@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"news-scraper/internal/models"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// ErrBlockedByRobots is returned when robots.txt disallows a page we were asked to fetch
+var ErrBlockedByRobots = errors.New("blocked by robots.txt")
+
+// fetchPolitely registers what every request does before it is sent:
+// URLs the site's robots.txt (fetched the way the source's pages are, see
+// robotsClient) disallows for the source's user agent are skipped and
+// passed to blocked (which may be nil), and the rest wait their turn on
+// the host's rate limit, slowed to its Crawl-delay. Sources with
+// ignore_robots set skip robots.txt but are still rate limited.
+// Register it before other OnRequest callbacks.
+func (s *Scraper) fetchPolitely(ctx context.Context, c *colly.Collector, source models.Source, blocked func(string)) {
+    c.OnRequest(func(r *colly.Request) {
+        var delay time.Duration
+        if !source.IgnoreRobots {
+            client, err := s.robotsClient(source)
+            if err != nil {
+                setOutcome(r.Ctx, err)
+                r.Abort()
+                return
+            }
+            var allowed bool
+            allowed, delay = s.robots.Check(ctx, r.URL, source.UserAgent, client)
+            if !allowed {
+                log.Printf("Skipping %s: %v", r.URL, ErrBlockedByRobots)
+                if blocked != nil {
+                    blocked(r.URL.String())
+                }
+                r.Abort()
+                return
+            }
+        }
+        if err := s.limiter.Wait(ctx, r.URL.Hostname(), delay); err != nil {
+            // Cancelled: report it from s.visit, which an aborted request would not
+            setOutcome(r.Ctx, err)
+            r.Abort()
+        }
+    })
+}
+
+// robotsAllow reports whether robots.txt lets us fetch rawURL for source
+// Used to check a page before it is visited or queued, so it can be
+// reported instead of silently skipped.
+func (s *Scraper) robotsAllow(ctx context.Context, source models.Source, rawURL string) bool {
+    if source.IgnoreRobots {
+        return true
+    }
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        // Let the fetch report the bad URL
+        return true
+    }
+    client, err := s.robotsClient(source)
+    if err != nil {
+        // or the bad proxy pool
+        return true
+    }
+    allowed, _ := s.robots.Check(ctx, u, source.UserAgent, client)
+    return allowed
+}
@@ -2,48 +2,182 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"strings"
 
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	// "github.com/PuerkitoBio/goquery"
+	"news-scraper/internal/circuit"
 	"news-scraper/internal/database"
 	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+	"news-scraper/internal/proxy"
+	"news-scraper/internal/ratelimit"
+	"news-scraper/internal/robots"
 
 	"github.com/gocolly/colly/v2"
 )
 
 // Scraper coordinates the scraping process
 type Scraper struct {
-    repo        *database.Repository  // Database access for saving articles
+    repo        database.Repository  // Database access for saving articles
     userAgent   string                // User-Agent header value
     workers     int                   // Number of concurrent workers
     timeout     time.Duration
-    rateLimit   int
+    maxJobAttempts int                // Attempts per queued article fetch before dead-lettering
+    robots      *robots.Checker       // robots.txt cache shared by every collector
+    limiter     *ratelimit.Limiter    // Per-host request budget shared by every collector
+    maxRetries   int                  // Retries of a transiently failed fetch
+    retryBackoff time.Duration        // Delay before the first retry, doubled per retry
+    breakers     *circuit.Breakers    // Per-source circuit breakers
+    proxies      *proxy.Manager       // Proxy pools sources can be assigned to
+    sourceTimeout time.Duration       // Deadline of one source's scrape
+
+    // Clients that fetch robots.txt through a proxy pool, by pool and timeout
+    robotsMu      sync.Mutex
+    robotsClients map[robotsClientKey]*http.Client
+
+    // Running scrapes, waited for and cancelled by Shutdown
+    mu       sync.Mutex
+    closed   bool
+    running  sync.WaitGroup
+    stop     context.Context
+    stopAll  context.CancelFunc
 }
 
 // Config holds scraper configuration
 type Config struct {
     Workers     int           // Number of concurrent goroutines
     Timeout     time.Duration // HTTP request timeout
-    RateLimit   int           // Maximum requests per second
+    RateLimit   int           // Maximum requests per second to one host
+    RateLimits  map[string]ratelimit.Rule // Per-domain overrides of RateLimit
     UserAgent   string        // User-Agent string for requests
+    MaxJobAttempts int        // Attempts per queued article fetch (default 5)
+    RobotsAgent    string        // robots.txt user-agent token (default: product token of UserAgent)
+    RobotsCacheTTL time.Duration // How long a robots.txt is cached (default 24h)
+    MaxRetries     int           // Retries of a fetch that failed transiently (default 3)
+    RetryBackoff   time.Duration // Delay before the first retry (default 1s)
+    Breaker        circuit.Config
+    Proxies        *proxy.Manager // nil when no pools are configured
+    SourceTimeout  time.Duration  // Deadline of one source's scrape (default 10m)
 }
 
+// ErrShuttingDown is returned by scrapes started after Shutdown
+var ErrShuttingDown = errors.New("scraper is shutting down")
+
 // NewScraper creates a new scraper instance
-func NewScraper(repo *database.Repository, cfg Config) *Scraper {
+func NewScraper(repo database.Repository, cfg Config) *Scraper {
+    if cfg.MaxJobAttempts <= 0 {
+        cfg.MaxJobAttempts = 5
+    }
+    if cfg.MaxRetries <= 0 {
+        cfg.MaxRetries = 3
+    }
+    if cfg.RetryBackoff <= 0 {
+        cfg.RetryBackoff = time.Second
+    }
+    if cfg.SourceTimeout <= 0 {
+        cfg.SourceTimeout = 10 * time.Minute
+    }
+
+    stop, stopAll := context.WithCancel(context.Background())
     return &Scraper{
         repo:        repo,
         userAgent:   cfg.UserAgent,
         workers:     cfg.Workers,
         timeout:     cfg.Timeout,
-        rateLimit:   cfg.RateLimit,
+        limiter:     ratelimit.New(ratelimit.Rule{Rate: float64(cfg.RateLimit)}, cfg.RateLimits),
+        maxJobAttempts: cfg.MaxJobAttempts,
+        maxRetries:     cfg.MaxRetries,
+        retryBackoff:   cfg.RetryBackoff,
+        breakers:       circuit.New(cfg.Breaker),
+        proxies:        cfg.Proxies,
+        sourceTimeout:  cfg.SourceTimeout,
+        stop:           stop,
+        stopAll:        stopAll,
+        robots: robots.NewChecker(robots.Config{
+            UserAgent: cfg.UserAgent,
+            Agent:     cfg.RobotsAgent,
+            Timeout:   cfg.Timeout,
+            TTL:       cfg.RobotsCacheTTL,
+        }),
     }
 }
 
+// RateLimitStats reports how long requests have waited on each host's rate limit
+func (s *Scraper) RateLimitStats() []ratelimit.HostStats {
+    return s.limiter.Stats()
+}
+
+// CircuitStatus returns the state of a source's circuit breaker
+func (s *Scraper) CircuitStatus(sourceID int) circuit.Status {
+    return s.breakers.Status(sourceID)
+}
+
+// ProxyStats reports the health of every configured proxy
+func (s *Scraper) ProxyStats() []proxy.ProxyStats {
+    return s.proxies.Stats()
+}
+
+// Shutdown stops new scrapes from starting and waits for running ones to
+// finish. If ctx expires first they are cancelled: requests in flight are
+// aborted and no further sources are started, but what a scrape fetched
+// before is still saved.
+func (s *Scraper) Shutdown(ctx context.Context) error {
+    s.mu.Lock()
+    s.closed = true
+    s.mu.Unlock()
+
+    done := make(chan struct{})
+    go func() {
+        s.running.Wait()
+        close(done)
+    }()
+
+    select {
+    case <-done:
+        return nil
+    case <-ctx.Done():
+    }
+    log.Println("Cancelling running scrapes")
+    s.stopAll()
+    <-done
+    return ctx.Err()
+}
+
+// track registers a scrape with Shutdown and returns its context, which
+// Shutdown cancels; call release when the scrape is done
+func (s *Scraper) track(ctx context.Context) (context.Context, func(), error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.closed {
+        return nil, nil, ErrShuttingDown
+    }
+    s.running.Add(1)
+
+    ctx, cancel := context.WithCancel(ctx)
+    stop := context.AfterFunc(s.stop, cancel)
+    return ctx, func() {
+        stop()
+        cancel()
+        s.running.Done()
+    }, nil
+}
+
+// sourceDeadline returns how long one scrape of the source may take
+func (s *Scraper) sourceDeadline(source models.Source) time.Duration {
+    if source.DeadlineSeconds > 0 {
+        return time.Duration(source.DeadlineSeconds) * time.Second
+    }
+    return s.sourceTimeout
+}
+
 // ScrapeAll scrapes all active sources concurrently using a worker pool
 // WORKFLOW:
 // 1. Fetch active sources from database
@@ -52,23 +186,32 @@ func NewScraper(repo *database.Repository, cfg Config) *Scraper {
 // 4. Distribute sources to workers via channel
 // 5. Wait for all workers to complete
 // 6. Collect and return results
-func (s *Scraper) ScrapeAll(ctx context.Context) error {
+// The report has the run of every source; the error joins those of the
+// sources that failed, each prefixed with the source's name.
+func (s *Scraper) ScrapeAll(ctx context.Context) (*Report, error) {
+    report := &Report{StartedAt: time.Now().UTC()}
+    ctx, release, err := s.track(ctx)
+    if err != nil {
+        return nil, err
+    }
+    defer release()
+
     // STEP 1: Get all active sources from database
     sources, err := s.repo.GetActiveSources(ctx)
     if err != nil {
-        return fmt.Errorf("failed to get sources: %w", err)
+        return nil, fmt.Errorf("failed to get sources: %w", err)
     }
 
     log.Printf("Starting scraping for %d sources with %d workers", len(sources), s.workers)
 
     // STEP 2: Create channels for work distribution
-    // Jobs channel: Sources to be scraped
-    // Buffered size = number of sources (so we can send all without blocking)
-    jobs := make(chan models.Source, len(sources))
+    // Jobs channel: Indexes of the sources to be scraped
+    // Unbuffered, so a cancelled scrape stops handing out sources at once
+    jobs := make(chan int)
 
-    // Results channel: Collects errors from workers
-    // Buffered size = number of sources (one result per source)
-    results := make(chan error, len(sources))
+    // Results: each worker fills in the entries of the sources it scraped
+    report.Sources = make([]SourceReport, len(sources))
+    errs := make([]error, len(sources))
 
     // STEP 3: Start worker pool
     // WaitGroup tracks how many workers are still running
@@ -82,56 +225,144 @@ func (s *Scraper) ScrapeAll(ctx context.Context) error {
             defer wg.Done() // Decrement counter when worker exits
 
             // Worker loop: process sources until channel is closed
-            for source := range jobs {
+            for i := range jobs {
+                source := sources[i]
                 log.Printf("Worker %d: scraping %s", workerID, source.Name)
 
-                // Scrape this source
-                if err := s.scrapeSourceWithColly(ctx, source); err != nil {
+                // Scrape this source within its deadline and record the run in the history
+                run := &models.ScrapeRun{SourceID: source.ID, SourceName: source.Name, StartedAt: time.Now().UTC()}
+                err := s.breakers.Allow(source.ID)
+                if err == nil {
+                    sourceCtx, cancel := context.WithTimeout(ctx, s.sourceDeadline(source))
+                    err = s.scrapeSourceWithColly(sourceCtx, source, run)
+                    s.recordFetch(sourceCtx, source.ID, err)
+                    cancel()
+                }
+                s.recordRun(ctx, run, err)
+                if err != nil {
                     log.Printf("Worker %d: error scraping %s: %v", workerID, source.Name, err)
-                    results <- err // Send error to results channel
-                } else {
-                    results <- nil // Send nil to indicate success
+                    errs[i] = fmt.Errorf("%s: %w", source.Name, err)
                 }
+                report.Sources[i] = newSourceReport(run)
             }
         }(i)
     }
 
     // STEP 4: Send all sources to workers
-    // Workers will pick them up from the channel
-    for _, source := range sources {
-        jobs <- source
+    // Workers will pick them up from the channel, until ctx is cancelled
+    dispatched := 0
+dispatch:
+    for i := range sources {
+        select {
+        case jobs <- i:
+            dispatched++
+        case <-ctx.Done():
+            break dispatch
+        }
     }
     close(jobs)  // Signal that no more jobs are coming
 
      // STEP 5: Wait for all workers to finish
     wg.Wait()
 
-    // STEP 6: Close results channel (safe now that all workers are done)
-    close(results)
+    // STEP 6: Record the sources a cancellation kept from starting
+    if dispatched < len(sources) {
+        log.Printf("Scraping cancelled, %d sources not started: %v", len(sources)-dispatched, ctx.Err())
+    }
+    for i := dispatched; i < len(sources); i++ {
+        now := time.Now().UTC()
+        run := &models.ScrapeRun{SourceID: sources[i].ID, SourceName: sources[i].Name, StartedAt: now}
+        err := fmt.Errorf("not started: %w", ctx.Err())
+        s.recordRun(ctx, run, err)
+        errs[i] = fmt.Errorf("%s: %w", sources[i].Name, err)
+        report.Sources[i] = newSourceReport(run)
+    }
+
+    // STEP 7: Summarize and join the errors
+    report.FinishedAt = time.Now().UTC()
+    report.DurationSeconds = report.FinishedAt.Sub(report.StartedAt).Seconds()
+    report.Log()
+
+    return report, errors.Join(errs...)
+}
+
+// newSourceReport is the report entry of a recorded run
+func newSourceReport(run *models.ScrapeRun) SourceReport {
+    return SourceReport{ScrapeRun: *run, DurationSeconds: run.FinishedAt.Sub(run.StartedAt).Seconds()}
+}
 
-    // STEP 7: Collect all errors
-    var errors []error
-    for err := range results {
-        if err != nil {
-            errors = append(errors, err)
+// recordRun finishes a run with the outcome of its scrape and stores it
+// A failure to store is only logged: the scrape itself is done. The run
+// is stored even when ctx was cancelled.
+func (s *Scraper) recordRun(ctx context.Context, run *models.ScrapeRun, err error) {
+    ctx = context.WithoutCancel(ctx)
+    run.FinishedAt = time.Now().UTC()
+    if run.Status == "" {
+        run.Status = models.RunOK
+    }
+    run.ErrorKind = classifyError(err)
+    if err != nil {
+        run.Error = err.Error()
+        switch run.ErrorKind {
+        case models.ErrorKindCircuit, models.ErrorKindProxy:
+            run.Status = models.RunSkipped
+        case models.ErrorKindCancelled:
+            run.Status = models.RunCancelled
+        case models.ErrorKindTimeout:
+            run.Status = models.RunTimeout
+        default:
+            run.Status = models.RunFailed
         }
     }
+    if err := s.repo.RecordScrapeRun(ctx, run); err != nil {
+        log.Printf("Failed to record scrape run of %s: %v", run.SourceName, err)
+    }
+}
 
-    if len(errors) > 0 {
-        log.Printf("Scraping completed with %d errors", len(errors))
+// recordFetch reports the outcome of a fetch from a source to its circuit
+// breaker. Only transient failures count: a site that answers with a 404
+// or that robots.txt keeps us out of is up. Fetches cut short by ctx
+// (shutdown or the source's deadline) or by an exhausted proxy pool say
+// nothing about the site, but still release the probe of a half-open circuit.
+func (s *Scraper) recordFetch(ctx context.Context, sourceID int, err error) {
+    if ctx.Err() != nil || errors.Is(err, proxy.ErrNoHealthyProxy) {
+        s.breakers.Abort(sourceID)
+        return
+    }
+    if isTransient(err) {
+        s.breakers.Failure(sourceID, err)
     } else {
-        log.Println("Scraping completed successfully")
+        s.breakers.Success(sourceID)
     }
-
-    return nil
 }
 
 // scrapeSource scrapes a single news source using colly
-func (s *Scraper) scrapeSourceWithColly(ctx context.Context, source models.Source) error {
+// Pages robots.txt disallows are skipped and listed in run.BlockedURLs.
+func (s *Scraper) scrapeSourceWithColly(ctx context.Context, source models.Source, run *models.ScrapeRun) error {
   // Track found articles
     var articles []models.Article
     var mu sync.Mutex // Protect articles slice from concurrent access
 
+    blocked := func(u string) {
+        mu.Lock()
+        run.BlockedURLs = append(run.BlockedURLs, u)
+        mu.Unlock()
+    }
+    if !s.robotsAllow(ctx, source, source.URL) {
+        blocked(source.URL)
+        return fmt.Errorf("%s: %w", source.URL, ErrBlockedByRobots)
+    }
+
+    // Paginated and incremental sources compare the listing with the stored articles
+    var known map[string]bool
+    if maxPages(source) > 1 || source.StopAfterKnown > 0 {
+        var err error
+        if known, err = s.knownURLs(ctx, source); err != nil {
+            return fmt.Errorf("failed to load known articles of %s: %w", source.Name, &storeError{err})
+        }
+    }
+    streak := &knownStreak{limit: source.StopAfterKnown, known: known}
+
     // Create a new Colly collector
     c := colly.NewCollector(
         // Set user agent
@@ -142,17 +373,32 @@ func (s *Scraper) scrapeSourceWithColly(ctx context.Context, source models.Sourc
 
         // Enable async mode for better performance
         colly.Async(false),
+
+        // Abort requests in flight when the scrape is cancelled
+        colly.StdlibContext(ctx),
     )
 
-    // Configure rate limiting (requests per second)
-    c.Limit(&colly.LimitRule{
-        DomainGlob:  "*",
-        Parallelism: 1,
-        Delay:       time.Second / time.Duration(s.rateLimit),
-    })
+    // Set timeout, and the source's own user agent, headers and cookies
+    if err := s.customize(c, source); err != nil {
+        return err
+    }
 
-    // Set timeout
-    c.SetRequestTimeout(s.timeout)
+    // Go through the source's proxy pool, if it has one
+    if err := s.useProxy(c, source); err != nil {
+        return err
+    }
+
+    // Convert pages that declare their charset only in HTML
+    c.OnResponse(fixCharset)
+
+    // Skip pages robots.txt disallows and wait for the host's rate limit
+    s.fetchPolitely(ctx, c, source, blocked)
+
+    // Retry timeouts, 429s and 5xx
+    s.retryTransient(ctx, c)
+
+    // Send the listing page's ETag/Last-Modified from the last run
+    pages := s.fetchConditionally(ctx, c, source)
 
     // Before making a request
     c.OnRequest(func(r *colly.Request) {
@@ -193,21 +439,50 @@ func (s *Scraper) scrapeSourceWithColly(ctx context.Context, source models.Sourc
             summaryElem := e.DOM.Closest("article, div").Find(source.SelectorSummary)
             article.Summary = summaryElem.First().Text()
         }
+        normalizeArticle(&article)
+
+        // Detect language (the page's lang attribute helps with short headlines)
+        declared, _ := e.DOM.Closest("[lang]").Attr("lang")
+        article.Language = nlp.DetectLanguage(article.Title+" "+article.Summary, declared)
 
         // ← NEW: Detect and set category
-        article.Category = detectCategory(article.Title, article.Summary, article.URL, source.DefaultCategory)
+        article.Category = detectCategory(article.Title, article.Summary, article.URL, article.Language, source.DefaultCategory)
 
 
-        // Only save if we have both title and URL
+        // Only save if we have both title and URL, and the listing isn't
+        // past the streak of known articles that ends an incremental run
         if article.Title != "" && article.URL != "" {
             mu.Lock()
-            articles = append(articles, article)
+            if !streak.stopped() {
+                streak.add(canonicalURL(source, article.URL))
+                articles = append(articles, article)
+                if streak.stopped() {
+                    log.Printf("%s: %d known articles in a row, stopping", source.Name, streak.count)
+                }
+            }
             mu.Unlock()
         }
     })
 
+    // Remember the page's next-page link, if the source has a selector for it
+    var next string
+    if source.SelectorNext != "" {
+        c.OnHTML(source.SelectorNext, func(e *colly.HTMLElement) {
+            href := e.Attr("href")
+            if href == "" {
+                href, _ = e.DOM.Find("a").Attr("href")
+            }
+            if next == "" && href != "" {
+                next = e.Request.AbsoluteURL(href)
+            }
+        })
+    }
+
     // On error
     c.OnError(func(r *colly.Response, err error) {
+        if r.StatusCode == http.StatusNotModified {
+            return
+        }
         log.Printf("Error scraping %s: %v", r.Request.URL, err)
     })
 
@@ -217,28 +492,90 @@ func (s *Scraper) scrapeSourceWithColly(ctx context.Context, source models.Sourc
     })
 
     // Visit the URL
-    if err := c.Visit(source.URL); err != nil {
+    err := s.visit(c, source.URL)
+    if notModified(err) {
+        log.Printf("%s not modified since the last run", source.URL)
+        run.Status = models.RunUnchanged
+        return nil
+    }
+    if err != nil {
         return fmt.Errorf("failed to visit %s: %w", source.URL, err)
     }
 
     // Wait for all async requests to complete
     c.Wait()
 
-    log.Printf("Found %d articles from %s", len(articles), source.Name)
+    if pages.isUnchanged(source.URL) {
+        log.Printf("%s unchanged since the last run", source.URL)
+        run.Status = models.RunUnchanged
+        pages.commit(context.WithoutCancel(ctx))
+        return nil
+    }
+
+    // Follow the next pages while they still bring articles we don't have
+    if limit := maxPages(source); limit > 1 {
+        visited := map[string]bool{source.URL: true}
+        seen := make(map[string]bool)
+        pageStart := 0
+        for n := 2; n <= limit && !streak.stopped(); n++ {
+            if !hasNewArticles(source, articles[pageStart:], known, seen) {
+                log.Printf("%s: page %d brought no new articles, stopping", source.Name, n-1)
+                break
+            }
+            pageURL := nextPageURL(source, n, next)
+            if pageURL == "" || visited[pageURL] {
+                break
+            }
+            visited[pageURL] = true
+            pageStart, next = len(articles), ""
+
+            // A failed later page ends the crawl; the pages before it still count
+            if err := s.visit(c, pageURL); err != nil {
+                if !notModified(err) {
+                    log.Printf("%s: stopping at page %d: %v", source.Name, n, err)
+                }
+                break
+            }
+            if pages.isUnchanged(pageURL) {
+                break
+            }
+        }
+    }
 
-    // Save articles to database
-    for _, article := range articles {
+    log.Printf("Found %d articles from %s", len(articles), source.Name)
+    run.ArticlesFound = len(articles)
+    if len(articles) == 0 {
+        return fmt.Errorf("%s: %q: %w", source.URL, source.SelectorTitle, ErrNoArticles)
+    }
 
-        dbArticle := &models.Article{
-            SourceID:   source.ID,
-            SourceName: source.Name,
-            Title:      article.Title,
-            URL:        article.URL,
-            Summary:    article.Summary,
-            Category:   article.Category,
+    // Save all articles of the run in one transaction
+    for i := range articles {
+        articles[i].SourceName = source.Name
+    }
+    // What was fetched is saved even when the scrape was cancelled meanwhile
+    ctx = context.WithoutCancel(ctx)
+    saved, err := s.saveScraped(ctx, source, articles)
+    if err != nil {
+        return err
+    }
+    run.ArticlesNew = saved.Inserted
+    run.ArticlesKnown = saved.Updated + saved.Unchanged
+    run.ArticlesUpdated = saved.Updated
+    pages.commit(ctx)
+
+    // Body and metadata are fetched later by the article worker pool,
+    // except for article pages robots.txt disallows and, in incremental
+    // mode, articles stored before
+    for i := range articles {
+        if source.StopAfterKnown > 0 && known[articles[i].CanonicalURL] {
+            continue
+        }
+        if !s.robotsAllow(ctx, source, articles[i].URL) {
+            blocked(articles[i].URL)
+            continue
         }
-        if err := s.repo.SaveArticle(ctx, dbArticle); err != nil {
-            log.Printf("Failed to save article: %v", err)
+        if err := s.enqueueArticleFetch(ctx, &articles[i]); err != nil {
+            log.Printf("Failed to enqueue article fetch: %v", err)
         }
     }
     // fmt.Printf("Scraped articles is %v", articles)
@@ -247,65 +584,91 @@ func (s *Scraper) scrapeSourceWithColly(ctx context.Context, source models.Sourc
 }
 
 //Helper function for category detection
-func detectCategory(title, summary, url string, defaultCategory string) string {
-    text := strings.ToLower(title + " " + summary + " " + url)
-
-    // Technology keywords
-    techKeywords := []string{"tech", "ai", "software", "app", "startup", "code", "programming",
-                             "computer", "gadget", "robot", "crypto", "blockchain"}
-    for _, kw := range techKeywords {
-        if strings.Contains(text, kw) {
-            return "technology"
-        }
+// Keywords are matched against stemmed words, so "matches" finds "match";
+// longer stems also match as prefixes ("tech" finds "technology").
+// The article's own language is checked first, then English, which also
+// covers English URL slugs on other-language pages.
+func detectCategory(title, summary, url, language string, defaultCategory string) string {
+    text := title + " " + summary + " " + url
+
+    languages := []string{nlp.English}
+    if language != "" && language != nlp.English {
+        languages = []string{language, nlp.English}
     }
 
-    // Sports keywords
-    sportsKeywords := []string{"sport", "football", "soccer", "basketball", "tennis", "cricket",
-                               "olympics", "championship", "match", "player", "team", "goal"}
-    for _, kw := range sportsKeywords {
-        if strings.Contains(text, kw) {
-            return "sports"
+    for _, lang := range languages {
+        rules, ok := categoryKeywords[lang]
+        if !ok {
+            continue
         }
-    }
 
-    // Politics keywords
-    politicsKeywords := []string{"politic", "election", "government", "president", "minister",
-                                 "parliament", "vote", "law", "senate", "congress"}
-    for _, kw := range politicsKeywords {
-        if strings.Contains(text, kw) {
-            return "politics"
+        stems := make(map[string]bool)
+        for _, w := range nlp.Words(text) {
+            stems[nlp.Stem(w, lang)] = true
         }
-    }
-
-    // Business keywords
-    businessKeywords := []string{"business", "market", "stock", "economy", "trade", "finance",
-                                 "bank", "investor", "revenue", "profit"}
-    for _, kw := range businessKeywords {
-        if strings.Contains(text, kw) {
-            return "business"
+        for _, rule := range rules {
+            for _, kw := range rule.keywords {
+                if matchesStem(stems, nlp.Stem(kw, lang)) {
+                    return rule.category
+                }
+            }
         }
     }
 
-    // Entertainment keywords
-    entertainmentKeywords := []string{"entertainment", "movie", "music", "celebrity", "film",
-                                     "actor", "actress", "concert", "album", "show"}
-    for _, kw := range entertainmentKeywords {
-        if strings.Contains(text, kw) {
-            return "entertainment"
-        }
-    }
+    // Default to source's default category
+    return defaultCategory
+}
 
-    // Health keywords
-    healthKeywords := []string{"health", "medical", "doctor", "hospital", "disease", "vaccine",
-                               "treatment", "patient", "medicine"}
-    for _, kw := range healthKeywords {
-        if strings.Contains(text, kw) {
-            return "health"
+// matchesStem reports whether any word stem matches a keyword stem
+// Keywords of three characters or fewer must match exactly: "ai" is not "aid".
+func matchesStem(stems map[string]bool, keyword string) bool {
+    if stems[keyword] {
+        return true
+    }
+    if utf8.RuneCountInString(keyword) <= 3 {
+        return false
+    }
+    for stem := range stems {
+        if strings.HasPrefix(stem, keyword) {
+            return true
         }
     }
+    return false
+}
 
-    // Default to source's default category
-    return defaultCategory
+// categoryKeywords lists keywords per language, in the order categories are tried
+var categoryKeywords = map[string][]struct {
+    category string
+    keywords []string
+}{
+    nlp.English: {
+        {"technology", []string{"tech", "ai", "software", "app", "startup", "code", "programming",
+            "computer", "gadget", "robot", "crypto", "blockchain"}},
+        {"sports", []string{"sport", "football", "soccer", "basketball", "tennis", "cricket",
+            "olympics", "championship", "match", "player", "team", "goal"}},
+        {"politics", []string{"politic", "election", "government", "president", "minister",
+            "parliament", "vote", "law", "senate", "congress"}},
+        {"business", []string{"business", "market", "stock", "economy", "trade", "finance",
+            "bank", "investor", "revenue", "profit"}},
+        {"entertainment", []string{"entertainment", "movie", "music", "celebrity", "film",
+            "actor", "actress", "concert", "album", "show"}},
+        {"health", []string{"health", "medical", "doctor", "hospital", "disease", "vaccine",
+            "treatment", "patient", "medicine"}},
+    },
+    nlp.Hindi: {
+        {"technology", []string{"तकनीक", "टेक्नोलॉजी", "एआई", "सॉफ्टवेयर", "ऐप", "स्टार्टअप", "कंप्यूटर",
+            "रोबोट", "क्रिप्टो", "इंटरनेट", "स्मार्टफोन"}},
+        {"sports", []string{"खेल", "क्रिकेट", "फुटबॉल", "टेनिस", "हॉकी", "ओलंपिक", "मैच", "खिलाड़ी",
+            "टीम", "गोल", "चैंपियनशिप"}},
+        {"politics", []string{"राजनीति", "चुनाव", "सरकार", "राष्ट्रपति", "प्रधानमंत्री", "मंत्री", "संसद",
+            "विधानसभा", "वोट", "कानून", "भाजपा", "कांग्रेस"}},
+        {"business", []string{"व्यापार", "कारोबार", "बाजार", "बाज़ार", "शेयर", "अर्थव्यवस्था", "वित्त",
+            "बैंक", "निवेश", "मुनाफा", "कंपनी"}},
+        {"entertainment", []string{"मनोरंजन", "फिल्म", "संगीत", "अभिनेता", "अभिनेत्री", "बॉलीवुड",
+            "गाना", "सीरीज"}},
+        {"health", []string{"स्वास्थ्य", "डॉक्टर", "अस्पताल", "बीमारी", "टीका", "वैक्सीन", "इलाज",
+            "मरीज", "दवा"}},
+    },
 }
 
 // extractDomain extracts domain from URL for Colly's AllowedDomains
@@ -0,0 +1,334 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"news-scraper/internal/circuit"
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/proxy"
+)
+
+// newTestSite serves a listing page at / and an article page under /news/
+func newTestSite(t *testing.T) *httptest.Server {
+    t.Helper()
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+        fmt.Fprint(w, `<html><body>
+            <h2><a href="/news/1?utm_source=home">First headline about the election</a></h2>
+            <h2><a href="/news/2">Second headline about the match</a></h2>
+        </body></html>`)
+    })
+    mux.HandleFunc("/news/", func(w http.ResponseWriter, r *http.Request) {
+        fmt.Fprint(w, `<html><head><meta name="description" content="What happened."></head>
+            <body><article><p>The full story of what happened today.</p></article></body></html>`)
+    })
+    site := httptest.NewServer(mux)
+    t.Cleanup(site.Close)
+    return site
+}
+
+// newTestScraper returns a scraper on an in-memory repository holding one source for site
+func newTestScraper(t *testing.T, site *httptest.Server, breaker circuit.Config) (*Scraper, *database.MemoryRepository, models.Source) {
+    t.Helper()
+
+    repo := database.NewMemoryRepository()
+    source := models.Source{Name: "Example", URL: site.URL + "/", SelectorTitle: "h2", IgnoreRobots: true, Active: true}
+    if err := repo.CreateSource(context.Background(), &source); err != nil {
+        t.Fatal(err)
+    }
+
+    s := NewScraper(repo, Config{
+        Workers:      1,
+        Timeout:      5 * time.Second,
+        RateLimit:    100,
+        UserAgent:    "NewsBot/1.0",
+        RetryBackoff: time.Millisecond,
+        Breaker:      breaker,
+    })
+    return s, repo, source
+}
+
+func TestScrapeAll(t *testing.T) {
+    site := newTestSite(t)
+    s, repo, _ := newTestScraper(t, site, circuit.Config{})
+    ctx := context.Background()
+
+    report, err := s.ScrapeAll(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(report.Sources) != 1 || report.Sources[0].Status != models.RunOK || report.Sources[0].ArticlesNew != 2 {
+        t.Fatalf("got report %+v, want one ok run with 2 new articles", report.Sources)
+    }
+
+    // The publisher's URL is kept; only the key is canonical
+    articles, err := repo.GetArticleURLs(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]string{
+        site.URL + "/news/1?utm_source=home": "https://" + site.Listener.Addr().String() + "/news/1",
+        site.URL + "/news/2":                 "https://" + site.Listener.Addr().String() + "/news/2",
+    }
+    if len(articles) != len(want) {
+        t.Fatalf("got %d articles, want %d", len(articles), len(want))
+    }
+    for _, a := range articles {
+        if key, ok := want[a.URL]; !ok || a.CanonicalURL != key {
+            t.Errorf("got URL %s keyed %s, want one of %v", a.URL, a.CanonicalURL, want)
+        }
+    }
+
+    // An unmodified listing ends the next run early
+    report, err = s.ScrapeAll(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if report.Sources[0].Status != models.RunUnchanged {
+        t.Errorf("second run: got status %s, want %s", report.Sources[0].Status, models.RunUnchanged)
+    }
+}
+
+func TestScrapeAllSkipsOpenCircuit(t *testing.T) {
+    site := newTestSite(t)
+    s, _, source := newTestScraper(t, site, circuit.Config{Threshold: 1, Cooldown: time.Hour})
+    s.breakers.Failure(source.ID, fmt.Errorf("HTTP 503"))
+
+    report, err := s.ScrapeAll(context.Background())
+    if err == nil {
+        t.Fatal("error of the skipped source not returned")
+    }
+    if run := report.Sources[0]; run.Status != models.RunSkipped || run.ErrorKind != models.ErrorKindCircuit {
+        t.Errorf("got run %+v, want skipped with kind %s", run, models.ErrorKindCircuit)
+    }
+}
+
+func TestScrapeAllWithoutHealthyProxy(t *testing.T) {
+    site := newTestSite(t)
+    s, repo, source := newTestScraper(t, site, circuit.Config{Threshold: 1, Cooldown: time.Hour})
+    ctx := context.Background()
+
+    const proxyURL = "http://127.0.0.1:1"
+    proxies, err := proxy.New(proxy.Config{Pools: map[string]proxy.PoolConfig{"test": {URLs: []string{proxyURL}}}, MaxFailures: 1})
+    if err != nil {
+        t.Fatal(err)
+    }
+    pool, _ := proxies.Pool("test")
+    pool.Failure(proxyURL, errors.New("connection refused"))
+    s.proxies = proxies
+
+    if err := repo.DeleteSource(ctx, source.ID); err != nil {
+        t.Fatal(err)
+    }
+    source.ID, source.ProxyPool = 0, "test"
+    if err := repo.CreateSource(ctx, &source); err != nil {
+        t.Fatal(err)
+    }
+
+    report, err := s.ScrapeAll(ctx)
+    if !errors.Is(err, proxy.ErrNoHealthyProxy) {
+        t.Fatalf("got %v, want ErrNoHealthyProxy", err)
+    }
+    if run := report.Sources[0]; run.Status != models.RunSkipped || run.ErrorKind != models.ErrorKindProxy {
+        t.Errorf("got run %+v, want skipped with kind %s", run, models.ErrorKindProxy)
+    }
+    if status := s.CircuitStatus(source.ID); status.State != circuit.Closed || status.Failures != 0 {
+        t.Errorf("circuit %+v, want closed without failures", status)
+    }
+}
+
+func TestScrapeThroughProxy(t *testing.T) {
+    site := newTestSite(t)
+    s, repo, source := newTestScraper(t, site, circuit.Config{})
+    ctx := context.Background()
+
+    // A forward proxy that records what went through it
+    var mu sync.Mutex
+    seen := make(map[string]string) // path -> User-Agent
+    proxySite := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        seen[r.URL.Path] = r.Header.Get("User-Agent")
+        mu.Unlock()
+
+        out := r.Clone(r.Context())
+        out.RequestURI = ""
+        resp, err := http.DefaultTransport.RoundTrip(out)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusBadGateway)
+            return
+        }
+        defer resp.Body.Close()
+        w.WriteHeader(resp.StatusCode)
+        io.Copy(w, resp.Body)
+    }))
+    t.Cleanup(proxySite.Close)
+
+    proxies, err := proxy.New(proxy.Config{Pools: map[string]proxy.PoolConfig{"test": {URLs: []string{proxySite.URL}}}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    s.proxies = proxies
+
+    if err := repo.DeleteSource(ctx, source.ID); err != nil {
+        t.Fatal(err)
+    }
+    source.ID, source.ProxyPool, source.IgnoreRobots, source.UserAgent = 0, "test", false, "SourceBot/2.0"
+    if err := repo.CreateSource(ctx, &source); err != nil {
+        t.Fatal(err)
+    }
+
+    report, err := s.ScrapeAll(ctx)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if report.Sources[0].Status != models.RunOK {
+        t.Errorf("got run %+v, want ok", report.Sources[0])
+    }
+
+    mu.Lock()
+    defer mu.Unlock()
+    for _, path := range []string{"/robots.txt", "/"} {
+        if ua, ok := seen[path]; !ok || ua != source.UserAgent {
+            t.Errorf("%s through the proxy as %q (seen %v), want %q", path, ua, ok, source.UserAgent)
+        }
+    }
+}
+
+func TestScrapeAllDeadline(t *testing.T) {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+        select {
+        case <-r.Context().Done():
+        case <-time.After(5 * time.Second):
+        }
+    })
+    site := httptest.NewServer(mux)
+    t.Cleanup(site.Close)
+
+    s, _, _ := newTestScraper(t, site, circuit.Config{Threshold: 1})
+    s.sourceTimeout = 50 * time.Millisecond
+
+    report, err := s.ScrapeAll(context.Background())
+    if !errors.Is(err, context.DeadlineExceeded) {
+        t.Fatalf("got %v, want the source's deadline", err)
+    }
+    if run := report.Sources[0]; run.Status != models.RunTimeout || run.ErrorKind != models.ErrorKindTimeout {
+        t.Errorf("got run %s/%s, want %s/%s", run.Status, run.ErrorKind, models.RunTimeout, models.ErrorKindTimeout)
+    }
+    if status := s.CircuitStatus(report.Sources[0].SourceID); status.State != circuit.Closed {
+        t.Errorf("circuit %s, want the deadline not counted", status.State)
+    }
+}
+
+func TestRecordRun(t *testing.T) {
+    tests := []struct {
+        name       string
+        status     string // set by the scrape before it is recorded
+        err        error
+        wantStatus string
+        wantKind   string
+    }{
+        {"ok", "", nil, models.RunOK, ""},
+        {"unchanged", models.RunUnchanged, nil, models.RunUnchanged, ""},
+        {"failed", "", &FetchError{StatusCode: 500, Err: errors.New("Internal Server Error")}, models.RunFailed, models.ErrorKindHTTP},
+        {"source deadline", "", fmt.Errorf("failed to visit https://example.com/: %w", context.DeadlineExceeded), models.RunTimeout, models.ErrorKindTimeout},
+        {"request timeout", "", &FetchError{Err: os.ErrDeadlineExceeded}, models.RunTimeout, models.ErrorKindTimeout},
+        {"cancelled", models.RunUnchanged, fmt.Errorf("not started: %w", context.Canceled), models.RunCancelled, models.ErrorKindCancelled},
+        {"circuit open", "", &circuit.OpenError{}, models.RunSkipped, models.ErrorKindCircuit},
+        {"no healthy proxy", "", fmt.Errorf("proxy pool test: %w", proxy.ErrNoHealthyProxy), models.RunSkipped, models.ErrorKindProxy},
+    }
+
+    site := newTestSite(t)
+    s, repo, source := newTestScraper(t, site, circuit.Config{})
+    ctx := context.Background()
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            run := &models.ScrapeRun{SourceID: source.ID, SourceName: source.Name, Status: tt.status, StartedAt: time.Now().UTC()}
+            s.recordRun(ctx, run, tt.err)
+            if run.Status != tt.wantStatus || run.ErrorKind != tt.wantKind {
+                t.Errorf("got %s/%s, want %s/%s", run.Status, run.ErrorKind, tt.wantStatus, tt.wantKind)
+            }
+            if (run.Error != "") != (tt.err != nil) {
+                t.Errorf("error %q recorded for %v", run.Error, tt.err)
+            }
+
+            runs, err := repo.GetScrapeRuns(ctx, source.ID, 1)
+            if err != nil || len(runs) != 1 || runs[0].Status != tt.wantStatus {
+                t.Errorf("stored runs %+v (%v), want the latest %s", runs, err, tt.wantStatus)
+            }
+        })
+    }
+}
+
+func TestScrapeAfterShutdown(t *testing.T) {
+    site := newTestSite(t)
+    s, _, _ := newTestScraper(t, site, circuit.Config{})
+    if err := s.Shutdown(context.Background()); err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err := s.ScrapeAll(context.Background()); err != ErrShuttingDown {
+        t.Errorf("got %v, want ErrShuttingDown", err)
+    }
+}
+
+func TestNextPageURL(t *testing.T) {
+    tests := []struct {
+        name     string
+        source   models.Source
+        n        int
+        found    string
+        want     string
+    }{
+        {"found link", models.Source{URL: "https://example.com/news"}, 2, "https://example.com/news?p=2", "https://example.com/news?p=2"},
+        {"query template", models.Source{URL: "https://example.com/news", PageURLTemplate: "?page={n}"}, 3, "", "https://example.com/news?page=3"},
+        {"path template", models.Source{URL: "https://example.com/news/", PageURLTemplate: "page/{n}/"}, 2, "", "https://example.com/news/page/2/"},
+        {"template wins", models.Source{URL: "https://example.com/", PageURLTemplate: "/p/{n}"}, 4, "https://example.com/next", "https://example.com/p/4"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := nextPageURL(tt.source, tt.n, tt.found); got != tt.want {
+                t.Errorf("nextPageURL() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestKnownStreak(t *testing.T) {
+    known := map[string]bool{"a": true, "b": true, "c": true}
+    tests := []struct {
+        name  string
+        limit int
+        urls  []string
+        want  bool
+    }{
+        {"off", 0, []string{"a", "b", "c"}, false},
+        {"streak reached", 2, []string{"x", "a", "b"}, true},
+        {"streak broken", 2, []string{"a", "x", "b"}, false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            streak := &knownStreak{limit: tt.limit, known: known}
+            for _, u := range tt.urls {
+                streak.add(u)
+            }
+            if got := streak.stopped(); got != tt.want {
+                t.Errorf("stopped() = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
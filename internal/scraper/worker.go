@@ -0,0 +1,357 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"news-scraper/internal/circuit"
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+	"news-scraper/internal/proxy"
+	"news-scraper/internal/summarize"
+	"news-scraper/internal/textnorm"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// defaultBodySelector is used when a source has no selector_body
+const defaultBodySelector = "article p, [itemprop='articleBody'] p, main p"
+
+// ArticleWorkers drains the jobs queue filled by the listing scrape
+// Each worker leases one job at a time, fetches the article page,
+// stores its body and metadata, and acks the job. Failures are retried
+// with exponential backoff until the job is dead-lettered, so one broken
+// article never holds up its source or the rest of the queue.
+type ArticleWorkers struct {
+    scraper      *Scraper
+    repo         database.Repository
+    workers      int
+    pollInterval time.Duration
+    lease        time.Duration
+
+    cancel context.CancelFunc
+    wg     sync.WaitGroup
+}
+
+// WorkerConfig holds worker pool configuration
+type WorkerConfig struct {
+    Workers      int           // Number of concurrent article fetchers
+    PollInterval time.Duration // How long an idle worker waits before polling again
+    Lease        time.Duration // How long a job stays claimed before another worker may take it
+}
+
+// NewArticleWorkers creates a worker pool that uses the scraper's HTTP settings
+func NewArticleWorkers(s *Scraper, repo database.Repository, cfg WorkerConfig) *ArticleWorkers {
+    if cfg.Workers <= 0 {
+        cfg.Workers = 1
+    }
+    if cfg.PollInterval <= 0 {
+        cfg.PollInterval = 5 * time.Second
+    }
+    if cfg.Lease <= 0 {
+        cfg.Lease = 2 * time.Minute
+    }
+
+    return &ArticleWorkers{
+        scraper:      s,
+        repo:         repo,
+        workers:      cfg.Workers,
+        pollInterval: cfg.PollInterval,
+        lease:        cfg.Lease,
+    }
+}
+
+// Start launches the workers; they run until Stop is called or ctx is cancelled
+func (w *ArticleWorkers) Start(ctx context.Context) {
+    ctx, w.cancel = context.WithCancel(ctx)
+
+    for i := 0; i < w.workers; i++ {
+        w.wg.Add(1)
+        go func(workerID int) {
+            defer w.wg.Done()
+            w.run(ctx, workerID)
+        }(i)
+    }
+
+    log.Printf("Article worker pool started with %d workers", w.workers)
+}
+
+// Stop cancels the workers and waits for in-flight jobs to return
+// A job interrupted here keeps its lease and is retried after it expires.
+func (w *ArticleWorkers) Stop() {
+    if w.cancel != nil {
+        w.cancel()
+    }
+    w.wg.Wait()
+}
+
+func (w *ArticleWorkers) run(ctx context.Context, workerID int) {
+    for {
+        jobs, err := w.repo.LeaseJobs(ctx, 1, w.lease)
+        if err != nil && ctx.Err() == nil {
+            log.Printf("Article worker %d: failed to lease job: %v", workerID, err)
+        }
+
+        if len(jobs) == 0 {
+            // Nothing to do (or the lease failed): wait before polling again
+            select {
+            case <-ctx.Done():
+                return
+            case <-time.After(w.pollInterval):
+            }
+            continue
+        }
+
+        for _, job := range jobs {
+            w.process(ctx, workerID, job)
+        }
+    }
+}
+
+func (w *ArticleWorkers) process(ctx context.Context, workerID int, job models.Job) {
+    err := w.handle(ctx, job)
+    if ctx.Err() != nil {
+        // Shutting down: leave the lease to expire so the job is retried
+        return
+    }
+
+    if err == nil {
+        if err := w.repo.AckJob(ctx, job); err != nil {
+            log.Printf("Article worker %d: failed to ack job %d: %v", workerID, job.ID, err)
+        }
+        return
+    }
+
+    // The source is paused: come back when its cool-down ends, without
+    // spending an attempt, so jobs outlive the breaker's cool-down
+    var open *circuit.OpenError
+    if errors.As(err, &open) {
+        retryAt := open.RetryAt
+        if retryAt.IsZero() {
+            // A probe is in flight; it decides soon either way
+            retryAt = time.Now().Add(w.pollInterval)
+        }
+        log.Printf("Article worker %d: job %d postponed until %s: %v", workerID, job.ID, retryAt.Format(time.RFC3339), err)
+        if err := w.repo.RescheduleJob(ctx, job, retryAt); err != nil {
+            log.Printf("Article worker %d: failed to reschedule job %d: %v", workerID, job.ID, err)
+        }
+        return
+    }
+
+    // Likewise when the source's proxies are all ejected: the page was never requested
+    if errors.Is(err, proxy.ErrNoHealthyProxy) {
+        retryAt := time.Now().Add(retryBackoff(1))
+        log.Printf("Article worker %d: job %d postponed until %s: %v", workerID, job.ID, retryAt.Format(time.RFC3339), err)
+        if err := w.repo.RescheduleJob(ctx, job, retryAt); err != nil {
+            log.Printf("Article worker %d: failed to reschedule job %d: %v", workerID, job.ID, err)
+        }
+        return
+    }
+
+    retryAt := time.Now().Add(retryBackoff(job.Attempts))
+    if job.Attempts >= job.MaxAttempts {
+        log.Printf("Article worker %d: job %d dead after %d attempts: %v", workerID, job.ID, job.Attempts, err)
+    } else {
+        log.Printf("Article worker %d: job %d failed (attempt %d/%d), retrying at %s: %v",
+            workerID, job.ID, job.Attempts, job.MaxAttempts, retryAt.Format(time.RFC3339), err)
+    }
+
+    if err := w.repo.FailJob(ctx, job, err, retryAt); err != nil {
+        log.Printf("Article worker %d: failed to record failure of job %d: %v", workerID, job.ID, err)
+    }
+}
+
+func (w *ArticleWorkers) handle(ctx context.Context, job models.Job) error {
+    switch job.Kind {
+    case models.JobFetchArticle:
+        var payload models.FetchArticlePayload
+        if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+            return fmt.Errorf("invalid payload: %w", err)
+        }
+
+        source, err := w.repo.GetSourceByID(ctx, payload.SourceID)
+        if err != nil {
+            return fmt.Errorf("failed to load source %d: %w", payload.SourceID, err)
+        }
+        if source == nil {
+            return fmt.Errorf("source %d not found", payload.SourceID)
+        }
+
+        content, err := w.scraper.fetchArticle(ctx, *source, payload.URL)
+        if errors.Is(err, ErrBlockedByRobots) {
+            // Retrying won't help until the site changes its robots.txt
+            log.Printf("Skipping article %s: %v", payload.URL, err)
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+
+        // The page names its own canonical URL; key the article on it
+        // and keep linking to the URL the source gave
+        key := payload.CanonicalURL
+        if key == "" {
+            key = payload.URL
+        }
+        if content.CanonicalURL != "" && content.CanonicalURL != key {
+            if err := w.repo.MergeArticleURL(ctx, key, content.CanonicalURL); err != nil {
+                return fmt.Errorf("failed to move %s to its canonical URL: %w", payload.URL, err)
+            }
+            key = content.CanonicalURL
+        }
+
+        if err := w.repo.UpdateArticleContent(ctx, key, content); err != nil {
+            return err
+        }
+        return w.repo.SetArticleEntities(ctx, key, content.Entities)
+    default:
+        return fmt.Errorf("unknown job kind %q", job.Kind)
+    }
+}
+
+// enqueueArticleFetch queues a body/metadata fetch for a saved article
+func (s *Scraper) enqueueArticleFetch(ctx context.Context, article *models.Article) error {
+    payload, err := json.Marshal(models.FetchArticlePayload{
+        SourceID:     article.SourceID,
+        URL:          article.URL,
+        CanonicalURL: article.CanonicalURL,
+    })
+    if err != nil {
+        return err
+    }
+
+    _, err = s.repo.EnqueueJob(ctx, models.JobFetchArticle, firstNonEmpty(article.CanonicalURL, article.URL), string(payload), s.maxJobAttempts)
+    return err
+}
+
+// fetchArticle downloads a single article page and extracts its content
+func (s *Scraper) fetchArticle(ctx context.Context, source models.Source, articleURL string) (models.ArticleContent, error) {
+    var content models.ArticleContent
+    var headline string
+
+    if !s.robotsAllow(ctx, source, articleURL) {
+        return content, fmt.Errorf("%s: %w", articleURL, ErrBlockedByRobots)
+    }
+
+    c := colly.NewCollector(
+        colly.UserAgent(s.userAgent),
+        colly.StdlibContext(ctx),
+    )
+    if err := s.customize(c, source); err != nil {
+        return content, err
+    }
+    if err := s.useProxy(c, source); err != nil {
+        return content, err
+    }
+
+    c.OnResponse(fixCharset)
+    s.fetchPolitely(ctx, c, source, nil)
+    s.retryTransient(ctx, c)
+
+    bodySelector := source.SelectorBody
+    if bodySelector == "" {
+        bodySelector = defaultBodySelector
+    }
+
+    c.OnHTML("html", func(e *colly.HTMLElement) {
+        // The declared language; DetectLanguage checks it against the body
+        content.Language = e.Attr("lang")
+
+        var paragraphs []string
+        e.DOM.Find(bodySelector).Each(func(_ int, p *goquery.Selection) {
+            if text := strings.TrimSpace(p.Text()); text != "" {
+                paragraphs = append(paragraphs, text)
+            }
+        })
+        content.Body = strings.Join(paragraphs, "\n\n")
+
+        headline = firstNonEmpty(
+            e.ChildAttr(`meta[property="og:title"]`, "content"),
+            e.DOM.Find("h1").First().Text(),
+        )
+        content.Description = firstNonEmpty(
+            e.ChildAttr(`meta[property="og:description"]`, "content"),
+            e.ChildAttr(`meta[name="description"]`, "content"),
+        )
+        if image := firstNonEmpty(
+            e.ChildAttr(`meta[property="og:image"]`, "content"),
+            e.ChildAttr(`meta[name="twitter:image"]`, "content"),
+        ); image != "" {
+            content.ImageURL = e.Request.AbsoluteURL(image)
+        }
+        content.Author = firstNonEmpty(
+            e.ChildAttr(`meta[name="author"]`, "content"),
+            e.ChildAttr(`meta[property="article:author"]`, "content"),
+        )
+
+        if canonical := firstNonEmpty(
+            e.ChildAttr(`link[rel="canonical"]`, "href"),
+            e.ChildAttr(`meta[property="og:url"]`, "content"),
+        ); canonical != "" {
+            content.CanonicalURL = pageCanonicalURL(source, articleURL, e.Request.AbsoluteURL(canonical))
+        }
+
+        published := e.ChildAttr(`meta[property="article:published_time"]`, "content")
+        if t, err := time.Parse(time.RFC3339, published); err == nil {
+            content.PublishedAt = t
+        }
+    })
+
+    if err := s.breakers.Allow(source.ID); err != nil {
+        return content, fmt.Errorf("%s: %w", source.Name, err)
+    }
+    err := s.visit(c, articleURL)
+    s.recordFetch(ctx, source.ID, err)
+    if err != nil {
+        return content, fmt.Errorf("failed to fetch %s: %w", articleURL, err)
+    }
+
+    normalizeContent(&content)
+    content.Language = nlp.DetectLanguage(content.Body, content.Language)
+    content.Entities = nlp.ExtractEntities(textnorm.Line(headline), content.Body)
+
+    // Pages without a description get an extractive summary of the body
+    if content.Description == "" {
+        content.Summary = summarize.Summarize(content.Body, summarize.Sentences)
+    }
+
+    return content, nil
+}
+
+// retryBackoff returns the delay before the next attempt
+// 30s, 1m, 2m, 4m ... capped at one hour, with up to 20% jitter so
+// jobs that failed together don't all come back at the same moment.
+func retryBackoff(attempt int) time.Duration {
+    if attempt < 1 {
+        attempt = 1
+    }
+
+    delay := 30 * time.Second
+    for i := 1; i < attempt && delay < time.Hour; i++ {
+        delay *= 2
+    }
+    if delay > time.Hour {
+        delay = time.Hour
+    }
+
+    jitter := time.Duration(rand.Int63n(int64(delay) / 5))
+    return delay + jitter
+}
+
+func firstNonEmpty(values ...string) string {
+    for _, v := range values {
+        if v = strings.TrimSpace(v); v != "" {
+            return v
+        }
+    }
+    return ""
+}
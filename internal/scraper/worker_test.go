@@ -0,0 +1,144 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"news-scraper/internal/circuit"
+	"news-scraper/internal/models"
+	"news-scraper/internal/proxy"
+)
+
+func TestWorkerFetchesArticle(t *testing.T) {
+    site := newTestSite(t)
+    s, repo, source := newTestScraper(t, site, circuit.Config{})
+    ctx := context.Background()
+
+    if _, err := s.ScrapeAll(ctx); err != nil {
+        t.Fatal(err)
+    }
+    w := NewArticleWorkers(s, repo, WorkerConfig{})
+
+    jobs, err := repo.LeaseJobs(ctx, 10, time.Minute)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(jobs) != 2 {
+        t.Fatalf("got %d queued jobs, want 2", len(jobs))
+    }
+    for _, job := range jobs {
+        w.process(ctx, 0, job)
+    }
+
+    listed, err := repo.GetArticlesBySource(ctx, source.ID)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var ids []int
+    for _, a := range listed {
+        ids = append(ids, a.ID)
+    }
+    articles, err := repo.GetArticlesByIDs(ctx, ids)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(articles) != 2 {
+        t.Fatalf("got %d articles, want 2", len(articles))
+    }
+    for _, a := range articles {
+        if a.Body != "The full story of what happened today." || a.Summary != "What happened." {
+            t.Errorf("%s: got body %q and summary %q", a.URL, a.Body, a.Summary)
+        }
+    }
+
+    dead, err := repo.GetDeadJobs(ctx, 10)
+    if err != nil || len(dead) != 0 {
+        t.Errorf("got %d dead jobs (%v), want none", len(dead), err)
+    }
+}
+
+func TestWorkerPostponesOpenCircuit(t *testing.T) {
+    site := newTestSite(t)
+    s, repo, source := newTestScraper(t, site, circuit.Config{Threshold: 1, Cooldown: 100 * time.Millisecond})
+    ctx := context.Background()
+
+    if _, err := s.ScrapeAll(ctx); err != nil {
+        t.Fatal(err)
+    }
+    w := NewArticleWorkers(s, repo, WorkerConfig{})
+
+    jobs, err := repo.LeaseJobs(ctx, 1, time.Minute)
+    if err != nil || len(jobs) != 1 {
+        t.Fatalf("LeaseJobs: %v, %d jobs", err, len(jobs))
+    }
+    s.breakers.Failure(source.ID, errors.New("HTTP 503"))
+    w.process(ctx, 0, jobs[0])
+
+    // Not due before the cool-down ends, and then on the same attempt
+    if again, err := repo.LeaseJobs(ctx, 10, time.Minute); err != nil || len(again) != 1 || again[0].ID == jobs[0].ID {
+        t.Fatalf("got %d jobs (%v), want only the other one", len(again), err)
+    }
+    time.Sleep(150 * time.Millisecond)
+    again, err := repo.LeaseJobs(ctx, 10, time.Minute)
+    if err != nil || len(again) != 1 {
+        t.Fatalf("LeaseJobs after cool-down: %v, %d jobs", err, len(again))
+    }
+    if again[0].ID != jobs[0].ID || again[0].Attempts != 1 {
+        t.Errorf("got job %d on attempt %d, want job %d on attempt 1", again[0].ID, again[0].Attempts, jobs[0].ID)
+    }
+}
+
+func TestRetryBackoff(t *testing.T) {
+    tests := []struct {
+        attempt int
+        base    time.Duration
+    }{
+        {0, 30 * time.Second},
+        {1, 30 * time.Second},
+        {2, time.Minute},
+        {4, 4 * time.Minute},
+        {20, time.Hour},
+    }
+
+    for _, tt := range tests {
+        got := retryBackoff(tt.attempt)
+        if got < tt.base || got > tt.base+tt.base/5 {
+            t.Errorf("retryBackoff(%d) = %s, want %s plus up to 20%%", tt.attempt, got, tt.base)
+        }
+    }
+}
+
+func TestClassifyError(t *testing.T) {
+    tests := []struct {
+        name string
+        err  error
+        want string
+    }{
+        {"none", nil, ""},
+        {"cancelled", context.Canceled, models.ErrorKindCancelled},
+        {"circuit open", &circuit.OpenError{}, models.ErrorKindCircuit},
+        {"no healthy proxy", fmt.Errorf("failed to visit https://example.com/: proxy pool test: %w", proxy.ErrNoHealthyProxy), models.ErrorKindProxy},
+        {"robots", ErrBlockedByRobots, models.ErrorKindRobots},
+        {"selector", ErrNoArticles, models.ErrorKindSelector},
+        {"database", &storeError{errors.New("disk full")}, models.ErrorKindDatabase},
+        {"http status", &FetchError{StatusCode: 503}, models.ErrorKindHTTP},
+        {"network", &FetchError{Err: errors.New("connection refused")}, models.ErrorKindNetwork},
+        {"source deadline", fmt.Errorf("Example: %w", context.DeadlineExceeded), models.ErrorKindTimeout},
+        {"request timeout", &FetchError{Err: &url.Error{Op: "Get", URL: "https://example.com/", Err: os.ErrDeadlineExceeded}}, models.ErrorKindTimeout},
+        {"cancelled fetch", &FetchError{Err: context.Canceled}, models.ErrorKindCancelled},
+        {"other", errors.New("boom"), models.ErrorKindOther},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := classifyError(tt.err); got != tt.want {
+                t.Errorf("classifyError(%v) = %q, want %q", tt.err, got, tt.want)
+            }
+        })
+    }
+}
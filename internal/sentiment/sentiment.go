@@ -0,0 +1,151 @@
+package sentiment
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+)
+
+// Options controls a sentiment report; zero values use the defaults
+type Options struct {
+    Days   int    // days of coverage, counting today (default 30)
+    Entity string // only articles mentioning this person, organization or place, in any spelling
+    Query  string // only articles whose title or summary contains this text
+}
+
+// Bucket is the tone of a group of articles
+// Articles without a score are counted in Articles but not in the rest.
+type Bucket struct {
+    Key      string  `json:"key"`
+    Articles int     `json:"articles"`
+    Scored   int     `json:"scored"`
+    Average  float64 `json:"average"`
+    Positive int     `json:"positive"`
+    Neutral  int     `json:"neutral"`
+    Negative int     `json:"negative"`
+}
+
+// Report is the result of Aggregate
+type Report struct {
+    GeneratedAt time.Time `json:"generated_at"`
+    Days        int       `json:"days"`
+    Entity      string    `json:"entity,omitempty"`
+    Query       string    `json:"query,omitempty"`
+    Overall     Bucket    `json:"overall"`
+    Sources     []Bucket  `json:"sources"`    // most articles first
+    Categories  []Bucket  `json:"categories"` // most articles first
+    Daily       []Bucket  `json:"daily"`      // one per UTC day, oldest first, keyed YYYY-MM-DD
+}
+
+// Aggregate averages article sentiment overall, per source, per category
+// and per day. Articles are placed by created_at, when they were first seen.
+// The repository does the grouping, so the cost follows the number of
+// sources, categories and days rather than the number of articles.
+func Aggregate(ctx context.Context, repo database.Repository, opts Options) (*Report, error) {
+    opts = withDefaults(opts)
+
+    today := time.Now().UTC().Truncate(24 * time.Hour)
+    start := today.AddDate(0, 0, -(opts.Days - 1))
+
+    var entity string
+    if opts.Entity != "" {
+        entity = nlp.NormalizeEntityName(opts.Entity)
+    }
+    counts, err := repo.GetSentimentCounts(ctx, start, entity, opts.Query)
+    if err != nil {
+        return nil, err
+    }
+
+    report := &Report{
+        GeneratedAt: time.Now().UTC(),
+        Days:        opts.Days,
+        Entity:      opts.Entity,
+        Query:       opts.Query,
+    }
+
+    sources := make(map[string]*Bucket)
+    categories := make(map[string]*Bucket)
+    daily := make([]*Bucket, opts.Days)
+    for d := range daily {
+        daily[d] = &Bucket{Key: start.AddDate(0, 0, d).Format("2006-01-02")}
+    }
+
+    for _, c := range counts {
+        day := opts.Days - 1
+        if t, err := time.Parse("2006-01-02", c.Day); err == nil {
+            day = max(min(int(t.Sub(start)/(24*time.Hour)), opts.Days-1), 0)
+        }
+        category := c.Category
+        if category == "" {
+            category = "uncategorized"
+        }
+        for _, b := range []*Bucket{&report.Overall, bucket(sources, c.SourceName), bucket(categories, category), daily[day]} {
+            b.add(c)
+        }
+    }
+
+    report.Overall.Key = "all"
+    report.Overall.finish()
+    report.Sources = sorted(sources)
+    report.Categories = sorted(categories)
+    for _, b := range daily {
+        b.finish()
+        report.Daily = append(report.Daily, *b)
+    }
+    return report, nil
+}
+
+// add counts a group of articles; Average holds the running sum until finish
+func (b *Bucket) add(c models.SentimentCount) {
+    b.Articles += c.Articles
+    b.Scored += c.Scored
+    b.Average += c.Sum
+    b.Positive += c.Positive
+    b.Neutral += c.Neutral
+    b.Negative += c.Negative
+}
+
+func (b *Bucket) finish() {
+    if b.Scored > 0 {
+        b.Average = math.Round(b.Average/float64(b.Scored)*1000) / 1000
+    }
+}
+
+func bucket(buckets map[string]*Bucket, key string) *Bucket {
+    b, ok := buckets[key]
+    if !ok {
+        b = &Bucket{Key: key}
+        buckets[key] = b
+    }
+    return b
+}
+
+func sorted(buckets map[string]*Bucket) []Bucket {
+    out := make([]Bucket, 0, len(buckets))
+    for _, b := range buckets {
+        b.finish()
+        out = append(out, *b)
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i].Articles == out[j].Articles {
+            return out[i].Key < out[j].Key
+        }
+        return out[i].Articles > out[j].Articles
+    })
+    return out
+}
+
+func withDefaults(opts Options) Options {
+    if opts.Days <= 0 {
+        opts.Days = 30
+    }
+    opts.Entity = strings.TrimSpace(opts.Entity)
+    opts.Query = strings.TrimSpace(opts.Query)
+    return opts
+}
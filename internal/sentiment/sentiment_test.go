@@ -0,0 +1,74 @@
+package sentiment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+)
+
+// fakeRepo serves fixed sentiment groups and records the filters asked for
+type fakeRepo struct {
+    database.Repository
+    counts []models.SentimentCount
+    since  time.Time
+    entity string
+    query  string
+}
+
+func (r *fakeRepo) GetSentimentCounts(ctx context.Context, since time.Time, entity, query string) ([]models.SentimentCount, error) {
+    r.since, r.entity, r.query = since, entity, query
+    return r.counts, nil
+}
+
+func day(daysAgo int) string {
+    return time.Now().UTC().AddDate(0, 0, -daysAgo).Format("2006-01-02")
+}
+
+func TestAggregate(t *testing.T) {
+    repo := &fakeRepo{counts: []models.SentimentCount{
+        {SourceName: "Daily", Category: "sports", Day: day(0), Articles: 3, Scored: 3, Sum: 1.5, Positive: 2, Neutral: 1},
+        {SourceName: "Daily", Category: "", Day: day(1), Articles: 2, Scored: 1, Sum: -0.6, Negative: 1},
+        {SourceName: "Times", Category: "sports", Day: day(0), Articles: 1, Scored: 1, Sum: 0.3, Positive: 1},
+        {SourceName: "Times", Category: "world", Day: day(40), Articles: 1},
+    }}
+
+    report, err := Aggregate(context.Background(), repo, Options{Days: 3, Entity: " U.S. ", Query: " launch "})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if repo.entity != "united states" || repo.query != "launch" {
+        t.Errorf("filters entity %q, query %q, want normalized", repo.entity, repo.query)
+    }
+    if want := time.Now().UTC().Truncate(24 * time.Hour).AddDate(0, 0, -2); !repo.since.Equal(want) {
+        t.Errorf("since %v, want %v", repo.since, want)
+    }
+    if len(report.Daily) != 3 || report.Daily[0].Key != day(2) || report.Daily[2].Key != day(0) {
+        t.Fatalf("daily %+v, want 3 days ending today", report.Daily)
+    }
+
+    tests := []struct {
+        name string
+        got  Bucket
+        want Bucket
+    }{
+        {"overall", report.Overall, Bucket{Key: "all", Articles: 7, Scored: 5, Average: 0.24, Positive: 3, Neutral: 1, Negative: 1}},
+        {"most articles first", report.Sources[0], Bucket{Key: "Daily", Articles: 5, Scored: 4, Average: 0.225, Positive: 2, Neutral: 1, Negative: 1}},
+        {"unscored only counted", report.Sources[1], Bucket{Key: "Times", Articles: 2, Scored: 1, Average: 0.3, Positive: 1}},
+        {"category", report.Categories[0], Bucket{Key: "sports", Articles: 4, Scored: 4, Average: 0.45, Positive: 3, Neutral: 1}},
+        {"ties by key", report.Categories[1], Bucket{Key: "uncategorized", Articles: 2, Scored: 1, Average: -0.6, Negative: 1}},
+        {"older groups clamp to the first day", report.Daily[0], Bucket{Key: day(2), Articles: 1}},
+        {"yesterday", report.Daily[1], Bucket{Key: day(1), Articles: 2, Scored: 1, Average: -0.6, Negative: 1}},
+        {"today", report.Daily[2], Bucket{Key: day(0), Articles: 4, Scored: 4, Average: 0.45, Positive: 3, Neutral: 1}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if tt.got != tt.want {
+                t.Errorf("got %+v, want %+v", tt.got, tt.want)
+            }
+        })
+    }
+}
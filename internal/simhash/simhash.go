@@ -0,0 +1,77 @@
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// stopwords carry no signal about what a story is about
+var stopwords = map[string]bool{
+    "a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+    "by": true, "for": true, "from": true, "has": true, "have": true, "in": true, "is": true,
+    "it": true, "its": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+    "this": true, "to": true, "was": true, "were": true, "will": true, "with": true,
+    "after": true, "over": true, "says": true, "said": true, "new": true,
+}
+
+// Fingerprint computes a 64-bit SimHash of the text
+// Similar texts get fingerprints that differ in few bits; compare them with
+// Distance. Words and word pairs are the features, so reordered or lightly
+// edited copies of a story stay close. Text with no usable words returns 0.
+func Fingerprint(text string) uint64 {
+    words := tokens(text)
+    if len(words) == 0 {
+        return 0
+    }
+
+    var weights [64]int
+    add := func(feature string) {
+        h := fnv.New64a()
+        h.Write([]byte(feature))
+        sum := h.Sum64()
+        for i := 0; i < 64; i++ {
+            if sum&(1<<uint(i)) != 0 {
+                weights[i]++
+            } else {
+                weights[i]--
+            }
+        }
+    }
+
+    for i, w := range words {
+        add(w)
+        if i > 0 {
+            add(words[i-1] + " " + w)
+        }
+    }
+
+    var fp uint64
+    for i, weight := range weights {
+        if weight > 0 {
+            fp |= 1 << uint(i)
+        }
+    }
+    return fp
+}
+
+// Distance is the number of differing bits between two fingerprints
+func Distance(a, b uint64) int {
+    return bits.OnesCount64(a ^ b)
+}
+
+// tokens lowercases the text and returns its words without stopwords
+func tokens(text string) []string {
+    fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+
+    words := fields[:0]
+    for _, f := range fields {
+        if len(f) > 1 && !stopwords[f] {
+            words = append(words, f)
+        }
+    }
+    return words
+}
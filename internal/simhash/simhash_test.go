@@ -0,0 +1,57 @@
+package simhash
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+    tests := []struct {
+        name string
+        a, b uint64
+        want int
+    }{
+        {"equal", 0x5a5a, 0x5a5a, 0},
+        {"one bit", 0b1011, 0b1010, 1},
+        {"top bit", 1 << 63, 0, 1},
+        {"all bits", 0, ^uint64(0), 64},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Distance(tt.a, tt.b); got != tt.want {
+                t.Errorf("Distance(%x, %x) = %d, want %d", tt.a, tt.b, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestFingerprint(t *testing.T) {
+    const wire = "Government announces new budget for schools and hospitals in the capital region this year"
+
+    tests := []struct {
+        name    string
+        a, b    string
+        minDist int
+        maxDist int
+    }{
+        {"identical", wire, wire, 0, 0},
+        {"case, punctuation and stopwords", "Storm hits the coast, thousands without power", "STORM HITS COAST: thousands without power!", 0, 0},
+        {"reworded copy", wire, "Government announces budget for schools and hospitals in capital region this year, officials say", 1, 10},
+        {"unrelated", wire, "Local football club wins championship after dramatic penalty shootout against rivals", 20, 64},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            d := Distance(Fingerprint(tt.a), Fingerprint(tt.b))
+            if d < tt.minDist || d > tt.maxDist {
+                t.Errorf("distance %d, want %d to %d", d, tt.minDist, tt.maxDist)
+            }
+        })
+    }
+}
+
+func TestFingerprintWithoutWords(t *testing.T) {
+    for _, text := range []string{"", "  -- !! ", "the and of a", "x y z"} {
+        if got := Fingerprint(text); got != 0 {
+            t.Errorf("Fingerprint(%q) = %x, want 0", text, got)
+        }
+    }
+}
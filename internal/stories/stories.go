@@ -0,0 +1,141 @@
+package stories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/simhash"
+)
+
+// MaxDistance is the largest SimHash distance at which two articles are the same story
+// Unrelated texts land around 32 bits apart; lightly reworded copies of a
+// wire story usually stay under 10.
+const MaxDistance = 10
+
+// Window is how far back a new article is compared against
+const Window = 72 * time.Hour
+
+// assignMu serializes Assign: sources are scraped concurrently and two runs
+// must not both start a story for the same event
+var assignMu sync.Mutex
+
+// Assign adds every recent article that has no story yet to the closest
+// story within MaxDistance, or starts a new one. It is meant for a backfill,
+// e.g. after seeding; scrapes use AssignArticles for what they saved.
+// It returns the number of articles assigned.
+func Assign(ctx context.Context, repo database.Repository) (int, error) {
+    assignMu.Lock()
+    defer assignMu.Unlock()
+
+    pending, err := repo.GetUnclusteredArticles(ctx, time.Now().Add(-Window))
+    if err != nil {
+        return 0, err
+    }
+    return assign(ctx, repo, pending)
+}
+
+// AssignArticles clusters the articles with the given canonical URLs that
+// have no story yet, like Assign
+func AssignArticles(ctx context.Context, repo database.Repository, canonicalURLs []string) (int, error) {
+    assignMu.Lock()
+    defer assignMu.Unlock()
+
+    pending, err := repo.GetUnclusteredArticlesByURL(ctx, canonicalURLs)
+    if err != nil {
+        return 0, err
+    }
+    return assign(ctx, repo, pending)
+}
+
+// assign clusters pending articles on the fingerprints the repository
+// stores with them (a SimHash of title, summary and body); callers hold assignMu
+func assign(ctx context.Context, repo database.Repository, pending []models.Article) (int, error) {
+    if len(pending) == 0 {
+        return 0, nil
+    }
+
+    pool, err := repo.GetStoryFingerprints(ctx, time.Now().Add(-Window))
+    if err != nil {
+        return 0, err
+    }
+
+    assigned := 0
+    for _, a := range pending {
+        fp := a.Fingerprint
+
+        storyID := 0
+        if fp != 0 {
+            storyID = closest(pool, fp)
+        }
+        if storyID == 0 {
+            storyID, err = repo.CreateStory(ctx, a.Title)
+            if err != nil {
+                return assigned, err
+            }
+        }
+
+        if err := repo.SetArticleStory(ctx, a.ID, storyID); err != nil {
+            return assigned, err
+        }
+        assigned++
+
+        if fp != 0 {
+            pool = append(pool, models.Article{ID: a.ID, SourceID: a.SourceID, StoryID: storyID, Fingerprint: fp})
+        }
+    }
+
+    return assigned, nil
+}
+
+// closest returns the story of the nearest article within MaxDistance, or 0
+func closest(pool []models.Article, fp uint64) int {
+    best, bestDistance := 0, MaxDistance+1
+    for _, p := range pool {
+        if d := simhash.Distance(p.Fingerprint, fp); d < bestDistance {
+            best, bestDistance = p.StoryID, d
+        }
+    }
+    return best
+}
+
+// Group is a story as shown in a listing: its newest article and the others
+type Group struct {
+    Lead   models.Article
+    Others []models.Article
+}
+
+// OtherSources counts the sources covering the story besides the lead's
+func (g Group) OtherSources() int {
+    seen := map[int]bool{g.Lead.SourceID: true}
+    n := 0
+    for _, a := range g.Others {
+        if !seen[a.SourceID] {
+            seen[a.SourceID] = true
+            n++
+        }
+    }
+    return n
+}
+
+// GroupArticles folds a listing into one group per story, keeping its order
+// Articles without a story are groups of their own.
+func GroupArticles(articles []models.Article) []Group {
+    var groups []Group
+    index := make(map[int]int)
+    for _, a := range articles {
+        if a.StoryID == 0 {
+            groups = append(groups, Group{Lead: a})
+            continue
+        }
+        if i, ok := index[a.StoryID]; ok {
+            groups[i].Others = append(groups[i].Others, a)
+            continue
+        }
+        index[a.StoryID] = len(groups)
+        groups = append(groups, Group{Lead: a})
+    }
+    return groups
+}
@@ -0,0 +1,165 @@
+package stories
+
+import (
+	"context"
+	"testing"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+)
+
+func TestAssignArticles(t *testing.T) {
+    ctx := context.Background()
+    repo := database.NewMemoryRepository()
+
+    var sources []models.Source
+    for _, url := range []string{"https://one.example/", "https://two.example/"} {
+        source := models.Source{Name: url, URL: url, SelectorTitle: "h2", Active: true}
+        if err := repo.CreateSource(ctx, &source); err != nil {
+            t.Fatal(err)
+        }
+        sources = append(sources, source)
+    }
+
+    articles := []struct {
+        source int
+        url    string
+        title  string
+    }{
+        {0, "https://one.example/budget", "Government announces new budget for schools and hospitals in the capital region this year"},
+        {1, "https://two.example/budget", "Government announces budget for schools and hospitals in capital region this year, officials say"},
+        {1, "https://two.example/football", "Local football club wins championship after dramatic penalty shootout against rivals"},
+        {0, "https://one.example/untouched", "Storm hits the coast, thousands without power"},
+    }
+    for _, a := range articles {
+        source := sources[a.source]
+        err := repo.SaveArticle(ctx, &models.Article{SourceID: source.ID, SourceName: source.Name, Title: a.title, URL: a.url})
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    // The last article was saved by another run and is left to it
+    urls := []string{articles[0].url, articles[1].url, articles[2].url}
+    assigned, err := AssignArticles(ctx, repo, urls)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if assigned != 3 {
+        t.Fatalf("assigned %d articles, want 3", assigned)
+    }
+
+    stored, err := repo.GetRecentArticles(ctx, 10)
+    if err != nil {
+        t.Fatal(err)
+    }
+    story := make(map[string]int)
+    for _, a := range stored {
+        story[a.URL] = a.StoryID
+    }
+
+    if story[articles[0].url] == 0 || story[articles[0].url] != story[articles[1].url] {
+        t.Errorf("reworded copy not grouped: stories %v", story)
+    }
+    if story[articles[2].url] == 0 || story[articles[2].url] == story[articles[0].url] {
+        t.Errorf("unrelated article not in a story of its own: stories %v", story)
+    }
+    if story[articles[3].url] != 0 {
+        t.Errorf("article outside the batch was clustered: stories %v", story)
+    }
+
+    // Articles that already have a story are skipped
+    if assigned, err := AssignArticles(ctx, repo, urls); err != nil || assigned != 0 {
+        t.Errorf("second AssignArticles assigned %d (%v), want 0", assigned, err)
+    }
+}
+
+func TestGroupArticles(t *testing.T) {
+    tests := []struct {
+        name     string
+        articles []models.Article
+        want     []int // articles per group
+        others   []int // other sources per group
+    }{
+        {"empty", nil, nil, nil},
+        {
+            name:     "no stories",
+            articles: []models.Article{{ID: 1, SourceID: 1}, {ID: 2, SourceID: 2}},
+            want:     []int{1, 1},
+            others:   []int{0, 0},
+        },
+        {
+            name: "story keeps the position of its newest article",
+            articles: []models.Article{
+                {ID: 4, SourceID: 1, StoryID: 7},
+                {ID: 3, SourceID: 2},
+                {ID: 2, SourceID: 2, StoryID: 7},
+                {ID: 1, SourceID: 3, StoryID: 7},
+            },
+            want:   []int{3, 1},
+            others: []int{2, 0},
+        },
+        {
+            name: "same source counted once",
+            articles: []models.Article{
+                {ID: 3, SourceID: 1, StoryID: 5},
+                {ID: 2, SourceID: 1, StoryID: 5},
+                {ID: 1, SourceID: 2, StoryID: 5},
+            },
+            want:   []int{3},
+            others: []int{1},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            groups := GroupArticles(tt.articles)
+            if len(groups) != len(tt.want) {
+                t.Fatalf("got %d groups, want %d", len(groups), len(tt.want))
+            }
+            for i, g := range groups {
+                if n := 1 + len(g.Others); n != tt.want[i] {
+                    t.Errorf("group %d has %d articles, want %d", i, n, tt.want[i])
+                }
+                if n := g.OtherSources(); n != tt.others[i] {
+                    t.Errorf("group %d has %d other sources, want %d", i, n, tt.others[i])
+                }
+            }
+        })
+    }
+}
+
+func TestFingerprintCoversBody(t *testing.T) {
+    ctx := context.Background()
+    repo := database.NewMemoryRepository()
+
+    source := models.Source{Name: "Example", URL: "https://example.com/", SelectorTitle: "h2", Active: true}
+    if err := repo.CreateSource(ctx, &source); err != nil {
+        t.Fatal(err)
+    }
+    url := "https://example.com/news/1"
+    if err := repo.SaveArticle(ctx, &models.Article{SourceID: source.ID, SourceName: source.Name, Title: "Storm hits the coast", URL: url}); err != nil {
+        t.Fatal(err)
+    }
+
+    fingerprint := func() uint64 {
+        t.Helper()
+        pending, err := repo.GetUnclusteredArticlesByURL(ctx, []string{url})
+        if err != nil || len(pending) != 1 {
+            t.Fatalf("GetUnclusteredArticlesByURL: %v, %d articles", err, len(pending))
+        }
+        return pending[0].Fingerprint
+    }
+
+    listed := fingerprint()
+    if listed == 0 {
+        t.Fatal("no fingerprint stored on save")
+    }
+    body := "Thousands of homes lost power overnight as the storm brought flooding to coastal towns."
+    if err := repo.UpdateArticleContent(ctx, url, models.ArticleContent{Body: body}); err != nil {
+        t.Fatal(err)
+    }
+    if fetched := fingerprint(); fetched == listed {
+        t.Error("fingerprint unchanged after the body was fetched")
+    }
+}
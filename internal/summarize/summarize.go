@@ -0,0 +1,239 @@
+package summarize
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"news-scraper/internal/nlp"
+)
+
+// Sentences is how many sentences a generated summary keeps
+const Sentences = 3
+
+// MaxLength caps a generated summary, in bytes
+const MaxLength = 600
+
+// Sentences shorter than this are captions, bylines or "Advertisement"
+const minWords = 6
+
+// abbreviations end in a period without ending the sentence
+var abbreviations = map[string]bool{
+    "mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "st": true,
+    "jr": true, "sr": true, "vs": true, "inc": true, "ltd": true, "co": true,
+    "corp": true, "gen": true, "gov": true, "sen": true, "rep": true, "jan": true,
+    "feb": true, "mar": true, "apr": true, "aug": true, "sept": true, "oct": true,
+    "nov": true, "dec": true,
+}
+
+// Summarize returns the most central sentences of text, in their original order
+// Sentences are ranked with TextRank: each one is a node, edges are
+// weighted by shared keywords, and PageRank finds the sentences the rest
+// of the text agrees with most. It keeps up to n sentences within
+// MaxLength and returns "" when there is nothing worth extracting.
+func Summarize(text string, n int) string {
+    var candidates []sentence
+    for _, s := range Split(text) {
+        keywords := nlp.Keywords(s)
+        if len(strings.Fields(s)) < minWords || len(keywords) == 0 {
+            continue
+        }
+        candidates = append(candidates, sentence{text: s, position: len(candidates), keywords: toSet(keywords)})
+    }
+    if len(candidates) == 0 || n <= 0 {
+        return ""
+    }
+
+    scores := rank(candidates)
+    order := make([]int, len(candidates))
+    for i := range order {
+        order[i] = i
+    }
+    sort.SliceStable(order, func(a, b int) bool {
+        return scores[order[a]] > scores[order[b]]
+    })
+
+    var picked []sentence
+    length := 0
+    for _, i := range order {
+        if len(picked) >= n {
+            break
+        }
+        s := candidates[i]
+        if length+len(s.text) > MaxLength {
+            continue
+        }
+        picked = append(picked, s)
+        length += len(s.text) + 1
+    }
+    if len(picked) == 0 {
+        // Even the best sentence is too long: cut it at a word boundary
+        return truncateWords(candidates[order[0]].text, MaxLength)
+    }
+
+    sort.Slice(picked, func(a, b int) bool { return picked[a].position < picked[b].position })
+    texts := make([]string, len(picked))
+    for i, s := range picked {
+        texts[i] = s.text
+    }
+    return strings.Join(texts, " ")
+}
+
+type sentence struct {
+    text     string
+    position int
+    keywords map[string]bool
+}
+
+// rank runs weighted PageRank over the sentence similarity graph
+func rank(sentences []sentence) []float64 {
+    const (
+        damping    = 0.85
+        iterations = 50
+        epsilon    = 1e-6
+    )
+
+    n := len(sentences)
+    weights := make([][]float64, n)
+    totals := make([]float64, n)
+    for i := range weights {
+        weights[i] = make([]float64, n)
+    }
+    for i := 0; i < n; i++ {
+        for j := i + 1; j < n; j++ {
+            w := similarity(sentences[i], sentences[j])
+            weights[i][j], weights[j][i] = w, w
+            totals[i] += w
+            totals[j] += w
+        }
+    }
+
+    scores := make([]float64, n)
+    for i := range scores {
+        scores[i] = 1
+    }
+    for iter := 0; iter < iterations; iter++ {
+        next := make([]float64, n)
+        delta := 0.0
+        for i := 0; i < n; i++ {
+            sum := 0.0
+            for j := 0; j < n; j++ {
+                if weights[j][i] > 0 {
+                    sum += weights[j][i] / totals[j] * scores[j]
+                }
+            }
+            next[i] = (1 - damping) + damping*sum
+            delta += math.Abs(next[i] - scores[i])
+        }
+        scores = next
+        if delta < epsilon {
+            break
+        }
+    }
+    return scores
+}
+
+// similarity is the TextRank overlap measure: shared keywords normalized
+// by sentence length, so long sentences don't win by size alone
+func similarity(a, b sentence) float64 {
+    shared := 0
+    for w := range a.keywords {
+        if b.keywords[w] {
+            shared++
+        }
+    }
+    if shared == 0 {
+        return 0
+    }
+    return float64(shared) / (math.Log(float64(1+len(a.keywords))) + math.Log(float64(1+len(b.keywords))))
+}
+
+// Split breaks text into sentences
+// Paragraph breaks always end a sentence; '.', '!', '?' and the Devanagari
+// danda end one when followed by a space, unless the period belongs to an
+// abbreviation, an initial or an acronym.
+func Split(text string) []string {
+    var sentences []string
+    for _, paragraph := range strings.Split(text, "\n\n") {
+        runes := []rune(strings.Join(strings.Fields(paragraph), " "))
+        start := 0
+        for i := 0; i < len(runes); i++ {
+            if !isTerminator(runes[i]) {
+                continue
+            }
+            end := i + 1
+            for end < len(runes) && isCloser(runes[end]) {
+                end++
+            }
+            if end < len(runes) && runes[end] != ' ' {
+                continue
+            }
+            if runes[i] == '.' && !endsSentence(runes[start:i]) {
+                continue
+            }
+            if s := strings.TrimSpace(string(runes[start:end])); s != "" {
+                sentences = append(sentences, s)
+            }
+            start = end
+            i = end - 1
+        }
+        if s := strings.TrimSpace(string(runes[start:])); s != "" {
+            sentences = append(sentences, s)
+        }
+    }
+    return sentences
+}
+
+func isTerminator(r rune) bool {
+    return r == '.' || r == '!' || r == '?' || r == '।'
+}
+
+func isCloser(r rune) bool {
+    return r == '"' || r == '\'' || r == ')' || r == '”' || r == '’'
+}
+
+// endsSentence reports whether a period after text ends the sentence
+func endsSentence(text []rune) bool {
+    i := len(text)
+    for i > 0 && text[i-1] != ' ' {
+        i--
+    }
+    word := string(text[i:])
+    if word == "" {
+        return true
+    }
+    // A single capital letter is an initial ("John F. Kennedy"), and dotted
+    // letters are an acronym ("J.P. Morgan")
+    if r := []rune(word); len(r) == 1 && unicode.IsUpper(r[0]) || strings.Contains(word, ".") {
+        return false
+    }
+    return !abbreviations[strings.ToLower(strings.TrimLeft(word, "(\"'“‘"))]
+}
+
+// truncateWords cuts s to at most max bytes at a word boundary
+func truncateWords(s string, max int) string {
+    if len(s) <= max {
+        return s
+    }
+    cut := strings.LastIndex(s[:max], " ")
+    if cut <= 0 {
+        cut = max
+        for cut > 0 && !utf8Start(s[cut]) {
+            cut--
+        }
+    }
+    return strings.TrimRight(s[:cut], " ,;:") + "…"
+}
+
+func utf8Start(b byte) bool {
+    return b&0xC0 != 0x80
+}
+
+func toSet(words []string) map[string]bool {
+    set := make(map[string]bool, len(words))
+    for _, w := range words {
+        set[w] = true
+    }
+    return set
+}
@@ -0,0 +1,135 @@
+package summarize
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplit(t *testing.T) {
+    tests := []struct {
+        name string
+        text string
+        want []string
+    }{
+        {"empty", "", nil},
+        {"terminators", "One here. Two there! Three?", []string{"One here.", "Two there!", "Three?"}},
+        {"no final period", "One here. Two there", []string{"One here.", "Two there"}},
+        {"paragraph break", "First line\n\nSecond line", []string{"First line", "Second line"}},
+        {"whitespace collapsed", "  One\n here.   Two  ", []string{"One here.", "Two"}},
+        {"abbreviation", "Dr. Smith met Mr. Jones. They talked.", []string{"Dr. Smith met Mr. Jones.", "They talked."}},
+        {"initial", "John F. Kennedy spoke. Crowds cheered.", []string{"John F. Kennedy spoke.", "Crowds cheered."}},
+        {"acronym", "Shares of J.P. Morgan rose. Others fell.", []string{"Shares of J.P. Morgan rose.", "Others fell."}},
+        {"no space after period", "Version 2.5 shipped. Users upgraded.", []string{"Version 2.5 shipped.", "Users upgraded."}},
+        {"closing quote", `He said "stop." Then left.`, []string{`He said "stop."`, "Then left."}},
+        {"danda", "पहला वाक्य। दूसरा वाक्य।", []string{"पहला वाक्य।", "दूसरा वाक्य।"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Split(tt.text); !slices.Equal(got, tt.want) {
+                t.Errorf("Split(%q) = %q, want %q", tt.text, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestSummarize(t *testing.T) {
+    article := strings.Join([]string{
+        "The city council approved the new transit budget on Tuesday evening.",
+        "Advertisement",
+        "The transit budget adds bus routes and extends train service across the city.",
+        "Council members said the budget was the largest transit investment in a decade.",
+        "Photo: Jane Doe",
+        "Separately, a local bakery celebrated its fiftieth anniversary with free pastries.",
+        "Riders welcomed the council vote on transit and the budget for new routes.",
+    }, " ")
+
+    tests := []struct {
+        name string
+        text string
+        n    int
+        want []string // sentences expected, in order
+        not  []string
+    }{
+        {"empty", "", 3, nil, nil},
+        {"captions only", "Advertisement. Photo: Jane Doe. Read more.", 3, nil, nil},
+        {"zero sentences", article, 0, nil, nil},
+        {
+            name: "central sentences in original order",
+            text: article,
+            n:    2,
+            not:  []string{"Advertisement", "Photo", "bakery"},
+        },
+        {
+            name: "one sentence",
+            text: "The council approved the transit budget after a long debate on Tuesday.",
+            n:    3,
+            want: []string{"The council approved the transit budget after a long debate on Tuesday."},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := Summarize(tt.text, tt.n)
+            if tt.want == nil && tt.not == nil && got != "" {
+                t.Fatalf("Summarize() = %q, want empty", got)
+            }
+            if tt.want != nil && got != strings.Join(tt.want, " ") {
+                t.Errorf("Summarize() = %q, want %q", got, strings.Join(tt.want, " "))
+            }
+            if tt.not != nil && got == "" {
+                t.Error("Summarize() = \"\", want a summary")
+            }
+            for _, s := range tt.not {
+                if strings.Contains(got, s) {
+                    t.Errorf("Summarize() = %q, should not contain %q", got, s)
+                }
+            }
+            if tt.n > 0 && len(Split(got)) > tt.n {
+                t.Errorf("Summarize() kept %d sentences, want at most %d", len(Split(got)), tt.n)
+            }
+        })
+    }
+}
+
+func TestSummarizeKeepsOrder(t *testing.T) {
+    article := "The council approved the transit budget on Tuesday evening. " +
+        "A bakery on Main Street celebrated fifty years with free pastries for everyone. " +
+        "The transit budget adds new bus routes that the council debated for months."
+
+    got := Split(Summarize(article, 2))
+    if len(got) != 2 || !strings.Contains(got[0], "Tuesday") || !strings.Contains(got[1], "bus routes") {
+        t.Errorf("Summarize() picked %q, want the two transit sentences in order", got)
+    }
+}
+
+func TestSummarizeMaxLength(t *testing.T) {
+    long := "The council " + strings.Repeat("approved the ambitious transit budget ", 30) + "today."
+
+    got := Summarize(long, 3)
+    if len(got) > MaxLength+len("…") || !strings.HasSuffix(got, "…") || !utf8.ValidString(got) {
+        t.Errorf("Summarize() of a %d byte sentence = %d bytes %q", len(long), len(got), got)
+    }
+}
+
+func TestTruncateWords(t *testing.T) {
+    tests := []struct {
+        s    string
+        max  int
+        want string
+    }{
+        {"short", 10, "short"},
+        {"one two three", 8, "one two…"},
+        {"one, two three", 6, "one…"},
+        {"unbroken", 4, "unbr…"},
+        {"héllo", 2, "h…"},
+    }
+
+    for _, tt := range tests {
+        if got := truncateWords(tt.s, tt.max); got != tt.want {
+            t.Errorf("truncateWords(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,102 @@
+package textdiff
+
+import "strings"
+
+// Op says what happened to a chunk of text between two versions
+type Op int
+
+const (
+    Equal Op = iota
+    Insert
+    Delete
+)
+
+// Chunk is a run of text with the same Op
+type Chunk struct {
+    Op   Op
+    Text string
+}
+
+// maxCells bounds the LCS table; larger inputs are shown as a full replacement
+const maxCells = 4_000_000
+
+// Words diffs two strings word by word, for titles and summaries
+func Words(old, new string) []Chunk {
+    return diff(strings.Fields(old), strings.Fields(new), " ")
+}
+
+// Paragraphs diffs two strings paragraph by paragraph, for article bodies
+func Paragraphs(old, new string) []Chunk {
+    return diff(splitParagraphs(old), splitParagraphs(new), "\n\n")
+}
+
+// diff finds the longest common subsequence of tokens and returns the
+// edits that turn a into b, with adjacent tokens of the same Op joined by sep
+func diff(a, b []string, sep string) []Chunk {
+    if len(a)*len(b) > maxCells {
+        var chunks []Chunk
+        chunks = appendChunk(chunks, Delete, strings.Join(a, sep), sep)
+        return appendChunk(chunks, Insert, strings.Join(b, sep), sep)
+    }
+
+    // lcs[i][j] is the LCS length of a[i:] and b[j:]
+    lcs := make([][]int, len(a)+1)
+    for i := range lcs {
+        lcs[i] = make([]int, len(b)+1)
+    }
+    for i := len(a) - 1; i >= 0; i-- {
+        for j := len(b) - 1; j >= 0; j-- {
+            if a[i] == b[j] {
+                lcs[i][j] = lcs[i+1][j+1] + 1
+            } else {
+                lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+            }
+        }
+    }
+
+    var chunks []Chunk
+    i, j := 0, 0
+    for i < len(a) && j < len(b) {
+        switch {
+        case a[i] == b[j]:
+            chunks = appendChunk(chunks, Equal, a[i], sep)
+            i++
+            j++
+        case lcs[i+1][j] >= lcs[i][j+1]:
+            chunks = appendChunk(chunks, Delete, a[i], sep)
+            i++
+        default:
+            chunks = appendChunk(chunks, Insert, b[j], sep)
+            j++
+        }
+    }
+    for ; i < len(a); i++ {
+        chunks = appendChunk(chunks, Delete, a[i], sep)
+    }
+    for ; j < len(b); j++ {
+        chunks = appendChunk(chunks, Insert, b[j], sep)
+    }
+    return chunks
+}
+
+// appendChunk adds text to the last chunk if it has the same Op
+func appendChunk(chunks []Chunk, op Op, text, sep string) []Chunk {
+    if text == "" {
+        return chunks
+    }
+    if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+        chunks[n-1].Text += sep + text
+        return chunks
+    }
+    return append(chunks, Chunk{Op: op, Text: text})
+}
+
+func splitParagraphs(s string) []string {
+    var paragraphs []string
+    for _, p := range strings.Split(s, "\n") {
+        if p = strings.TrimSpace(p); p != "" {
+            paragraphs = append(paragraphs, p)
+        }
+    }
+    return paragraphs
+}
@@ -0,0 +1,167 @@
+package textnorm
+
+import (
+	"html"
+	"mime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ToUTF8 converts an HTML document to UTF-8
+// A charset in the Content-Type header is handled by colly before this
+// runs, so this covers the pages that only declare it in a <meta> tag,
+// or not at all. Bodies that are already valid UTF-8 are returned as is,
+// even when a meta tag claims otherwise: mislabeled pages are common and
+// re-decoding them would garble every non-ASCII character.
+func ToUTF8(body []byte, contentType string) []byte {
+    if utf8.Valid(body) {
+        return body
+    }
+    if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+        return body
+    }
+
+    // Meta prescan, falling back to windows-1252 like browsers do
+    enc, _, _ := charset.DetermineEncoding(body, contentType)
+    decoded, err := enc.NewDecoder().Bytes(body)
+    if err != nil {
+        return body
+    }
+    return decoded
+}
+
+// Line normalizes text that belongs on one line: titles, authors, teasers
+// Every run of whitespace, line breaks included, becomes a single space.
+func Line(s string) string {
+    return strings.Join(strings.Fields(clean(s)), " ")
+}
+
+// Paragraphs normalizes body text, keeping paragraphs separated by a blank line
+func Paragraphs(s string) string {
+    var paragraphs []string
+    for _, p := range strings.Split(clean(s), "\n\n") {
+        if p = strings.Join(strings.Fields(p), " "); p != "" {
+            paragraphs = append(paragraphs, p)
+        }
+    }
+    return strings.Join(paragraphs, "\n\n")
+}
+
+// Truncate shortens s to at most max characters without splitting a
+// character, and at a word boundary when one is close to the limit
+func Truncate(s string, max int) string {
+    if utf8.RuneCountInString(s) <= max {
+        return s
+    }
+
+    cut, n := 0, 0
+    for i := range s {
+        if n == max {
+            cut = i
+            break
+        }
+        n++
+    }
+
+    // Prefer the last space in the final tenth over cutting a word in half
+    if space := strings.LastIndexFunc(s[:cut], unicode.IsSpace); space > 0 && utf8.RuneCountInString(s[space:cut]) <= max/10 {
+        cut = space
+    }
+    return strings.TrimRightFunc(s[:cut], unicode.IsSpace)
+}
+
+// clean repairs encoding damage and removes what should never be stored:
+// invalid bytes, leftover entities, mojibake, invisible characters.
+// Text comes out in NFC with paragraph breaks as "\n\n" and other
+// whitespace as single spaces.
+func clean(s string) string {
+    s = strings.ToValidUTF8(s, "")
+    if strings.Contains(s, "&") {
+        // e.Text decodes entities once; what's left was double-encoded
+        s = html.UnescapeString(s)
+    }
+    s = fixMojibake(s)
+    s = norm.NFC.String(s)
+
+    s = strings.ReplaceAll(s, "\r\n", "\n")
+    var b strings.Builder
+    b.Grow(len(s))
+    for _, r := range s {
+        switch {
+        case r == '\n':
+            b.WriteRune(r)
+        case unicode.IsSpace(r):
+            b.WriteByte(' ')
+        case invisible(r):
+        default:
+            b.WriteRune(r)
+        }
+    }
+
+    // Any run of lines with a blank one between them is a paragraph break
+    lines := strings.Split(b.String(), "\n")
+    var out []string
+    blank := false
+    for _, line := range lines {
+        if strings.TrimSpace(line) == "" {
+            blank = true
+            continue
+        }
+        if len(out) > 0 {
+            if blank {
+                out = append(out, "\n\n")
+            } else {
+                out = append(out, " ")
+            }
+        }
+        out = append(out, line)
+        blank = false
+    }
+    return strings.Join(out, "")
+}
+
+// invisible reports characters that render as nothing: control characters,
+// zero-width spaces, soft hyphens, BOMs and bidi controls, plus the
+// replacement character. The zero-width joiner and non-joiner are kept; Indic scripts use them to
+// choose letter forms.
+func invisible(r rune) bool {
+    switch r {
+    case '\u200c', '\u200d':
+        return false
+    case '\ufffd':
+        // Replacement characters mark bytes that were already lost
+        return true
+    }
+    return unicode.IsControl(r) || unicode.Is(unicode.Cf, r)
+}
+
+// mojibakeMarkers are what UTF-8 punctuation and accents look like after
+// being decoded as windows-1252: "â€™" for ’, "Ã©" for é, "Â " for a non-breaking space
+var mojibakeMarkers = []string{"â€", "Ã", "Â"}
+
+// fixMojibake undoes UTF-8 text that was decoded as windows-1252 somewhere upstream
+// The text is encoded back to windows-1252 bytes; if those form valid
+// UTF-8 with fewer markers, that was the original.
+func fixMojibake(s string) string {
+    if markers(s) == 0 {
+        return s
+    }
+    raw, err := charmap.Windows1252.NewEncoder().String(s)
+    if err != nil || !utf8.ValidString(raw) || markers(raw) >= markers(s) {
+        return s
+    }
+    return raw
+}
+
+func markers(s string) int {
+    n := 0
+    for _, m := range mojibakeMarkers {
+        n += strings.Count(s, m)
+    }
+    return n
+}
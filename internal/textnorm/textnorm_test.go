@@ -0,0 +1,123 @@
+package textnorm
+
+import "testing"
+
+func TestToUTF8(t *testing.T) {
+    tests := []struct {
+        name        string
+        body        []byte
+        contentType string
+        want        string
+    }{
+        {
+            name:        "meta charset",
+            body:        []byte("<html><head><meta charset=\"iso-8859-1\"></head><body>Caf\xe9</body></html>"),
+            contentType: "text/html",
+            want:        "<html><head><meta charset=\"iso-8859-1\"></head><body>Café</body></html>",
+        },
+        {
+            name:        "undeclared is windows-1252",
+            body:        []byte("<p>\x93quoted\x94</p>"),
+            contentType: "text/html",
+            want:        "<p>“quoted”</p>",
+        },
+        {
+            name:        "already UTF-8 despite meta",
+            body:        []byte("<meta charset=\"iso-8859-1\"><p>Café</p>"),
+            contentType: "text/html",
+            want:        "<meta charset=\"iso-8859-1\"><p>Café</p>",
+        },
+        {
+            name:        "header charset is left to colly",
+            body:        []byte("<p>Caf\xe9</p>"),
+            contentType: "text/html; charset=iso-8859-1",
+            want:        "<p>Caf\xe9</p>",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := string(ToUTF8(tt.body, tt.contentType)); got != tt.want {
+                t.Errorf("ToUTF8() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestLine(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        want string
+    }{
+        {"NFC", "Cafe\u0301", "Caf\u00e9"},
+        {"whitespace collapse", "  Breaking\n\tnews \u00a0 today  ", "Breaking news today"},
+        {"zero-width space", "Head\u200bline", "Headline"},
+        {"soft hyphen", "Ex\u00adample", "Example"},
+        {"BOM and bidi controls", "\ufeffHello \u202eworld\u202c", "Hello world"},
+        {"control characters", "Tab\x00le", "Table"},
+        {"replacement character", "Bad \ufffdbyte", "Bad byte"},
+        {"zero-width joiner kept", "\u0915\u094d\u200d\u0937", "\u0915\u094d\u200d\u0937"},
+        {"mojibake quotes", "It\u00e2\u20ac\u2122s here", "It\u2019s here"},
+        {"mojibake accent", "Caf\u00c3\u00a9", "Caf\u00e9"},
+        {"genuine accents untouched", "\u00c7a co\u00fbte \u00e0 \u00c2", "\u00c7a co\u00fbte \u00e0 \u00c2"},
+        {"double-encoded entity", "Tom &amp; Jerry", "Tom & Jerry"},
+        {"double-encoded numeric entity", "Rock &#39;n&#39; roll", "Rock 'n' roll"},
+        {"invalid UTF-8", "Bro\xffken", "Broken"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Line(tt.in); got != tt.want {
+                t.Errorf("Line(%q) = %q, want %q", tt.in, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParagraphs(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        want string
+    }{
+        {"blank line separates", "One.\n\nTwo.", "One.\n\nTwo."},
+        {"single break joins", "One\nline.", "One line."},
+        {"blank lines collapse", "One.\r\n\r\n \r\n\n Two.  ", "One.\n\nTwo."},
+        {"whitespace inside paragraph", "Too   many\t spaces.", "Too many spaces."},
+        {"empty", " \n\n ", ""},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Paragraphs(tt.in); got != tt.want {
+                t.Errorf("Paragraphs(%q) = %q, want %q", tt.in, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestTruncate(t *testing.T) {
+    tests := []struct {
+        name string
+        in   string
+        max  int
+        want string
+    }{
+        {"short enough", "Short", 10, "Short"},
+        {"exact length", "Exact", 5, "Exact"},
+        {"counts runes not bytes", "ééééé", 5, "ééééé"},
+        {"cuts between runes", "ééééééé", 5, "ééééé"},
+        {"multi-byte script", "日本語のニュース", 3, "日本語"},
+        {"word boundary near the limit", "The quick brown fox jumps", 21, "The quick brown fox"},
+        {"no space near the limit", "Supercalifragilistic words", 12, "Supercalifra"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Truncate(tt.in, tt.max); got != tt.want {
+                t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,277 @@
+package trends
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+	"news-scraper/internal/nlp"
+)
+
+// Options controls a trends analysis; zero values use the defaults
+type Options struct {
+    Window   time.Duration // recent period terms are scored on (default 24h)
+    Baseline time.Duration // history before the window to compare with (default 7 days)
+    Limit    int           // terms per list (default 20)
+    MinCount int           // articles a term needs in the window to count (default 3)
+}
+
+// Term is a keyword or two-word phrase and how it's doing
+type Term struct {
+    Term     string  `json:"term"`
+    Count    int     `json:"count"`    // articles in the window mentioning it
+    Baseline int     `json:"baseline"` // articles in the baseline mentioning it
+    TFIDF    float64 `json:"tfidf"`
+    Spike    float64 `json:"spike"` // hourly rate in the window / hourly rate in the baseline
+}
+
+// Series is the number of articles per hour mentioning a term
+type Series struct {
+    Term   string `json:"term"`
+    Counts []int  `json:"counts"`
+}
+
+// Report is the result of Analyze
+type Report struct {
+    GeneratedAt     time.Time         `json:"generated_at"`
+    WindowHours     int               `json:"window_hours"`
+    BaselineHours   int               `json:"baseline_hours"`
+    Articles        int               `json:"articles"` // articles in the window
+    Rising          []Term            `json:"rising"`
+    Top             []Term            `json:"top"`
+    Categories      map[string][]Term `json:"categories"`
+    Hours           []time.Time       `json:"hours"`
+    ArticlesPerHour []int             `json:"articles_per_hour"`
+    Series          []Series          `json:"series"`
+}
+
+// Number of terms per category and of charted series
+const (
+    categoryLimit = 5
+    seriesLimit   = 5
+)
+
+// document is an article reduced to its term counts
+type document struct {
+    category string
+    hour     int // bucket index in the window, -1 for the baseline
+    terms    map[string]int
+}
+
+// Analyze finds the terms that dominate the recent window (TF-IDF against
+// window and baseline together) and the ones rising fastest compared with
+// their baseline rate. Articles are placed by created_at, when they were
+// first seen.
+func Analyze(ctx context.Context, repo database.Repository, opts Options) (*Report, error) {
+    opts = withDefaults(opts)
+
+    end := time.Now().UTC().Truncate(time.Hour).Add(time.Hour)
+    windowStart := end.Add(-opts.Window)
+    baselineStart := windowStart.Add(-opts.Baseline)
+    hours := int(opts.Window / time.Hour)
+
+    articles, err := repo.GetArticlesCreatedSince(ctx, baselineStart)
+    if err != nil {
+        return nil, err
+    }
+
+    report := &Report{
+        GeneratedAt:     time.Now().UTC(),
+        WindowHours:     hours,
+        BaselineHours:   int(opts.Baseline / time.Hour),
+        Categories:      make(map[string][]Term),
+        ArticlesPerHour: make([]int, hours),
+    }
+    for h := 0; h < hours; h++ {
+        report.Hours = append(report.Hours, windowStart.Add(time.Duration(h)*time.Hour))
+    }
+
+    docs := make([]document, 0, len(articles))
+    df := make(map[string]int)
+    for _, a := range articles {
+        doc := document{category: a.Category, hour: -1, terms: termCounts(a)}
+        if !a.CreatedAt.Before(windowStart) {
+            doc.hour = min(int(a.CreatedAt.Sub(windowStart)/time.Hour), hours-1)
+            report.ArticlesPerHour[doc.hour]++
+            report.Articles++
+        }
+        for term := range doc.terms {
+            df[term]++
+        }
+        docs = append(docs, doc)
+    }
+
+    terms := scoreTerms(docs, df, "", opts)
+    report.Top = topBy(terms, opts.Limit, func(t Term) float64 { return t.TFIDF })
+    report.Rising = topBy(rising(terms), opts.Limit, risingScore)
+
+    categories := make(map[string]bool)
+    for _, d := range docs {
+        if d.hour >= 0 && d.category != "" {
+            categories[d.category] = true
+        }
+    }
+    for category := range categories {
+        scored := scoreTerms(docs, df, category, Options{MinCount: 2, Window: opts.Window, Baseline: opts.Baseline})
+        if top := topBy(scored, categoryLimit, func(t Term) float64 { return t.TFIDF }); len(top) > 0 {
+            report.Categories[category] = top
+        }
+    }
+
+    charted := report.Rising
+    if len(charted) == 0 {
+        charted = report.Top
+    }
+    for _, t := range charted[:min(seriesLimit, len(charted))] {
+        series := Series{Term: t.Term, Counts: make([]int, hours)}
+        for _, d := range docs {
+            if d.hour >= 0 && d.terms[t.Term] > 0 {
+                series.Counts[d.hour]++
+            }
+        }
+        report.Series = append(report.Series, series)
+    }
+
+    return report, nil
+}
+
+// termCounts returns how often each keyword and keyword pair occurs in an article
+// Title words count twice: headlines say what a story is about.
+func termCounts(a models.Article) map[string]int {
+    counts := make(map[string]int)
+    add := func(text string, weight int) {
+        words := nlp.Words(text)
+        for i, w := range words {
+            if !nlp.IsKeyword(w) {
+                continue
+            }
+            counts[w] += weight
+            if i > 0 && nlp.IsKeyword(words[i-1]) {
+                counts[words[i-1]+" "+w] += weight
+            }
+        }
+    }
+    add(a.Title, 2)
+    add(a.Summary, 1)
+    return counts
+}
+
+// scoreTerms aggregates window and baseline counts, optionally for one category
+func scoreTerms(docs []document, df map[string]int, category string, opts Options) []Term {
+    type agg struct{ tf, count, baseline int }
+    byTerm := make(map[string]*agg)
+
+    for _, d := range docs {
+        if category != "" && d.category != category {
+            continue
+        }
+        for term, n := range d.terms {
+            a := byTerm[term]
+            if a == nil {
+                a = &agg{}
+                byTerm[term] = a
+            }
+            if d.hour >= 0 {
+                a.tf += n
+                a.count++
+            } else {
+                a.baseline++
+            }
+        }
+    }
+
+    n := float64(len(docs))
+    windowHours := opts.Window.Hours()
+    baselineHours := opts.Baseline.Hours()
+
+    var terms []Term
+    for term, a := range byTerm {
+        if a.count < opts.MinCount {
+            continue
+        }
+        // Add-one smoothing keeps terms new to the baseline finite
+        spike := (float64(a.count+1) / windowHours) / (float64(a.baseline+1) / baselineHours)
+        terms = append(terms, Term{
+            Term:     term,
+            Count:    a.count,
+            Baseline: a.baseline,
+            TFIDF:    round(float64(a.tf) * math.Log(n/float64(df[term]))),
+            Spike:    round(spike),
+        })
+    }
+    return terms
+}
+
+// rising keeps terms mentioned at least twice as often as in the baseline
+func rising(terms []Term) []Term {
+    var out []Term
+    for _, t := range terms {
+        if t.Spike >= 2 {
+            out = append(out, t)
+        }
+    }
+    return out
+}
+
+// risingScore favours big spikes, but not on a handful of mentions
+func risingScore(t Term) float64 {
+    return t.Spike * math.Log1p(float64(t.Count))
+}
+
+// topBy sorts terms by score and drops a word when a phrase containing it ranks higher
+func topBy(terms []Term, limit int, score func(Term) float64) []Term {
+    sort.Slice(terms, func(i, j int) bool {
+        si, sj := score(terms[i]), score(terms[j])
+        if si == sj {
+            // On a tie the phrase goes first so it can cover its words
+            wi, wj := strings.Count(terms[i].Term, " "), strings.Count(terms[j].Term, " ")
+            if wi != wj {
+                return wi > wj
+            }
+            return terms[i].Term < terms[j].Term
+        }
+        return si > sj
+    })
+
+    covered := make(map[string]bool)
+    var out []Term
+    for _, t := range terms {
+        if len(out) >= limit {
+            break
+        }
+        if covered[t.Term] {
+            continue
+        }
+        out = append(out, t)
+        for _, w := range nlp.Words(t.Term) {
+            covered[w] = true
+        }
+    }
+    return out
+}
+
+func withDefaults(opts Options) Options {
+    if opts.Window < time.Hour {
+        opts.Window = 24 * time.Hour
+    }
+    if opts.Baseline < time.Hour {
+        opts.Baseline = 7 * 24 * time.Hour
+    }
+    if opts.Limit <= 0 {
+        opts.Limit = 20
+    }
+    if opts.MinCount <= 0 {
+        opts.MinCount = 3
+    }
+    opts.Window = opts.Window.Truncate(time.Hour)
+    opts.Baseline = opts.Baseline.Truncate(time.Hour)
+    return opts
+}
+
+func round(f float64) float64 {
+    return math.Round(f*100) / 100
+}
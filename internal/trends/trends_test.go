@@ -0,0 +1,151 @@
+package trends
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"news-scraper/internal/database"
+	"news-scraper/internal/models"
+)
+
+// fakeRepo serves articles with chosen created_at times, which the
+// in-memory repository always sets to the time of the save
+type fakeRepo struct {
+    database.Repository
+    articles []models.Article
+}
+
+func (r fakeRepo) GetArticlesCreatedSince(ctx context.Context, since time.Time) ([]models.Article, error) {
+    var out []models.Article
+    for _, a := range r.articles {
+        if !a.CreatedAt.Before(since) {
+            out = append(out, a)
+        }
+    }
+    return out, nil
+}
+
+// articles returns n copies of a headline, created age ago
+func articles(n int, title, category string, age time.Duration) []models.Article {
+    out := make([]models.Article, n)
+    for i := range out {
+        out[i] = models.Article{Title: title, Category: category, CreatedAt: time.Now().UTC().Add(-age)}
+    }
+    return out
+}
+
+func terms(list []Term) []string {
+    var out []string
+    for _, t := range list {
+        out = append(out, t.Term)
+    }
+    return out
+}
+
+func TestAnalyze(t *testing.T) {
+    var repo fakeRepo
+    repo.articles = append(repo.articles, articles(5, "Earthquake strikes coastal city", "world", 30*time.Minute)...)
+    repo.articles = append(repo.articles, articles(3, "Parliament debates budget", "politics", 2*time.Hour)...)
+    repo.articles = append(repo.articles, articles(40, "Parliament debates budget", "politics", 3*24*time.Hour)...)
+    repo.articles = append(repo.articles, articles(2, "Rare comet sighted", "science", 30*time.Minute)...)
+    repo.articles = append(repo.articles, articles(5, "Ancient history", "science", 30*24*time.Hour)...)
+
+    report, err := Analyze(context.Background(), repo, Options{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name string
+        got  []string
+        has  []string
+        not  []string
+    }{
+        {"rising", terms(report.Rising), []string{"earthquake strikes"}, []string{"parliament debates", "comet", "ancient"}},
+        {"top", terms(report.Top), []string{"earthquake strikes", "parliament debates"}, []string{"comet", "ancient"}},
+        {"phrase covers its words", terms(report.Top), nil, []string{"earthquake", "strikes"}},
+        {"world category", terms(report.Categories["world"]), []string{"earthquake strikes"}, nil},
+        {"category min count is 2", terms(report.Categories["science"]), []string{"rare comet"}, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            for _, term := range tt.has {
+                if !slices.Contains(tt.got, term) {
+                    t.Errorf("%q missing from %q", term, tt.got)
+                }
+            }
+            for _, term := range tt.not {
+                if slices.Contains(tt.got, term) {
+                    t.Errorf("%q listed in %q", term, tt.got)
+                }
+            }
+        })
+    }
+
+    if report.Articles != 10 || report.WindowHours != 24 || report.BaselineHours != 7*24 {
+        t.Errorf("report counts %d articles over %dh/%dh, want 10 over 24h/168h", report.Articles, report.WindowHours, report.BaselineHours)
+    }
+    if len(report.Hours) != 24 || len(report.ArticlesPerHour) != 24 {
+        t.Fatalf("got %d hours and %d counts, want 24", len(report.Hours), len(report.ArticlesPerHour))
+    }
+    if n := report.ArticlesPerHour[23]; n != 7 {
+        t.Errorf("last hour has %d articles, want 7", n)
+    }
+    if len(report.Series) == 0 || report.Series[0].Term != report.Rising[0].Term || report.Series[0].Counts[23] != 5 {
+        t.Errorf("series %+v, want the top rising term with 5 in the last hour", report.Series)
+    }
+}
+
+func TestSpike(t *testing.T) {
+    tests := []struct {
+        name     string
+        window   int
+        baseline int
+        want     float64
+    }{
+        // Add-one smoothing: (window+1)/24h over (baseline+1)/168h
+        {"steady rate", 1, 13, 1},
+        {"new term", 3, 0, 28},
+        {"quieter than before", 1, 27, 0.5},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var docs []document
+            for range tt.window {
+                docs = append(docs, document{hour: 0, terms: map[string]int{"term": 1}})
+            }
+            for range tt.baseline {
+                docs = append(docs, document{hour: -1, terms: map[string]int{"term": 1}})
+            }
+
+            scored := scoreTerms(docs, map[string]int{"term": len(docs)}, "", withDefaults(Options{MinCount: 1}))
+            if len(scored) != 1 || scored[0].Spike != tt.want {
+                t.Errorf("scored %+v, want spike %v", scored, tt.want)
+            }
+        })
+    }
+}
+
+func TestWithDefaults(t *testing.T) {
+    tests := []struct {
+        name string
+        in   Options
+        want Options
+    }{
+        {"zero", Options{}, Options{Window: 24 * time.Hour, Baseline: 7 * 24 * time.Hour, Limit: 20, MinCount: 3}},
+        {"kept", Options{Window: 6 * time.Hour, Baseline: 48 * time.Hour, Limit: 5, MinCount: 1}, Options{Window: 6 * time.Hour, Baseline: 48 * time.Hour, Limit: 5, MinCount: 1}},
+        {"truncated to hours", Options{Window: 90 * time.Minute, Baseline: 150 * time.Minute}, Options{Window: time.Hour, Baseline: 2 * time.Hour, Limit: 20, MinCount: 3}},
+        {"under an hour", Options{Window: time.Minute}, Options{Window: 24 * time.Hour, Baseline: 7 * 24 * time.Hour, Limit: 20, MinCount: 3}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := withDefaults(tt.in); got != tt.want {
+                t.Errorf("withDefaults(%+v) = %+v, want %+v", tt.in, got, tt.want)
+            }
+        })
+    }
+}
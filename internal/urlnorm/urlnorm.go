@@ -0,0 +1,161 @@
+package urlnorm
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// trackingParams are dropped from every URL
+// Names ending in "_" are prefixes (utm_ matches utm_source, utm_medium, ...).
+var trackingParams = []string{
+    "utm_", "fbclid", "gclid", "dclid", "msclkid", "yclid", "mc_cid", "mc_eid",
+    "_ga", "_gl", "igshid", "ocid", "cmpid", "cmp", "ref", "ref_src", "ref_url",
+    "smid", "sr_share", "at_medium", "at_campaign", "ito", "CMP",
+    "outputType", "amp",
+}
+
+// Canonicalize normalizes an article URL so the variants publishers link
+// to (tracking parameters, http vs https, www., AMP pages, trailing
+// slashes) map to one key.
+//
+// keep lists the query parameters that identify an article on this source;
+// when it is non-empty every other parameter is dropped.
+//
+// The result is a dedupe key, not a URL to fetch: the host it names may
+// not serve https or the page without www. or the AMP path.
+func Canonicalize(raw string, keep []string) (string, error) {
+    u, err := url.Parse(strings.TrimSpace(raw))
+    if err != nil {
+        return "", err
+    }
+
+    u = unwrapAMPCache(u)
+    if u.Host == "" {
+        return "", fmt.Errorf("%q is not an absolute URL", raw)
+    }
+
+    if u.Scheme == "http" || u.Scheme == "" {
+        u.Scheme = "https"
+    }
+    u.Scheme = strings.ToLower(u.Scheme)
+
+    host := strings.ToLower(u.Hostname())
+    host = strings.TrimPrefix(host, "www.")
+    host = strings.TrimPrefix(host, "amp.")
+    if port := u.Port(); port != "" && port != "80" && port != "443" {
+        host += ":" + port
+    }
+    u.Host = host
+
+    // Clean the escaped form so an encoded slash (%2F) stays part of its segment
+    escaped := normalizePath(u.EscapedPath())
+    if p, err := url.PathUnescape(escaped); err == nil {
+        u.Path, u.RawPath = p, escaped
+    }
+    u.RawQuery = normalizeQuery(u.Query(), keep)
+    u.Fragment = ""
+    u.RawFragment = ""
+    u.User = nil
+
+    return u.String(), nil
+}
+
+// unwrapAMPCache turns Google AMP cache URLs back into the publisher URL
+// https://example-com.cdn.ampproject.org/c/s/example.com/a/1 -> https://example.com/a/1
+func unwrapAMPCache(u *url.URL) *url.URL {
+    if !strings.HasSuffix(strings.ToLower(u.Hostname()), ".cdn.ampproject.org") {
+        return u
+    }
+
+    rest := strings.TrimPrefix(u.Path, "/")
+    for _, prefix := range []string{"c/s/", "v/s/", "c/", "v/"} {
+        if strings.HasPrefix(rest, prefix) {
+            inner, err := url.Parse("https://" + strings.TrimPrefix(rest, prefix))
+            if err != nil {
+                return u
+            }
+            inner.RawQuery = u.RawQuery
+            return inner
+        }
+    }
+    return u
+}
+
+// normalizePath cleans the path and strips AMP markers and trailing slashes
+func normalizePath(p string) string {
+    if p == "" {
+        return "/"
+    }
+
+    p = path.Clean(p)
+    switch {
+    case strings.HasSuffix(p, "/amp"):
+        p = strings.TrimSuffix(p, "/amp")
+    case strings.HasPrefix(p, "/amp/"):
+        p = strings.TrimPrefix(p, "/amp")
+    case strings.HasSuffix(p, ".amp.html"):
+        p = strings.TrimSuffix(p, ".amp.html") + ".html"
+    case strings.HasSuffix(p, ".amp"):
+        p = strings.TrimSuffix(p, ".amp")
+    }
+
+    if p == "" || p == "." {
+        return "/"
+    }
+    return p
+}
+
+// normalizeQuery drops tracking parameters and sorts the rest
+func normalizeQuery(q url.Values, keep []string) string {
+    keepSet := make(map[string]bool, len(keep))
+    for _, k := range keep {
+        if k = strings.TrimSpace(k); k != "" {
+            keepSet[k] = true
+        }
+    }
+
+    for name := range q {
+        if len(keepSet) > 0 {
+            if !keepSet[name] {
+                q.Del(name)
+            }
+            continue
+        }
+        if isTracking(name) {
+            q.Del(name)
+        }
+    }
+
+    // Encode sorts by key; sort values too so ?a=2&a=1 and ?a=1&a=2 match
+    for _, values := range q {
+        sort.Strings(values)
+    }
+    return q.Encode()
+}
+
+func isTracking(name string) bool {
+    for _, t := range trackingParams {
+        if strings.HasSuffix(t, "_") {
+            if strings.HasPrefix(strings.ToLower(name), t) {
+                return true
+            }
+        } else if name == t {
+            return true
+        }
+    }
+    return false
+}
+
+// SplitParams parses a comma-separated keep_query_params value
+func SplitParams(s string) []string {
+    var params []string
+    for _, p := range strings.Split(s, ",") {
+        if p = strings.TrimSpace(p); p != "" {
+            params = append(params, p)
+        }
+    }
+    return params
+}
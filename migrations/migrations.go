@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned SQL schema migrations
+// Each supported driver has its own directory (mysql, postgres, sqlite)
+// holding the same versions, named NNN_description.up.sql and
+// NNN_description.down.sql, applied in version order by database.Migrator.
+package migrations
+
+import "embed"
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var Files embed.FS
@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS sources (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(512) NOT NULL,
+    selector_title VARCHAR(255) NOT NULL,
+    selector_link VARCHAR(255) NOT NULL,
+    selector_summary VARCHAR(255),
+    default_category VARCHAR(50) DEFAULT 'general',
+    active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_url (url)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- utf8mb4 index keys are limited to 3072 bytes, so uniqueness is
+-- enforced on the first 768 characters of the URL
+CREATE TABLE IF NOT EXISTS articles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    source_id INT NOT NULL,
+    source_name VARCHAR(255) NOT NULL,
+    title VARCHAR(512) NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    summary TEXT,
+    category VARCHAR(50) DEFAULT 'general',
+    scraped_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_article (url(768)),
+    INDEX idx_scraped_at (scraped_at),
+    INDEX idx_source_id (source_id),
+    INDEX idx_category (category)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Databases created by the old startup code have an articles table
+-- without source_name and with url VARCHAR(512); bring them in line
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'articles' AND column_name = 'source_name') = 0,
+    'ALTER TABLE articles ADD COLUMN source_name VARCHAR(255) NOT NULL DEFAULT '''' AFTER source_id',
+    'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+ALTER TABLE articles
+    DROP INDEX unique_article,
+    MODIFY url VARCHAR(1024) NOT NULL,
+    ADD UNIQUE KEY unique_article (url(768));
+
+UPDATE articles a JOIN sources s ON s.id = a.source_id
+SET a.source_name = s.name
+WHERE a.source_name = '';
+
+-- Insert sample sources
+INSERT IGNORE INTO sources (name, url, selector_title, selector_link, selector_summary) VALUES
+('TechCrunch', 'https://techcrunch.com', '[class*="post"] a[href*="techcrunch"]', '[class*="post"] a[href*="techcrunch"]', 'div.post-block__content'),
+('BBC News', 'https://www.bbc.com/news', 'a[data-testid="internal-link"]', 'a[data-testid="internal-link"]', 'p.gs-c-promo-summary'),
+('The Guardian', 'https://www.theguardian.com/international', 'a[href*="/2025/"]', 'a[href*="/2025/"]', 'div[data-link-name*="article title"] p'),
+('Reuters', 'https://www.reuters.com', 'a[href^="/world/"]' , 'a[href^="/world/"]' , 'p[data-testid="Text"]'),
+('The Indian Express', 'https://indianexpress.com/', 'h3 a, h2 a', 'h3 a, h2 a', 'div.short-story p');
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS jobs;
+
+ALTER TABLE articles
+    DROP COLUMN fetched_at,
+    DROP COLUMN published_at,
+    DROP COLUMN author,
+    DROP COLUMN image_url,
+    DROP COLUMN body;
+
+ALTER TABLE sources DROP COLUMN selector_body;
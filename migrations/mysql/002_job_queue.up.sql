@@ -0,0 +1,45 @@
+-- Columns are added only when missing: databases created by the old
+-- startup code already have them
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'sources' AND column_name = 'selector_body') = 0,
+    'ALTER TABLE sources ADD COLUMN selector_body VARCHAR(255) AFTER selector_summary',
+    'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.columns
+     WHERE table_schema = DATABASE() AND table_name = 'articles' AND column_name = 'body') = 0,
+    'ALTER TABLE articles
+        ADD COLUMN body MEDIUMTEXT AFTER category,
+        ADD COLUMN image_url VARCHAR(1024) AFTER body,
+        ADD COLUMN author VARCHAR(255) AFTER image_url,
+        ADD COLUMN published_at DATETIME NULL AFTER author,
+        ADD COLUMN fetched_at DATETIME NULL AFTER published_at',
+    'DO 0');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Durable work queue for per-article fetches.
+-- Rows are leased by the worker pool, acked to 'done', retried with
+-- backoff, and moved to 'dead' once max_attempts is exhausted.
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    dedupe_key CHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    last_error TEXT,
+    lease_token CHAR(32),
+    leased_until DATETIME NULL,
+    run_after DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    UNIQUE KEY unique_job (kind, dedupe_key),
+    INDEX idx_status_run_after (status, run_after)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
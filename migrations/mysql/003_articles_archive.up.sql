@@ -0,0 +1,21 @@
+-- Expired articles moved aside by the retention job when archive mode is "table".
+-- No unique key on url: the same URL can be archived more than once.
+CREATE TABLE IF NOT EXISTS articles_archive (
+    id INT NOT NULL,
+    source_id INT NOT NULL,
+    source_name VARCHAR(255) NOT NULL,
+    title VARCHAR(512) NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    summary TEXT,
+    category VARCHAR(50),
+    body MEDIUMTEXT,
+    image_url VARCHAR(1024),
+    author VARCHAR(255),
+    published_at DATETIME NULL,
+    scraped_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    archived_at DATETIME NOT NULL,
+    INDEX idx_archive_id (id),
+    INDEX idx_archive_source_id (source_id),
+    INDEX idx_archived_at (archived_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,18 @@
+-- Every distinct title/summary/body an article has had.
+-- A row is added whenever a save changes the content hash.
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id INT NOT NULL,
+    title VARCHAR(512) NOT NULL,
+    summary TEXT,
+    body MEDIUMTEXT,
+    content_hash CHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL,
+    INDEX idx_revisions_article (article_id, id),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Existing articles start with their current content as the first revision.
+-- The hash is left empty and computed by the application on the next save.
+INSERT INTO article_revisions (article_id, title, summary, body, content_hash, created_at)
+SELECT id, title, summary, body, '', COALESCE(scraped_at, CURRENT_TIMESTAMP) FROM articles;
@@ -0,0 +1,3 @@
+-- Query parameters that identify an article on this source.
+-- Comma-separated; when set, every other parameter is stripped from article URLs.
+ALTER TABLE sources ADD COLUMN keep_query_params VARCHAR(255) AFTER selector_body;
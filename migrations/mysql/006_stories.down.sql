@@ -0,0 +1,7 @@
+ALTER TABLE articles
+    DROP FOREIGN KEY fk_articles_story,
+    DROP INDEX idx_story_id,
+    DROP COLUMN story_id,
+    DROP COLUMN fingerprint;
+
+DROP TABLE IF EXISTS stories;
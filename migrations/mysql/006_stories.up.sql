@@ -0,0 +1,14 @@
+-- Near-duplicate articles from different sources are grouped into stories.
+-- fingerprint is the SimHash of title and summary, stored as a signed 64-bit value.
+CREATE TABLE IF NOT EXISTS stories (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(512) NOT NULL,
+    created_at DATETIME NOT NULL,
+    INDEX idx_stories_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE articles
+    ADD COLUMN fingerprint BIGINT NULL AFTER category,
+    ADD COLUMN story_id INT NULL AFTER fingerprint,
+    ADD INDEX idx_story_id (story_id),
+    ADD CONSTRAINT fk_articles_story FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE SET NULL;
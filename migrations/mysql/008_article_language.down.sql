@@ -0,0 +1,3 @@
+ALTER TABLE articles
+    DROP INDEX idx_language,
+    DROP COLUMN language;
@@ -0,0 +1,4 @@
+-- ISO 639-1 code detected from the article text and the page's lang attribute
+ALTER TABLE articles
+    ADD COLUMN language VARCHAR(8) NULL AFTER category,
+    ADD INDEX idx_language (language);
@@ -0,0 +1,12 @@
+-- People, organizations and places mentioned in each article.
+-- normalized_name is the lookup key for /api/entities/:name/articles.
+CREATE TABLE IF NOT EXISTS article_entities (
+    article_id INT NOT NULL,
+    type VARCHAR(16) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL,
+    mentions INT NOT NULL DEFAULT 1,
+    PRIMARY KEY (article_id, type, normalized_name),
+    INDEX idx_entities_name (normalized_name, article_id),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scrape_run_blocked;
+DROP TABLE IF EXISTS scrape_runs;
+ALTER TABLE sources DROP COLUMN ignore_robots;
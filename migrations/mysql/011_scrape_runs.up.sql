@@ -0,0 +1,26 @@
+-- Set to skip robots.txt and Crawl-delay for a source, e.g. one that
+-- has allowed us in writing.
+ALTER TABLE sources ADD COLUMN ignore_robots BOOLEAN NOT NULL DEFAULT FALSE AFTER keep_query_params;
+
+-- One row per source per scrape, with the URLs robots.txt kept us from fetching.
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    source_id INT NOT NULL,
+    source_name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    articles_found INT NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME NOT NULL,
+    INDEX idx_scrape_runs_source (source_id, started_at),
+    INDEX idx_scrape_runs_started_at (started_at),
+    FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS scrape_run_blocked (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    run_id BIGINT NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    INDEX idx_scrape_run_blocked_run (run_id),
+    FOREIGN KEY (run_id) REFERENCES scrape_runs(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+-- HTTP validators and body hash of each listing page, so unchanged pages
+-- are answered with 304 Not Modified or at least not parsed again.
+CREATE TABLE IF NOT EXISTS page_cache (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    source_id INT NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    etag VARCHAR(255),
+    last_modified VARCHAR(64),
+    content_hash CHAR(64),
+    checked_at DATETIME NOT NULL,
+    UNIQUE KEY unique_page (url(768)),
+    FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
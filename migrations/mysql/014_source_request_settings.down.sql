@@ -0,0 +1,5 @@
+ALTER TABLE sources
+    DROP COLUMN cookies,
+    DROP COLUMN headers,
+    DROP COLUMN timeout_seconds,
+    DROP COLUMN user_agent;
@@ -0,0 +1,7 @@
+-- Per-source request settings for sites that only show the real page to
+-- a particular user agent, language or consent cookie.
+ALTER TABLE sources
+    ADD COLUMN user_agent VARCHAR(255) NULL AFTER proxy_pool,
+    ADD COLUMN timeout_seconds INT NULL AFTER user_agent,
+    ADD COLUMN headers TEXT NULL AFTER timeout_seconds,
+    ADD COLUMN cookies TEXT NULL AFTER headers;
@@ -0,0 +1,4 @@
+ALTER TABLE sources
+    DROP COLUMN max_pages,
+    DROP COLUMN page_url_template,
+    DROP COLUMN selector_next;
@@ -0,0 +1,5 @@
+-- How the scraper finds a source's further listing pages.
+ALTER TABLE sources
+    ADD COLUMN selector_next VARCHAR(255) NULL AFTER selector_body,
+    ADD COLUMN page_url_template VARCHAR(1024) NULL AFTER selector_next,
+    ADD COLUMN max_pages INT NULL AFTER page_url_template;
@@ -0,0 +1,6 @@
+ALTER TABLE scrape_runs
+    DROP COLUMN articles_known,
+    DROP COLUMN articles_new;
+
+ALTER TABLE sources
+    DROP COLUMN stop_after_known;
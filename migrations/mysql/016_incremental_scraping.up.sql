@@ -0,0 +1,8 @@
+-- Incremental scraping: stop after a streak of known articles, and count
+-- new and already-known articles per run.
+ALTER TABLE sources
+    ADD COLUMN stop_after_known INT NULL AFTER max_pages;
+
+ALTER TABLE scrape_runs
+    ADD COLUMN articles_new INT NOT NULL DEFAULT 0 AFTER articles_found,
+    ADD COLUMN articles_known INT NOT NULL DEFAULT 0 AFTER articles_new;
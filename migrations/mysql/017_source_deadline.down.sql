@@ -0,0 +1,2 @@
+ALTER TABLE sources
+    DROP COLUMN deadline_seconds;
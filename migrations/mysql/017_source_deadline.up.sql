@@ -0,0 +1,3 @@
+-- How long one scrape of a source may take before it is cancelled.
+ALTER TABLE sources
+    ADD COLUMN deadline_seconds INT NULL AFTER timeout_seconds;
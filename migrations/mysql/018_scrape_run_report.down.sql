@@ -0,0 +1,3 @@
+ALTER TABLE scrape_runs
+    DROP COLUMN error_kind,
+    DROP COLUMN articles_updated;
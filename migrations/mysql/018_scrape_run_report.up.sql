@@ -0,0 +1,4 @@
+-- Updated articles and the class of a failed run's error.
+ALTER TABLE scrape_runs
+    ADD COLUMN articles_updated INT NOT NULL DEFAULT 0 AFTER articles_known,
+    ADD COLUMN error_kind VARCHAR(32) NULL AFTER error;
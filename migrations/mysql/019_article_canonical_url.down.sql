@@ -0,0 +1,3 @@
+ALTER TABLE articles
+    DROP INDEX unique_canonical_url,
+    DROP COLUMN canonical_url;
@@ -0,0 +1,8 @@
+-- The canonical form of an article's URL, the key articles are deduplicated
+-- on; url keeps the link the publisher gave, which is what gets fetched.
+ALTER TABLE articles
+    ADD COLUMN canonical_url VARCHAR(1024) NULL AFTER url;
+UPDATE articles SET canonical_url = url;
+ALTER TABLE articles
+    MODIFY canonical_url VARCHAR(1024) NOT NULL,
+    ADD UNIQUE KEY unique_canonical_url (canonical_url(768));
@@ -0,0 +1,5 @@
+ALTER TABLE articles_archive
+    DROP COLUMN sentiment,
+    DROP COLUMN language,
+    DROP COLUMN summary_generated,
+    DROP COLUMN canonical_url;
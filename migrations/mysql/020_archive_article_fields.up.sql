@@ -0,0 +1,6 @@
+-- Archived rows keep the columns added to articles since the archive table.
+ALTER TABLE articles_archive
+    ADD COLUMN canonical_url VARCHAR(1024) NULL AFTER url,
+    ADD COLUMN summary_generated BOOLEAN NOT NULL DEFAULT FALSE AFTER summary,
+    ADD COLUMN language VARCHAR(8) NULL AFTER category,
+    ADD COLUMN sentiment DOUBLE NULL AFTER language;
@@ -1,5 +1,5 @@
 CREATE TABLE IF NOT EXISTS sources (
-    id INT AUTO_INCREMENT PRIMARY KEY,
+    id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
     url VARCHAR(512) NOT NULL,
     selector_title VARCHAR(255) NOT NULL,
@@ -8,13 +8,13 @@ CREATE TABLE IF NOT EXISTS sources (
     default_category VARCHAR(50) DEFAULT 'general',
     active BOOLEAN DEFAULT TRUE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    UNIQUE KEY unique_url (url)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_url UNIQUE (url)
+);
 
 CREATE TABLE IF NOT EXISTS articles (
-    id INT AUTO_INCREMENT PRIMARY KEY,
-    source_id INT NOT NULL,
+    id SERIAL PRIMARY KEY,
+    source_id INTEGER NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
     source_name VARCHAR(255) NOT NULL,
     title VARCHAR(512) NOT NULL,
     url VARCHAR(1024) NOT NULL,
@@ -22,12 +22,12 @@ CREATE TABLE IF NOT EXISTS articles (
     category VARCHAR(50) DEFAULT 'general',
     scraped_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (source_id) REFERENCES sources(id) ON DELETE CASCADE,
-    UNIQUE KEY unique_article (url),
-    INDEX idx_scraped_at (scraped_at),
-    INDEX idx_source_id (source_id),
-    INDEX idx_category (category)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+    CONSTRAINT unique_article UNIQUE (url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scraped_at ON articles (scraped_at);
+CREATE INDEX IF NOT EXISTS idx_source_id ON articles (source_id);
+CREATE INDEX IF NOT EXISTS idx_category ON articles (category);
 
 -- Insert sample sources
 INSERT INTO sources (name, url, selector_title, selector_link, selector_summary) VALUES
@@ -35,4 +35,5 @@ INSERT INTO sources (name, url, selector_title, selector_link, selector_summary)
 ('BBC News', 'https://www.bbc.com/news', 'a[data-testid="internal-link"]', 'a[data-testid="internal-link"]', 'p.gs-c-promo-summary'),
 ('The Guardian', 'https://www.theguardian.com/international', 'a[href*="/2025/"]', 'a[href*="/2025/"]', 'div[data-link-name*="article title"] p'),
 ('Reuters', 'https://www.reuters.com', 'a[href^="/world/"]' , 'a[href^="/world/"]' , 'p[data-testid="Text"]'),
-('The Indian Express', 'https://indianexpress.com/', 'h3 a, h2 a', 'h3 a, h2 a', 'div.short-story p');
+('The Indian Express', 'https://indianexpress.com/', 'h3 a, h2 a', 'h3 a, h2 a', 'div.short-story p')
+ON CONFLICT (url) DO NOTHING;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS jobs;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS fetched_at,
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS author,
+    DROP COLUMN IF EXISTS image_url,
+    DROP COLUMN IF EXISTS body;
+
+ALTER TABLE sources DROP COLUMN IF EXISTS selector_body;
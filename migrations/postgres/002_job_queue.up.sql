@@ -0,0 +1,30 @@
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS selector_body VARCHAR(255);
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS body TEXT,
+    ADD COLUMN IF NOT EXISTS image_url VARCHAR(1024),
+    ADD COLUMN IF NOT EXISTS author VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS fetched_at TIMESTAMP NULL;
+
+-- Durable work queue for per-article fetches.
+-- Rows are leased by the worker pool, acked to 'done', retried with
+-- backoff, and moved to 'dead' once max_attempts is exhausted.
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    dedupe_key CHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    last_error TEXT,
+    lease_token CHAR(32),
+    leased_until TIMESTAMP NULL,
+    run_after TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CONSTRAINT unique_job UNIQUE (kind, dedupe_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_run_after ON jobs (status, run_after);
@@ -0,0 +1,22 @@
+-- Expired articles moved aside by the retention job when archive mode is "table".
+-- No unique key on url: the same URL can be archived more than once.
+CREATE TABLE IF NOT EXISTS articles_archive (
+    id INTEGER NOT NULL,
+    source_id INTEGER NOT NULL,
+    source_name VARCHAR(255) NOT NULL,
+    title VARCHAR(512) NOT NULL,
+    url VARCHAR(1024) NOT NULL,
+    summary TEXT,
+    category VARCHAR(50),
+    body TEXT,
+    image_url VARCHAR(1024),
+    author VARCHAR(255),
+    published_at TIMESTAMP NULL,
+    scraped_at TIMESTAMP NULL,
+    created_at TIMESTAMP NULL,
+    archived_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_archive_id ON articles_archive (id);
+CREATE INDEX IF NOT EXISTS idx_archive_source_id ON articles_archive (source_id);
+CREATE INDEX IF NOT EXISTS idx_archived_at ON articles_archive (archived_at);
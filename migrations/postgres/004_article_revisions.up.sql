@@ -0,0 +1,18 @@
+-- Every distinct title/summary/body an article has had.
+-- A row is added whenever a save changes the content hash.
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    title VARCHAR(512) NOT NULL,
+    summary TEXT,
+    body TEXT,
+    content_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_revisions_article ON article_revisions (article_id, id);
+
+-- Existing articles start with their current content as the first revision.
+-- The hash is left empty and computed by the application on the next save.
+INSERT INTO article_revisions (article_id, title, summary, body, content_hash, created_at)
+SELECT id, title, summary, body, '', COALESCE(scraped_at, CURRENT_TIMESTAMP) FROM articles;
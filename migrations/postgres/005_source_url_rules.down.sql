@@ -0,0 +1 @@
+ALTER TABLE sources DROP COLUMN IF EXISTS keep_query_params;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_story_id;
+ALTER TABLE articles DROP COLUMN IF EXISTS story_id;
+ALTER TABLE articles DROP COLUMN IF EXISTS fingerprint;
+
+DROP TABLE IF EXISTS stories;
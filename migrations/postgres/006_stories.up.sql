@@ -0,0 +1,15 @@
+-- Near-duplicate articles from different sources are grouped into stories.
+-- fingerprint is the SimHash of title and summary, stored as a signed 64-bit value.
+CREATE TABLE IF NOT EXISTS stories (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(512) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stories_created_at ON stories (created_at);
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS fingerprint BIGINT NULL,
+    ADD COLUMN IF NOT EXISTS story_id INTEGER NULL REFERENCES stories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_story_id ON articles (story_id);
@@ -0,0 +1,3 @@
+-- summary_generated marks summaries extracted from the article body,
+-- as opposed to the publisher's own teaser or description.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS summary_generated BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,12 @@
+-- People, organizations and places mentioned in each article.
+-- normalized_name is the lookup key for /api/entities/:name/articles.
+CREATE TABLE IF NOT EXISTS article_entities (
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    type VARCHAR(16) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL,
+    mentions INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (article_id, type, normalized_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entities_name ON article_entities (normalized_name, article_id);
@@ -0,0 +1,3 @@
+-- Tone of the title and summary, from -1 (negative) to 1 (positive).
+-- NULL until the article is saved again after this migration.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS sentiment DOUBLE PRECISION NULL;
@@ -0,0 +1,11 @@
+-- HTTP validators and body hash of each listing page, so unchanged pages
+-- are answered with 304 Not Modified or at least not parsed again.
+CREATE TABLE IF NOT EXISTS page_cache (
+    id BIGSERIAL PRIMARY KEY,
+    source_id INTEGER NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    url VARCHAR(1024) NOT NULL UNIQUE,
+    etag VARCHAR(255),
+    last_modified VARCHAR(64),
+    content_hash CHAR(64),
+    checked_at TIMESTAMP NOT NULL
+);
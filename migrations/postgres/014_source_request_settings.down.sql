@@ -0,0 +1,4 @@
+ALTER TABLE sources DROP COLUMN IF EXISTS cookies;
+ALTER TABLE sources DROP COLUMN IF EXISTS headers;
+ALTER TABLE sources DROP COLUMN IF EXISTS timeout_seconds;
+ALTER TABLE sources DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,6 @@
+-- Per-source request settings for sites that only show the real page to
+-- a particular user agent, language or consent cookie.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS user_agent VARCHAR(255);
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS headers TEXT;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS cookies TEXT;
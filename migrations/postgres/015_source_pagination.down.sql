@@ -0,0 +1,3 @@
+ALTER TABLE sources DROP COLUMN IF EXISTS max_pages;
+ALTER TABLE sources DROP COLUMN IF EXISTS page_url_template;
+ALTER TABLE sources DROP COLUMN IF EXISTS selector_next;
@@ -0,0 +1,4 @@
+-- How the scraper finds a source's further listing pages.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS selector_next VARCHAR(255);
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS page_url_template VARCHAR(1024);
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS max_pages INTEGER;
@@ -0,0 +1,4 @@
+ALTER TABLE scrape_runs DROP COLUMN IF EXISTS articles_known;
+ALTER TABLE scrape_runs DROP COLUMN IF EXISTS articles_new;
+
+ALTER TABLE sources DROP COLUMN IF EXISTS stop_after_known;
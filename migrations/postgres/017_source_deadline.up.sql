@@ -0,0 +1,2 @@
+-- How long one scrape of a source may take before it is cancelled.
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS deadline_seconds INTEGER;
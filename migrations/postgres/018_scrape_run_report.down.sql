@@ -0,0 +1,2 @@
+ALTER TABLE scrape_runs DROP COLUMN IF EXISTS error_kind;
+ALTER TABLE scrape_runs DROP COLUMN IF EXISTS articles_updated;
@@ -0,0 +1,3 @@
+-- Updated articles and the class of a failed run's error.
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS articles_updated INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS error_kind VARCHAR(32);
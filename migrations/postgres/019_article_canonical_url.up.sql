@@ -0,0 +1,7 @@
+-- The canonical form of an article's URL, the key articles are deduplicated
+-- on; url keeps the link the publisher gave, which is what gets fetched.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS canonical_url VARCHAR(1024);
+UPDATE articles SET canonical_url = url WHERE canonical_url IS NULL;
+ALTER TABLE articles ALTER COLUMN canonical_url SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_canonical_url ON articles (canonical_url);
@@ -0,0 +1,4 @@
+ALTER TABLE articles_archive DROP COLUMN IF EXISTS sentiment;
+ALTER TABLE articles_archive DROP COLUMN IF EXISTS language;
+ALTER TABLE articles_archive DROP COLUMN IF EXISTS summary_generated;
+ALTER TABLE articles_archive DROP COLUMN IF EXISTS canonical_url;
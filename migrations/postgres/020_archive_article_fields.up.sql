@@ -0,0 +1,5 @@
+-- Archived rows keep the columns added to articles since the archive table.
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS canonical_url VARCHAR(1024) NULL;
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS summary_generated BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS language VARCHAR(8) NULL;
+ALTER TABLE articles_archive ADD COLUMN IF NOT EXISTS sentiment DOUBLE PRECISION NULL;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS sources (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    selector_title TEXT NOT NULL,
+    selector_link TEXT NOT NULL,
+    selector_summary TEXT,
+    default_category TEXT DEFAULT 'general',
+    active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_url UNIQUE (url)
+);
+
+CREATE TABLE IF NOT EXISTS articles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_id INTEGER NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    source_name TEXT NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    summary TEXT,
+    category TEXT DEFAULT 'general',
+    scraped_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_article UNIQUE (url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scraped_at ON articles (scraped_at);
+CREATE INDEX IF NOT EXISTS idx_source_id ON articles (source_id);
+CREATE INDEX IF NOT EXISTS idx_category ON articles (category);
+
+-- Insert sample sources
+INSERT OR IGNORE INTO sources (name, url, selector_title, selector_link, selector_summary) VALUES
+('TechCrunch', 'https://techcrunch.com', '[class*="post"] a[href*="techcrunch"]', '[class*="post"] a[href*="techcrunch"]', 'div.post-block__content'),
+('BBC News', 'https://www.bbc.com/news', 'a[data-testid="internal-link"]', 'a[data-testid="internal-link"]', 'p.gs-c-promo-summary'),
+('The Guardian', 'https://www.theguardian.com/international', 'a[href*="/2025/"]', 'a[href*="/2025/"]', 'div[data-link-name*="article title"] p'),
+('Reuters', 'https://www.reuters.com', 'a[href^="/world/"]' , 'a[href^="/world/"]' , 'p[data-testid="Text"]'),
+('The Indian Express', 'https://indianexpress.com/', 'h3 a, h2 a', 'h3 a, h2 a', 'div.short-story p');
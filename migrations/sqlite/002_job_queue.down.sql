@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS jobs;
+
+ALTER TABLE articles DROP COLUMN fetched_at;
+ALTER TABLE articles DROP COLUMN published_at;
+ALTER TABLE articles DROP COLUMN author;
+ALTER TABLE articles DROP COLUMN image_url;
+ALTER TABLE articles DROP COLUMN body;
+
+ALTER TABLE sources DROP COLUMN selector_body;
@@ -0,0 +1,29 @@
+ALTER TABLE sources ADD COLUMN selector_body TEXT;
+
+ALTER TABLE articles ADD COLUMN body TEXT;
+ALTER TABLE articles ADD COLUMN image_url TEXT;
+ALTER TABLE articles ADD COLUMN author TEXT;
+ALTER TABLE articles ADD COLUMN published_at DATETIME NULL;
+ALTER TABLE articles ADD COLUMN fetched_at DATETIME NULL;
+
+-- Durable work queue for per-article fetches.
+-- Rows are leased by the worker pool, acked to 'done', retried with
+-- backoff, and moved to 'dead' once max_attempts is exhausted.
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    dedupe_key TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    last_error TEXT,
+    lease_token TEXT,
+    leased_until DATETIME NULL,
+    run_after DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    CONSTRAINT unique_job UNIQUE (kind, dedupe_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_run_after ON jobs (status, run_after);
@@ -0,0 +1,22 @@
+-- Expired articles moved aside by the retention job when archive mode is "table".
+-- No unique key on url: the same URL can be archived more than once.
+CREATE TABLE IF NOT EXISTS articles_archive (
+    id INTEGER NOT NULL,
+    source_id INTEGER NOT NULL,
+    source_name TEXT NOT NULL,
+    title TEXT NOT NULL,
+    url TEXT NOT NULL,
+    summary TEXT,
+    category TEXT,
+    body TEXT,
+    image_url TEXT,
+    author TEXT,
+    published_at DATETIME NULL,
+    scraped_at DATETIME NULL,
+    created_at DATETIME NULL,
+    archived_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_archive_id ON articles_archive (id);
+CREATE INDEX IF NOT EXISTS idx_archive_source_id ON articles_archive (source_id);
+CREATE INDEX IF NOT EXISTS idx_archived_at ON articles_archive (archived_at);
@@ -0,0 +1 @@
+ALTER TABLE sources DROP COLUMN keep_query_params;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_story_id;
+ALTER TABLE articles DROP COLUMN story_id;
+ALTER TABLE articles DROP COLUMN fingerprint;
+
+DROP TABLE IF EXISTS stories;
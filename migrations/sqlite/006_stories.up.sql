@@ -0,0 +1,15 @@
+-- Near-duplicate articles from different sources are grouped into stories.
+-- fingerprint is the SimHash of title and summary, stored as a signed 64-bit value.
+-- story_id has no foreign key here: SQLite can't drop a column that has one.
+CREATE TABLE IF NOT EXISTS stories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stories_created_at ON stories (created_at);
+
+ALTER TABLE articles ADD COLUMN fingerprint INTEGER NULL;
+ALTER TABLE articles ADD COLUMN story_id INTEGER NULL;
+
+CREATE INDEX IF NOT EXISTS idx_story_id ON articles (story_id);
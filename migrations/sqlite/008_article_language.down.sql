@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_language;
+ALTER TABLE articles DROP COLUMN language;
@@ -0,0 +1,4 @@
+-- ISO 639-1 code detected from the article text and the page's lang attribute
+ALTER TABLE articles ADD COLUMN language TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_language ON articles (language);
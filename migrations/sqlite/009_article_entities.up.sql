@@ -0,0 +1,12 @@
+-- People, organizations and places mentioned in each article.
+-- normalized_name is the lookup key for /api/entities/:name/articles.
+CREATE TABLE IF NOT EXISTS article_entities (
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    name TEXT NOT NULL,
+    normalized_name TEXT NOT NULL,
+    mentions INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (article_id, type, normalized_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entities_name ON article_entities (normalized_name, article_id);
@@ -0,0 +1,26 @@
+-- Set to skip robots.txt and Crawl-delay for a source, e.g. one that
+-- has allowed us in writing.
+ALTER TABLE sources ADD COLUMN ignore_robots BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- One row per source per scrape, with the URLs robots.txt kept us from fetching.
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_id INTEGER NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    source_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    articles_found INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_scrape_runs_source ON scrape_runs (source_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_scrape_runs_started_at ON scrape_runs (started_at);
+
+CREATE TABLE IF NOT EXISTS scrape_run_blocked (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id INTEGER NOT NULL REFERENCES scrape_runs(id) ON DELETE CASCADE,
+    url TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_scrape_run_blocked_run ON scrape_run_blocked (run_id);
@@ -0,0 +1,11 @@
+-- HTTP validators and body hash of each listing page, so unchanged pages
+-- are answered with 304 Not Modified or at least not parsed again.
+CREATE TABLE IF NOT EXISTS page_cache (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_id INTEGER NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    url TEXT NOT NULL UNIQUE,
+    etag TEXT,
+    last_modified TEXT,
+    content_hash TEXT,
+    checked_at DATETIME NOT NULL
+);
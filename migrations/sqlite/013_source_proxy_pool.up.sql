@@ -0,0 +1,3 @@
+-- Name of the proxy pool (config.yaml proxies.pools) a source is fetched
+-- through; NULL fetches it directly.
+ALTER TABLE sources ADD COLUMN proxy_pool TEXT;
@@ -0,0 +1,4 @@
+ALTER TABLE sources DROP COLUMN cookies;
+ALTER TABLE sources DROP COLUMN headers;
+ALTER TABLE sources DROP COLUMN timeout_seconds;
+ALTER TABLE sources DROP COLUMN user_agent;
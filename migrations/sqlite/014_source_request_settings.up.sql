@@ -0,0 +1,6 @@
+-- Per-source request settings for sites that only show the real page to
+-- a particular user agent, language or consent cookie.
+ALTER TABLE sources ADD COLUMN user_agent TEXT;
+ALTER TABLE sources ADD COLUMN timeout_seconds INTEGER;
+ALTER TABLE sources ADD COLUMN headers TEXT;
+ALTER TABLE sources ADD COLUMN cookies TEXT;
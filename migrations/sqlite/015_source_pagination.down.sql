@@ -0,0 +1,3 @@
+ALTER TABLE sources DROP COLUMN max_pages;
+ALTER TABLE sources DROP COLUMN page_url_template;
+ALTER TABLE sources DROP COLUMN selector_next;
@@ -0,0 +1,4 @@
+-- How the scraper finds a source's further listing pages.
+ALTER TABLE sources ADD COLUMN selector_next TEXT;
+ALTER TABLE sources ADD COLUMN page_url_template TEXT;
+ALTER TABLE sources ADD COLUMN max_pages INTEGER;
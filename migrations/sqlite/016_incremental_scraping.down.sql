@@ -0,0 +1,4 @@
+ALTER TABLE scrape_runs DROP COLUMN articles_known;
+ALTER TABLE scrape_runs DROP COLUMN articles_new;
+
+ALTER TABLE sources DROP COLUMN stop_after_known;
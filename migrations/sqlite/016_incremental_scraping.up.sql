@@ -0,0 +1,6 @@
+-- Incremental scraping: stop after a streak of known articles, and count
+-- new and already-known articles per run.
+ALTER TABLE sources ADD COLUMN stop_after_known INTEGER;
+
+ALTER TABLE scrape_runs ADD COLUMN articles_new INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scrape_runs ADD COLUMN articles_known INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE scrape_runs DROP COLUMN error_kind;
+ALTER TABLE scrape_runs DROP COLUMN articles_updated;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS unique_canonical_url;
+ALTER TABLE articles DROP COLUMN canonical_url;
@@ -0,0 +1,6 @@
+-- The canonical form of an article's URL, the key articles are deduplicated
+-- on; url keeps the link the publisher gave, which is what gets fetched.
+ALTER TABLE articles ADD COLUMN canonical_url TEXT NOT NULL DEFAULT '';
+UPDATE articles SET canonical_url = url;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_canonical_url ON articles (canonical_url);
@@ -0,0 +1,4 @@
+ALTER TABLE articles_archive DROP COLUMN sentiment;
+ALTER TABLE articles_archive DROP COLUMN language;
+ALTER TABLE articles_archive DROP COLUMN summary_generated;
+ALTER TABLE articles_archive DROP COLUMN canonical_url;
@@ -0,0 +1,5 @@
+-- Archived rows keep the columns added to articles since the archive table.
+ALTER TABLE articles_archive ADD COLUMN canonical_url TEXT NULL;
+ALTER TABLE articles_archive ADD COLUMN summary_generated BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE articles_archive ADD COLUMN language TEXT NULL;
+ALTER TABLE articles_archive ADD COLUMN sentiment REAL NULL;
@@ -1,6 +1,11 @@
 package templates
 
-import "strings"
+import (
+    "net/url"
+    "strings"
+
+    "news-scraper/internal/models"
+)
 
 // Source represents a news source
 type Source struct {
@@ -16,6 +21,28 @@ var Sources = []Source{
     {5, "The Indian Express"},
 }
 
+// Language is a language filter on the articles page
+type Language struct {
+    Code string
+    Name string
+}
+
+// Languages are the languages our sources publish in
+var Languages = []Language{
+    {"en", "English"},
+    {"hi", "Hindi"},
+}
+
+// LanguageName returns the display name of a language code
+func LanguageName(code string) string {
+    for _, l := range Languages {
+        if l.Code == code {
+            return l.Name
+        }
+    }
+    return strings.ToUpper(code)
+}
+
 func getCategoryClass(category string) string {
     switch category {
     case "technology":
@@ -60,3 +87,36 @@ func Capitalize(s string) string {
     }
     return strings.ToUpper(s[:1]) + s[1:]
 }
+
+func pluralize(n int, singular, plural string) string {
+    if n == 1 {
+        return singular
+    }
+    return plural
+}
+
+// maxEntityChips is how many entities an article card shows
+const maxEntityChips = 6
+
+// EntityURL is the page listing the articles that mention an entity
+func EntityURL(e models.Entity) string {
+    return "/api/entities/" + url.PathEscape(e.NormalizedName) + "/articles"
+}
+
+// entityChips returns the entities an article card shows, most mentioned first
+func entityChips(entities []models.Entity) []models.Entity {
+    return entities[:min(len(entities), maxEntityChips)]
+}
+
+func getEntityChipClass(entityType string) string {
+    switch entityType {
+    case models.EntityPerson:
+        return "px-2 py-1 rounded-full text-xs font-medium bg-indigo-50 text-indigo-700 hover:bg-indigo-100"
+    case models.EntityOrganization:
+        return "px-2 py-1 rounded-full text-xs font-medium bg-amber-50 text-amber-700 hover:bg-amber-100"
+    case models.EntityPlace:
+        return "px-2 py-1 rounded-full text-xs font-medium bg-teal-50 text-teal-700 hover:bg-teal-100"
+    default:
+        return "px-2 py-1 rounded-full text-xs font-medium bg-gray-100 text-gray-700 hover:bg-gray-200"
+    }
+}